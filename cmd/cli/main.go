@@ -2,46 +2,81 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"log"
+	"log/slog"
 	"mars/internal/app"
 	"mars/internal/config"
+	"mars/internal/generator"
+	"mars/internal/lint"
 	"mars/internal/parser"
 	"mars/internal/rover"
 	"mars/internal/webapi"
 	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
-	// parse cmd line flags
-	cfg, err := config.ParseFlags(os.Args[:1])
+	// parse the command and its flags, merged over the config file and the environment
+	cfg, err := config.ParseArgs(os.Args[1:])
 	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(app.ExitOK)
+		}
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(app.ExitUsage)
 	}
 
+	// stop the mission or the server on SIGINT or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
 	// dispatch to the right runner function
-	switch cfg.OpMode {
-	case config.ModeCLI:
-		if err := runCLI(cfg); err != nil {
-			log.Fatalf("FATAL: CLI mode failed: %v", err)
-		}
+	err = runCommand(ctx, cfg)
+	stop()
 
-	case config.ModeWebAPI:
-		if err := runWebAPI(cfg); err != nil {
-			log.Fatalf("FATAL: Web API mode failed: %v", err)
+	if err != nil {
+		// report parse problems compiler-style so editors can jump to them
+		var diags parser.Diagnostics
+		switch {
+		case errors.As(err, &diags):
+			printDiagnostics(os.Stderr, inputName(cfg), diags)
+		case errors.Is(err, app.ErrAppInvalid):
+			// the report already lists the problems
+		default:
+			log.Printf("FATAL: %s failed: %v", cfg.Command, err)
 		}
+	}
+	os.Exit(app.ExitCode(err))
+}
+
+func runCommand(ctx context.Context, cfg *config.Config) error {
+	switch cfg.Command {
+	case config.CommandRun, config.CommandRender:
+		return runCLI(ctx, cfg)
+
+	case config.CommandValidate:
+		return runValidate(ctx, cfg)
+
+	case config.CommandServe:
+		return runWebAPI(ctx, cfg)
+
+	case config.CommandGenerate:
+		return generator.Write(os.Stdout, cfg.Generate)
 
 	default:
-		log.Fatalf("FATAL: Unknown operating mode configured.")
+		return fmt.Errorf("unknown command %q", cfg.Command)
 	}
 }
 
-func runCLI(cfg *config.Config) error {
+func runCLI(ctx context.Context, cfg *config.Config) error {
 	inputReader, cleanup, err := getInputReader(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to get input: %w", err)
+		return fmt.Errorf("%w: %w", app.ErrAppInput, err)
 	}
 	defer cleanup()
 
@@ -49,19 +84,67 @@ func runCLI(cfg *config.Config) error {
 	bufferedReader := bufio.NewReader(inputReader)
 
 	p := parser.New()
-	mcf := rover.NewMissionControlFactory()
+
+	var opts []rover.Option
+	if cfg.Command == config.CommandRun {
+		// surface ignored moves and rovers that did not finish operational on stderr
+		opts = append(opts, rover.WithEventSink(rover.NewSlogSink(slog.Default())))
+	}
+	mcf := rover.NewMissionControlFactory(opts...)
 
 	app := app.NewApp(p, mcf, bufferedReader, os.Stdout, cfg)
-	return app.Run()
+	return app.Run(ctx)
+}
+
+func runValidate(ctx context.Context, cfg *config.Config) error {
+	inputReader, cleanup, err := getInputReader(cfg)
+	if err != nil {
+		return fmt.Errorf("%w: %w", app.ErrAppInput, err)
+	}
+	defer cleanup()
+
+	application := app.NewApp(parser.New(), rover.NewMissionControlFactory(), bufio.NewReader(inputReader), os.Stdout, cfg)
+	report, err := application.Validate(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := report.WriteText(os.Stdout, inputName(cfg)); err != nil {
+		return err
+	}
+
+	if !report.Valid() {
+		return fmt.Errorf("%w: %d errors", app.ErrAppInvalid, report.Count(lint.SeverityError))
+	}
+	return nil
 }
 
-func runWebAPI(cfg *config.Config) error {
+func runWebAPI(ctx context.Context, cfg *config.Config) error {
 
 	p := parser.New()
 	mcf := rover.NewMissionControlFactory()
-	server := webapi.NewServer(cfg, p, mcf)
+	server := webapi.NewServer(cfg, p, parser.NewJSON(), mcf)
 
-	return server.Start()
+	// once ctx is done, in-flight missions are let complete
+	return server.Start(ctx)
+}
+
+// printDiagnostics writes one "file:line:col: message" line per diagnostic, followed by its hint if any
+func printDiagnostics(w io.Writer, name string, diags parser.Diagnostics) {
+	for _, d := range diags {
+		fmt.Fprintf(w, "%s:%d:%d: %v\n", name, d.Line, d.Column, d.Err)
+		if d.Hint != "" {
+			fmt.Fprintf(w, "\thint: %s\n", d.Hint)
+		}
+	}
+}
+
+// inputName returns the name of the input used in diagnostics
+func inputName(cfg *config.Config) string {
+	if cfg.FilePath != "" {
+		return cfg.FilePath
+	}
+	return "<stdin>"
 }
 
 func getInputReader(cfg *config.Config) (io.Reader, func(), error) {
@@ -86,7 +169,7 @@ func getInputReader(cfg *config.Config) (io.Reader, func(), error) {
 	}
 
 	if (stat.Mode() & os.ModeCharDevice) != 0 {
-		fmt.Fprintln(os.Stderr, "Usage: program -file <path> OR echo 'data' | program")
+		fmt.Fprintf(os.Stderr, "Usage: mars %[1]s -file <path> OR echo 'data' | mars %[1]s\n", cfg.Command)
 		fmt.Fprintln(os.Stderr, "\nNo input provided. Use -file flag or pipe data to stdin.")
 		return nil, noOpCleanup, fmt.Errorf("no input source provided")
 	}
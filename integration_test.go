@@ -1,6 +1,7 @@
 package main_test
 
 import (
+	"context"
 	"testing"
 
 	"mars/internal/config"
@@ -35,7 +36,7 @@ MMRMMRMRRM`
 	missionInput := &rover.MissionControlInput{
 		Instructions: instructions,
 	}
-	outputs, err := mc.Execute(missionInput)
+	outputs, err := mc.Execute(context.Background(), missionInput)
 	require.NoError(t, err)
 
 	// Verify expected outputs
@@ -69,7 +70,7 @@ MM`
 	missionInput := &rover.MissionControlInput{
 		Instructions: instructions,
 	}
-	outputs, err := mc.Execute(missionInput)
+	outputs, err := mc.Execute(context.Background(), missionInput)
 	require.NoError(t, err)
 
 	// Rover 1: (3,3) N, turns left to face W, stays at (3,3) W
@@ -101,7 +102,7 @@ MMM`
 	missionInput := &rover.MissionControlInput{
 		Instructions: instructions,
 	}
-	outputs, err := mc.Execute(missionInput)
+	outputs, err := mc.Execute(context.Background(), missionInput)
 	require.NoError(t, err)
 
 	// Rover 1: (5,5) N → moves MM → ends at (5,7) N
@@ -132,7 +133,7 @@ MMM`
 	missionInput := &rover.MissionControlInput{
 		Instructions: instructions,
 	}
-	outputs, err := mc.Execute(missionInput)
+	outputs, err := mc.Execute(context.Background(), missionInput)
 	require.NoError(t, err)
 
 	// Rover 1: (0,0) S, can't go south (already at boundary)
@@ -161,7 +162,7 @@ MMMRMMMLMM`
 	missionInput := &rover.MissionControlInput{
 		Instructions: instructions,
 	}
-	outputs, err := mc.Execute(missionInput)
+	outputs, err := mc.Execute(context.Background(), missionInput)
 	require.NoError(t, err)
 
 	// Trace:
@@ -173,3 +174,36 @@ MMMRMMMLMM`
 	// MM:    (5,7) N    [2 north]
 	assert.Equal(t, "5 7 N", outputs[0])
 }
+
+func TestRoverObstacles(t *testing.T) {
+	// Rover 1 drives north into a rock and stops in front of it
+	// Rover 2 is deployed straight onto the exclusion zone and is rejected
+	input := `5 5
+ROCK 1 4
+ZONE 3 3 4 4
+1 1 N
+MMMM
+3 4 N
+M`
+
+	cfg := config.Default()
+
+	p := parser.New()
+	plateau, instructions, err := p.Parse(input, cfg)
+	require.NoError(t, err)
+
+	factory := rover.NewMissionControlFactory()
+	mc, err := factory.Create(plateau)
+	require.NoError(t, err)
+
+	result, err := mc.Simulate(context.Background(), &rover.MissionControlInput{Instructions: instructions[:1]})
+	require.NoError(t, err)
+
+	// Rover 1: (1,1) N → (1,3), tries (1,4) ✗ BLOCKED by the rock
+	assert.Equal(t, "1 3 N", result.Rovers[0].String())
+	assert.ErrorIs(t, result.Rovers[0].IgnoredMoves[0].Reason, rover.ErrObstacleCollision)
+
+	// Rover 2 cannot be placed inside the zone
+	_, err = mc.Execute(context.Background(), &rover.MissionControlInput{Instructions: instructions[1:]})
+	require.ErrorIs(t, err, rover.ErrObstacleCollision)
+}
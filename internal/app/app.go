@@ -1,9 +1,11 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"mars/internal/config"
+	"mars/internal/render"
 	"mars/internal/rover"
 )
 
@@ -30,34 +32,97 @@ func NewApp(p Parser, mcf rover.MissionControlFactory, i io.Reader, o io.Writer,
 	}
 }
 
-// Run starts the application
-func (a *App) Run() error {
+// Run starts the application, writing the mission result in the configured output format. Batch inputs are handed over to RunBatch.
+// The mission stops once ctx is done
+func (a *App) Run(ctx context.Context) error {
+	if a.cfg.Batch {
+		return a.RunBatch(ctx)
+	}
+
+	renderer, err := Renderer(a.cfg)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrAppOutput, err)
+	}
+
+	result, err := a.Execute(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := renderer.Render(a.output, result); err != nil {
+		return fmt.Errorf("%w: %w", ErrAppOutput, err)
+	}
+	return nil
+}
+
+// Execute reads and parses the input and runs the mission, returning the detailed result without writing any output. The mission stops once ctx is done
+func (a *App) Execute(ctx context.Context) (*rover.MissionResult, error) {
 	inputBytes, err := io.ReadAll(a.input)
 	if err != nil {
-		return fmt.Errorf("%w: %w", ErrAppInput, err)
+		return nil, fmt.Errorf("%w: %w", ErrAppInput, err)
+	}
+
+	return a.execute(ctx, string(inputBytes))
+}
+
+// execute parses and runs a single mission
+func (a *App) execute(ctx context.Context, input string) (*rover.MissionResult, error) {
+	plateau, instructions, err := a.parser.Parse(input, a.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAppParsing, err)
 	}
 
-	plateau, instructions, err := a.parser.Parse(string(inputBytes), a.cfg)
+	opts, err := MissionOptions(a.cfg)
 	if err != nil {
-		return fmt.Errorf("%w: %s", ErrAppParsing, err)
+		return nil, fmt.Errorf("%w: %w", ErrAppCreatingMC, err)
 	}
 
-	mc, err := a.mcf.Create(plateau)
+	mc, err := a.mcf.Create(plateau, opts...)
 	if err != nil {
-		return fmt.Errorf("%w: %w", ErrAppCreatingMC, err)
+		return nil, fmt.Errorf("%w: %w", ErrAppCreatingMC, err)
 	}
 
 	missionControlInput := &rover.MissionControlInput{
 		Instructions: instructions,
 	}
 
-	output, err := mc.Execute(missionControlInput)
+	result, err := mc.Simulate(ctx, missionControlInput)
 	if err != nil {
-		return fmt.Errorf("%w: %w", ErrAppExecMission, err)
+		return nil, fmt.Errorf("%w: %w", ErrAppExecMission, err)
 	}
 
-	for _, singleRoverOutput := range output {
-		fmt.Fprintln(a.output, singleRoverOutput)
+	return result, nil
+}
+
+// Renderer returns the renderer of the configured output format, images drawn with the configured cell size and frame delay
+func Renderer(cfg *config.Config) (render.Renderer, error) {
+	return render.New(cfg.OutputFormat, render.WithCellSize(cfg.Image.CellSize), render.WithFrameDelay(cfg.Image.FrameDelay))
+}
+
+// MissionOptions translates the simulation policies in the config into MissionControl options
+func MissionOptions(cfg *config.Config) ([]rover.Option, error) {
+	boundary, err := rover.NewBoundaryPolicy(cfg.BoundaryPolicy)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+
+	collision, err := rover.NewCollisionPolicy(cfg.CollisionPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []rover.Option{
+		rover.WithBoundaryPolicy(boundary),
+		rover.WithCollisionPolicy(collision),
+	}
+
+	if cfg.NeedsTrace() {
+		opts = append(opts, rover.WithTrace())
+	}
+
+	if cfg.ExecMode == config.ExecLockstep {
+		opts = append(opts, rover.WithLockstep())
+	}
+
+	return opts, nil
 }
@@ -2,10 +2,12 @@ package app
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 
 	"mars/internal/config"
+	"mars/internal/render"
 	"mars/internal/rover"
 	"strings"
 	"testing"
@@ -34,7 +36,7 @@ type MockMissionControlFactory struct {
 	mock.Mock
 }
 
-func (m *MockMissionControlFactory) Create(plateau *rover.Plateau) (*rover.MissionControl, error) {
+func (m *MockMissionControlFactory) Create(plateau *rover.Plateau, opts ...rover.Option) (*rover.MissionControl, error) {
 	args := m.Called(plateau)
 
 	if args.Get(0) == nil {
@@ -58,6 +60,7 @@ func TestApp_Run(t *testing.T) {
 	testCases := map[string]struct {
 		inputData   string
 		inputReader io.Reader
+		cfg         *config.Config
 		setupMocks  func(*MockParser, *MockMissionControlFactory)
 		wantOutput  string
 		wantErr     error
@@ -102,6 +105,62 @@ func TestApp_Run(t *testing.T) {
 			wantOutput: "1 3 N\n",
 			wantErr:    nil,
 		},
+		"ok - csv output": {
+			inputData: "5 5\n1 2 N\nLMLMLMLMM",
+			cfg: func() *config.Config {
+				cfg := config.Default()
+				cfg.OutputFormat = render.FormatCSV
+				return cfg
+			}(),
+
+			setupMocks: func(mp *MockParser, mmcf *MockMissionControlFactory) {
+				plateau, _ := rover.NewPlateau(5, 5, cfg.MinPlateauX, cfg.MinPlateauY)
+				pos1, _ := rover.NewPosition(plateau, rover.NewCoordinates(1, 2), rover.N)
+
+				instructions := []rover.RoverInstruction{
+					{InitialPosition: pos1, Commands: "LMLMLMLMM"},
+				}
+
+				mp.On("Parse", "5 5\n1 2 N\nLMLMLMLMM").Return(plateau, instructions, nil)
+
+				mc, _ := rover.NewMissionControl(plateau)
+				mmcf.On("Create", plateau).Return(mc, nil)
+			},
+			wantOutput: "id,name,start_x,start_y,start_heading,x,y,heading,status,blocked_moves,error\n1,,1,2,N,1,3,N,operational,0,\n",
+			wantErr:    nil,
+		},
+		"err - unknown output format": {
+			inputData: "5 5\n1 2 N\nLMLMLMLMM",
+			cfg: func() *config.Config {
+				cfg := config.Default()
+				cfg.OutputFormat = "yaml"
+				return cfg
+			}(),
+			setupMocks: func(mp *MockParser, mmcf *MockMissionControlFactory) {},
+			wantErr:    ErrAppOutput,
+		},
+		"ok - trace printed before final positions": {
+			inputData: "5 5\n1 2 N\nLM",
+			cfg: func() *config.Config {
+				cfg := config.Default()
+				cfg.Trace = true
+				return cfg
+			}(),
+
+			setupMocks: func(mp *MockParser, mmcf *MockMissionControlFactory) {
+				plateau, _ := rover.NewPlateau(5, 5, cfg.MinPlateauX, cfg.MinPlateauY)
+				pos1, _ := rover.NewPosition(plateau, rover.NewCoordinates(1, 2), rover.N)
+
+				instructions := []rover.RoverInstruction{
+					{InitialPosition: pos1, Commands: "LM"},
+				}
+
+				mp.On("Parse", "5 5\n1 2 N\nLM").Return(plateau, instructions, nil)
+				mmcf.On("Create", plateau).Return(rover.NewMissionControl(plateau, rover.WithTrace()))
+			},
+			wantOutput: "rover 1 step 1 L: 1 2 N -> 1 2 W turned\nrover 1 step 2 M: 1 2 W -> 0 2 W moved\n0 2 W\n",
+			wantErr:    nil,
+		},
 		"err - reading input fails": {
 			inputReader: errReader{},
 
@@ -133,6 +192,20 @@ func TestApp_Run(t *testing.T) {
 			},
 			wantErr: ErrAppCreatingMC,
 		},
+		"err - unknown boundary policy": {
+			inputData: "5 5\n1 2 N\nLMLMLMLMM",
+			cfg: func() *config.Config {
+				cfg := config.Default()
+				cfg.BoundaryPolicy = "bounce"
+				return cfg
+			}(),
+
+			setupMocks: func(mp *MockParser, mmcf *MockMissionControlFactory) {
+				plateau, _ := rover.NewPlateau(5, 5, cfg.MinPlateauX, cfg.MinPlateauY)
+				mp.On("Parse", mock.Anything).Return(plateau, []rover.RoverInstruction{}, nil)
+			},
+			wantErr: ErrAppCreatingMC,
+		},
 		"err - execution fails with collision": {
 			inputData: "5 5\n1 2 N\nLMLMLMLMM\n1 3 N\nM",
 
@@ -172,9 +245,14 @@ func TestApp_Run(t *testing.T) {
 			}
 			output := &bytes.Buffer{}
 
+			appCfg := cfg
+			if tc.cfg != nil {
+				appCfg = tc.cfg
+			}
+
 			// Create app and run
-			app := NewApp(mockParser, mockMCFactory, input, output, cfg)
-			err := app.Run()
+			app := NewApp(mockParser, mockMCFactory, input, output, appCfg)
+			err := app.Run(context.Background())
 
 			if tc.wantErr != nil {
 				require.ErrorIs(t, err, tc.wantErr)
@@ -0,0 +1,139 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mars/internal/parser"
+	"mars/internal/render"
+	"mars/internal/rover"
+	"mars/internal/runner"
+	"strings"
+)
+
+// batchSeparator starts a new mission in a batch input, the rest of the line is the name of the mission
+const batchSeparator = "---"
+
+// batchInput is the text of one mission of a batch input
+type batchInput struct {
+	name string
+	line int // line of the batch input the mission starts on
+	text string
+}
+
+// splitBatch splits a batch input into its missions. Every separator line starts a new mission, lines before the first separator form a mission of their own unless they are blank.
+// Missions holding nothing but blank lines and comments are dropped
+func splitBatch(input string) []batchInput {
+	var missions []batchInput
+
+	current := batchInput{line: 1}
+	var lines []string
+
+	flush := func() {
+		if !isBlank(lines) {
+			current.text = strings.Join(lines, "\n")
+			missions = append(missions, current)
+		}
+		lines = nil
+	}
+
+	for i, l := range strings.Split(input, "\n") {
+		trimmed := strings.TrimSpace(l)
+		if !strings.HasPrefix(trimmed, batchSeparator) {
+			lines = append(lines, l)
+			continue
+		}
+
+		flush()
+
+		name, _, _ := strings.Cut(strings.TrimPrefix(trimmed, batchSeparator), "#")
+		current = batchInput{name: strings.TrimSpace(name), line: i + 2}
+	}
+	flush()
+
+	return missions
+}
+
+// isBlank reports whether the lines hold nothing but whitespace and comments
+func isBlank(lines []string) bool {
+	for _, l := range lines {
+		text, _, _ := strings.Cut(l, "#")
+		if strings.TrimSpace(text) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// batchError moves the lines of the parse diagnostics of a mission starting on the given line of a batch input so they point into the batch input
+func batchError(err error, line int) error {
+	var diags parser.Diagnostics
+	if !errors.As(err, &diags) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrAppParsing, diags.Offset(line-1))
+}
+
+// RunBatch reads a batch input, runs every mission independently of the others and writes their combined report in the configured output format.
+// A mission that fails does not stop the others, their number is reported once every mission ran
+func (a *App) RunBatch(ctx context.Context) error {
+	renderer, err := Renderer(a.cfg)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrAppOutput, err)
+	}
+
+	inputBytes, err := io.ReadAll(a.input)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrAppInput, err)
+	}
+
+	missions := a.ExecuteBatch(ctx, string(inputBytes))
+	if len(missions) == 0 {
+		return fmt.Errorf("%w: %w", ErrAppParsing, ErrAppBatchEmpty)
+	}
+
+	if err := renderer.RenderBatch(a.output, missions); err != nil {
+		return fmt.Errorf("%w: %w", ErrAppOutput, err)
+	}
+
+	var failed int
+	for _, m := range missions {
+		if !m.OK() {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d", ErrAppBatchFailed, failed, len(missions))
+	}
+	return nil
+}
+
+// ExecuteBatch splits a batch input into its missions and runs them on a pool of cfg.Parallel workers, each one within cfg.MissionTimeout if set.
+// The outcomes are returned in input order, missions not yet started when ctx is done are reported as cancelled
+func (a *App) ExecuteBatch(ctx context.Context, input string) []render.BatchMission {
+	inputs := splitBatch(input)
+
+	jobs := make([]runner.Job, 0, len(inputs))
+	for _, in := range inputs {
+		jobs = append(jobs, func(ctx context.Context) (*rover.MissionResult, error) {
+			result, err := a.execute(ctx, in.text)
+			return result, batchError(err, in.line)
+		})
+	}
+
+	results := runner.New(a.cfg.Parallel, runner.WithTimeout(a.cfg.MissionTimeout)).Run(ctx, jobs)
+
+	missions := make([]render.BatchMission, 0, len(inputs))
+	for i, in := range inputs {
+		missions = append(missions, render.BatchMission{
+			ID:     i + 1,
+			Name:   in.name,
+			Line:   in.line,
+			Result: results[i].Mission,
+			Err:    results[i].Err,
+		})
+	}
+	return missions
+}
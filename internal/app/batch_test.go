@@ -0,0 +1,202 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mars/internal/config"
+	"mars/internal/parser"
+	"mars/internal/render"
+	"mars/internal/rover"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitBatch(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		input string
+		want  []batchInput
+	}{
+		"ok - single mission without separator": {
+			input: "5 5\n1 2 N\nM",
+			want:  []batchInput{{line: 1, text: "5 5\n1 2 N\nM"}},
+		},
+		"ok - named missions": {
+			input: "--- day 1 # survey\n5 5\n1 2 N\n---day-2\n3 3\n0 0 E\nM\n",
+			want: []batchInput{
+				{name: "day 1", line: 2, text: "5 5\n1 2 N"},
+				{name: "day-2", line: 5, text: "3 3\n0 0 E\nM\n"},
+			},
+		},
+		"ok - lines before the first separator": {
+			input: "5 5\n1 2 N\n---\n3 3\n0 0 E",
+			want: []batchInput{
+				{line: 1, text: "5 5\n1 2 N"},
+				{line: 4, text: "3 3\n0 0 E"},
+			},
+		},
+		"ok - blank missions dropped": {
+			input: "\n# header\n---\n\n---\n5 5\n1 2 N\n---\n",
+			want:  []batchInput{{line: 6, text: "5 5\n1 2 N"}},
+		},
+		"ok - empty input": {
+			input: "",
+			want:  nil,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, splitBatch(tc.input))
+		})
+	}
+}
+
+func TestApp_RunBatch(t *testing.T) {
+	t.Parallel()
+
+	input := "--- first\n5 5\n1 2 N\nLMLMLMLMM\n--- broken\n5 5\n1 2 Q\n---\n5 5\n3 3 E\nMMRMMRMRRM\n"
+
+	testCases := map[string]struct {
+		input      string
+		format     string
+		parallel   int
+		wantOutput string
+		wantErr    error
+	}{
+		"ok - text report": {
+			input:  "5 5\n1 2 N\nLMLMLMLMM\n---\n5 5\n3 3 E\nMMRMMRMRRM",
+			format: render.FormatText,
+			wantOutput: "--- mission 1 (line 1): ok\n1 3 N\n" +
+				"--- mission 2 (line 5): ok\n5 1 E\n",
+		},
+		"err - broken mission does not stop the others": {
+			input:  input,
+			format: render.FormatText,
+			wantOutput: "--- mission 1 \"first\" (line 2): ok\n1 3 N\n" +
+				"--- mission 2 \"broken\" (line 6): failed: error parsing input: 7:5: invalid direction given, must be N, E, S, W: given Q\n" +
+				"--- mission 3 (line 9): ok\n5 1 E\n",
+			wantErr: ErrAppBatchFailed,
+		},
+		"err - missions run in parallel keep their order": {
+			input:    input,
+			format:   render.FormatCSV,
+			parallel: 3,
+			wantOutput: "mission,mission_name,mission_ok,mission_error,id,name,start_x,start_y,start_heading,x,y,heading,status,blocked_moves,error\n" +
+				"1,first,true,,1,,1,2,N,1,3,N,operational,0,\n" +
+				"2,broken,false,\"error parsing input: 7:5: invalid direction given, must be N, E, S, W: given Q\",,,,,,,,,,,\n" +
+				"3,,true,,1,,3,3,E,5,1,E,operational,0,\n",
+			wantErr: ErrAppBatchFailed,
+		},
+		"err - table report": {
+			input:  input,
+			format: render.FormatTable,
+			wantOutput: "MISSION  ID  NAME  START  FINAL  STATUS       BLOCKED\n" +
+				"first    1   -     1 2 N  1 3 N  operational  0\n" +
+				"broken   -   -     -      -      failed       -\n" +
+				"3        1   -     3 3 E  5 1 E  operational  0\n" +
+				"mission broken (line 6): error parsing input: 7:5: invalid direction given, must be N, E, S, W: given Q\n",
+			wantErr: ErrAppBatchFailed,
+		},
+		"ok - json report": {
+			input:      "--- only\n5 5\n1 2 N\nM",
+			format:     render.FormatJSON,
+			wantOutput: `{"missions":[{"id":1,"name":"only","line":2,"ok":true,"result":{"rovers":[{"id":1,"x":1,"y":3,"heading":"N","status":"operational","start":{"x":1,"y":2,"heading":"N"},"ignoredMoves":[]}]}}],"succeeded":1,"failed":0}` + "\n",
+		},
+		"err - no mission": {
+			input:   "# nothing here\n---\n",
+			format:  render.FormatText,
+			wantErr: ErrAppBatchEmpty,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Batch = true
+			cfg.OutputFormat = tc.format
+			if tc.parallel > 0 {
+				cfg.Parallel = tc.parallel
+			}
+
+			output := &bytes.Buffer{}
+			app := NewApp(parser.New(), rover.NewMissionControlFactory(), strings.NewReader(tc.input), output, cfg)
+
+			err := app.Run(context.Background())
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tc.wantOutput, output.String())
+		})
+	}
+}
+
+func TestApp_ExecuteBatch_ParseErrorLines(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.Default()
+	cfg.AllErrors = true
+	app := NewApp(parser.New(), rover.NewMissionControlFactory(), nil, nil, cfg)
+
+	// diagnostics point at the lines of the batch input, not of the mission
+	missions := app.ExecuteBatch(context.Background(), "--- first\n5 5\n1 2 N\nM\n\n--- second\n5 5\n1 2 N\nMXM\nx 1 N\n")
+	require.Len(t, missions, 2)
+	require.NoError(t, missions[0].Err)
+	require.ErrorIs(t, missions[1].Err, ErrAppParsing)
+
+	var diags parser.Diagnostics
+	require.ErrorAs(t, missions[1].Err, &diags)
+	require.Len(t, diags, 2)
+	assert.Equal(t, [2]int{9, 2}, [2]int{diags[0].Line, diags[0].Column})
+	assert.Equal(t, [2]int{10, 1}, [2]int{diags[1].Line, diags[1].Column})
+}
+
+// BenchmarkApp_ExecuteBatch runs a corpus of 10k generated missions with a growing number of workers, reporting the missions run per second
+func BenchmarkApp_ExecuteBatch(b *testing.B) {
+	corpus := batchCorpus(10_000)
+
+	for _, workers := range []int{1, 2, 4, 8, 16} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			cfg := config.Default()
+			cfg.Parallel = workers
+			app := NewApp(parser.New(), rover.NewMissionControlFactory(), nil, nil, cfg)
+
+			for b.Loop() {
+				app.ExecuteBatch(context.Background(), corpus)
+			}
+
+			b.ReportMetric(float64(10_000*b.N)/b.Elapsed().Seconds(), "missions/s")
+		})
+	}
+}
+
+// batchCorpus generates a batch of missions, each one with a few rovers on its own plateau. Like in a real regression corpus some of them fail, a rover being deployed where another one stands
+func batchCorpus(missions int) string {
+	rng := rand.New(rand.NewPCG(1, 2))
+	headings := []string{"N", "E", "S", "W"}
+
+	var sb strings.Builder
+	for i := range missions {
+		x, y := 5+rng.IntN(20), 5+rng.IntN(20)
+		fmt.Fprintf(&sb, "--- mission-%d\n%d %d\n", i, x, y)
+
+		for range 4 {
+			fmt.Fprintf(&sb, "%d %d %s\n", rng.IntN(x+1), rng.IntN(y+1), headings[rng.IntN(len(headings))])
+
+			for range 50 {
+				sb.WriteByte("LRMMM"[rng.IntN(5)])
+			}
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
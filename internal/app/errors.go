@@ -7,4 +7,8 @@ var (
 	ErrAppParsing     = errors.New("error parsing input")
 	ErrAppCreatingMC  = errors.New("error creating mission control")
 	ErrAppExecMission = errors.New("error executing mission")
+	ErrAppOutput      = errors.New("error writing output")
+	ErrAppBatchEmpty  = errors.New("batch input holds no mission")
+	ErrAppBatchFailed = errors.New("missions of the batch failed")
+	ErrAppInvalid     = errors.New("mission is not valid")
 )
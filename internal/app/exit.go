@@ -0,0 +1,32 @@
+package app
+
+import (
+	"errors"
+	"mars/internal/rover"
+)
+
+// process exit codes of the command line, telling apart what went wrong
+const (
+	ExitOK      = 0
+	ExitFailure = 1 // anything else, e.g. writing the output or running the web server
+	ExitUsage   = 2 // unknown command, invalid flags or configuration
+	ExitInput   = 3 // the input could not be read
+	ExitParse   = 4 // the input is not a valid mission, or its validation found errors
+	ExitMission = 5 // the mission failed or was cancelled, or some missions of a batch failed
+)
+
+// ExitCode returns the process exit code reporting err, an error returned by the App
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, ErrAppInput):
+		return ExitInput
+	case errors.Is(err, ErrAppParsing), errors.Is(err, ErrAppInvalid):
+		return ExitParse
+	case errors.Is(err, ErrAppCreatingMC), errors.Is(err, ErrAppExecMission), errors.Is(err, ErrAppBatchFailed), errors.Is(err, rover.ErrMissionCancelled):
+		return ExitMission
+	default:
+		return ExitFailure
+	}
+}
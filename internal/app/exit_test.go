@@ -0,0 +1,35 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"mars/internal/rover"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		err  error
+		want int
+	}{
+		"ok - no error":               {err: nil, want: ExitOK},
+		"err - input not read":        {err: fmt.Errorf("%w: %w", ErrAppInput, errors.New("read error")), want: ExitInput},
+		"err - input not parsed":      {err: fmt.Errorf("%w: %w", ErrAppParsing, errors.New("invalid direction")), want: ExitParse},
+		"err - validation errors":     {err: fmt.Errorf("%w: 2 errors", ErrAppInvalid), want: ExitParse},
+		"err - empty batch":           {err: fmt.Errorf("%w: %w", ErrAppParsing, ErrAppBatchEmpty), want: ExitParse},
+		"err - mission failed":        {err: fmt.Errorf("%w: %w", ErrAppExecMission, errors.New("blocked")), want: ExitMission},
+		"err - mission cancelled":     {err: fmt.Errorf("%w: %w", ErrAppExecMission, rover.ErrMissionCancelled), want: ExitMission},
+		"err - batch missions failed": {err: fmt.Errorf("%w: 1 of 3", ErrAppBatchFailed), want: ExitMission},
+		"err - output not written":    {err: fmt.Errorf("%w: %w", ErrAppOutput, errors.New("broken pipe")), want: ExitFailure},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, ExitCode(tc.err))
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mars/internal/config"
+	"mars/internal/lint"
+	"mars/internal/rover"
+)
+
+// Validate reads and parses the input, then simulates the mission with every command traced without writing any result. The report lists every problem found.
+// Only an input that cannot be read, or a simulation stopped because ctx is done, returns an error
+func (a *App) Validate(ctx context.Context) (*lint.Report, error) {
+	inputBytes, err := io.ReadAll(a.input)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAppInput, err)
+	}
+
+	return a.validate(ctx, string(inputBytes))
+}
+
+// validate lints a single mission. A mission that cannot be parsed is not simulated, its report only holds the parse errors
+func (a *App) validate(ctx context.Context, input string) (*lint.Report, error) {
+	report := &lint.Report{}
+
+	plateau, instructions, err := a.parser.Parse(input, a.cfg)
+	if err != nil {
+		report.Add(lint.ParseError(err)...)
+		return report, nil
+	}
+
+	opts, err := MissionOptions(a.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAppCreatingMC, err)
+	}
+
+	mc, err := a.mcf.Create(plateau, append(opts, rover.WithTrace(), rover.WithAttemptNumbering())...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAppCreatingMC, err)
+	}
+
+	if a.cfg.ExecMode == config.ExecLockstep {
+		err = validateLockstep(ctx, mc, instructions, report)
+	} else {
+		err = validateSequential(ctx, mc, instructions, report)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAppExecMission, err)
+	}
+
+	return report, nil
+}
+
+// validateSequential deploys the rovers one after another, so a rover that cannot be deployed does not hide the problems of the next ones.
+// Rovers keep the number of their instruction whether the ones before them could be deployed or not
+func validateSequential(ctx context.Context, mc *rover.MissionControl, instructions []rover.RoverInstruction, report *lint.Report) error {
+	// first rover starting on every cell
+	starts := map[[2]int]string{}
+
+	for i, instruction := range instructions {
+		label := rover.RoverResult{ID: i + 1, Name: instruction.Name}.Label()
+		findings := lint.Commands(label, instruction.Commands)
+		cell := [2]int{instruction.InitialPosition.X(), instruction.InitialPosition.Y()}
+
+		result, err := mc.Deploy(ctx, instruction)
+		switch {
+		case errors.Is(err, rover.ErrMissionCancelled):
+			return err
+
+		case err != nil && result.ID == 0:
+			findings = append(findings, lint.Finding{
+				Severity: lint.SeverityError,
+				Rover:    label,
+				Message:  fmt.Sprintf("rover cannot be deployed at %s: %s", instruction.InitialPosition.String(), deployProblem(err)),
+			})
+
+		default:
+			if first, ok := starts[cell]; ok {
+				findings = append(findings, lint.Finding{
+					Severity: lint.SeverityWarning,
+					Rover:    label,
+					Message:  fmt.Sprintf("rover starts on the cell rover %s started on, it can only be deployed because rover %s moved away first", first, first),
+				})
+			}
+			findings = append(findings, lint.Result(result)...)
+		}
+
+		if _, ok := starts[cell]; !ok {
+			starts[cell] = label
+		}
+
+		lint.SortByStep(findings)
+		report.Add(findings...)
+	}
+	return nil
+}
+
+// validateLockstep simulates the whole mission, all the rovers being deployed before the first tick. A mission failure is reported as a single error
+func validateLockstep(ctx context.Context, mc *rover.MissionControl, instructions []rover.RoverInstruction, report *lint.Report) error {
+	result, err := mc.Simulate(ctx, &rover.MissionControlInput{Instructions: instructions})
+	if errors.Is(err, rover.ErrMissionCancelled) {
+		return err
+	}
+	if err != nil {
+		report.Add(lint.Finding{Severity: lint.SeverityError, Message: fmt.Sprintf("mission fails: %v", err)})
+	}
+
+	for i, instruction := range instructions {
+		label := rover.RoverResult{ID: i + 1, Name: instruction.Name}.Label()
+		findings := lint.Commands(label, instruction.Commands)
+
+		if result != nil {
+			findings = append(findings, lint.Result(result.Rovers[i])...)
+		}
+
+		lint.SortByStep(findings)
+		report.Add(findings...)
+	}
+	return nil
+}
+
+// deployProblem returns why a rover could not be deployed
+func deployProblem(err error) string {
+	switch {
+	case errors.Is(err, rover.ErrRoverCollision):
+		return "another rover stands there"
+	case errors.Is(err, rover.ErrObstacleCollision):
+		return "an obstacle covers the cell"
+	default:
+		return err.Error()
+	}
+}
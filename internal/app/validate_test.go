@@ -0,0 +1,150 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"mars/internal/config"
+	"mars/internal/lint"
+	"mars/internal/parser"
+	"mars/internal/rover"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApp_Validate(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		input        string
+		cfg          func(cfg *config.Config)
+		wantFindings []lint.Finding
+	}{
+		"ok - nothing to report": {
+			input: "5 5\n1 2 N\nLMLMLMLMM\n3 3 E\nMMRMMRMRRM",
+		},
+		"err - every parse problem": {
+			input: "5 5\n1 2 Q\nMZ",
+			wantFindings: []lint.Finding{
+				{Severity: lint.SeverityError, Line: 2, Column: 5, Message: "invalid direction given, must be N, E, S, W: given Q", Hint: "headings are N, E, S and W"},
+				{Severity: lint.SeverityError, Line: 3, Column: 2, Message: "invalid command character given, must be L, R, M: given 'Z'", Hint: "commands are L (turn left), R (turn right) and M (move forward)"},
+			},
+		},
+		"err - overlapping start positions": {
+			input: "5 5\n1 2 N\n\n1 2 E\nM\n3 3 N\nM\n3 3 S",
+			wantFindings: []lint.Finding{
+				{Severity: lint.SeverityError, Rover: "2", Message: "rover cannot be deployed at 1 2 E: another rover stands there"},
+				{Severity: lint.SeverityWarning, Rover: "4", Message: "rover starts on the cell rover 3 started on, it can only be deployed because rover 3 moved away first"},
+			},
+		},
+		"err - rovers after a failed deploy keep their number": {
+			input: "5 5\n1 1 N\n\n1 1 N\nM\n5 5 N\nM",
+			wantFindings: []lint.Finding{
+				{Severity: lint.SeverityError, Rover: "2", Message: "rover cannot be deployed at 1 1 N: another rover stands there"},
+				{Severity: lint.SeverityWarning, Rover: "3", Step: 1, Message: "move blocked by the edge of the plateau, the rover stays at 5 5 N"},
+			},
+		},
+		"err - aborted rover after a failed deploy": {
+			input: "5 5\n1 3 N\n\n1 3 E\n\n1 1 N\nMM",
+			cfg: func(cfg *config.Config) {
+				cfg.CollisionPolicy = rover.CollisionAbort
+			},
+			wantFindings: []lint.Finding{
+				{Severity: lint.SeverityError, Rover: "2", Message: "rover cannot be deployed at 1 3 E: another rover stands there"},
+				{Severity: lint.SeverityError, Rover: "3", Message: "rover aborts the mission at 1 2 N: rover 3 blocked at step 2 moving to (1 3 N): path is blocked by another rover"},
+			},
+		},
+		"err - problems before an abort are kept": {
+			input: "5 5\n1 1 N\nL\n0 0 N\nLMRRMLM",
+			cfg: func(cfg *config.Config) {
+				cfg.CollisionPolicy = rover.CollisionAbort
+			},
+			wantFindings: []lint.Finding{
+				{Severity: lint.SeverityWarning, Rover: "2", Step: 2, Message: "move blocked by the edge of the plateau, the rover stays at 0 0 W"},
+				{Severity: lint.SeverityError, Rover: "2", Message: "rover aborts the mission at 1 0 N: rover 2 blocked at step 7 moving to (1 1 N): path is blocked by another rover"},
+			},
+		},
+		"ok - blocked moves and no-op commands": {
+			input: "5 5\n5 5 N\nMLRRRR\n\n4 4 N\n\n4 3 N\nM",
+			wantFindings: []lint.Finding{
+				{Severity: lint.SeverityWarning, Rover: "1", Step: 1, Message: "move blocked by the edge of the plateau, the rover stays at 5 5 N"},
+				{Severity: lint.SeverityInfo, Rover: "1", Step: 2, Message: `commands 2-6 "LRRRR" can be written "L"`},
+				{Severity: lint.SeverityWarning, Rover: "3", Step: 1, Message: "move blocked by another rover, the rover stays at 4 3 N"},
+			},
+		},
+		"err - lost rover": {
+			input: "5 5\n0 0 S\nLRM",
+			cfg: func(cfg *config.Config) {
+				cfg.BoundaryPolicy = rover.BoundaryLost
+			},
+			wantFindings: []lint.Finding{
+				{Severity: lint.SeverityInfo, Rover: "1", Step: 1, Message: `commands 1-2 "LR" leave the rover facing the way it was, they do nothing`},
+				{Severity: lint.SeverityError, Rover: "1", Step: 3, Message: "rover drives off the plateau from 0 0 S and is lost"},
+			},
+		},
+		"err - aborted rover does not hide the next ones": {
+			input: "5 5\n1 3 N\n\n1 1 N\nMM\n5 4 E\nM",
+			cfg: func(cfg *config.Config) {
+				cfg.CollisionPolicy = rover.CollisionAbort
+			},
+			wantFindings: []lint.Finding{
+				{Severity: lint.SeverityError, Rover: "2", Message: "rover aborts the mission at 1 2 N: rover 2 blocked at step 2 moving to (1 3 N): path is blocked by another rover"},
+				{Severity: lint.SeverityWarning, Rover: "3", Step: 1, Message: "move blocked by the edge of the plateau, the rover stays at 5 4 E"},
+			},
+		},
+		"err - lockstep mission failure": {
+			input: "5 5\n1 2 N\nLR\n1 2 E\nM",
+			cfg: func(cfg *config.Config) {
+				cfg.ExecMode = config.ExecLockstep
+			},
+			wantFindings: []lint.Finding{
+				{Severity: lint.SeverityError, Message: "mission fails: rover error executing instruction 2: new rover with id 2 cannot be placed at (1 2 E): path is blocked by another rover"},
+				{Severity: lint.SeverityInfo, Rover: "1", Step: 1, Message: `commands 1-2 "LR" leave the rover facing the way it was, they do nothing`},
+			},
+		},
+		"ok - lockstep blocked moves": {
+			input: "5 5\n0 0 E\nM\n1 0 E\nM\n2 0 W\nM",
+			cfg: func(cfg *config.Config) {
+				cfg.ExecMode = config.ExecLockstep
+			},
+			wantFindings: []lint.Finding{
+				{Severity: lint.SeverityWarning, Rover: "1", Step: 1, Message: "move blocked by another rover, the rover stays at 0 0 E"},
+				{Severity: lint.SeverityWarning, Rover: "2", Step: 1, Message: "move blocked by another rover, the rover stays at 1 0 E"},
+				{Severity: lint.SeverityWarning, Rover: "3", Step: 1, Message: "move blocked by another rover, the rover stays at 2 0 W"},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.AllErrors = true
+			if tc.cfg != nil {
+				tc.cfg(cfg)
+			}
+
+			app := NewApp(parser.New(), rover.NewMissionControlFactory(), strings.NewReader(tc.input), &bytes.Buffer{}, cfg)
+
+			report, err := app.Validate(context.Background())
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.wantFindings, report.Findings)
+		})
+	}
+}
+
+func TestApp_ValidateInputError(t *testing.T) {
+	t.Parallel()
+
+	mockMCFactory := new(MockMissionControlFactory)
+	app := NewApp(new(MockParser), mockMCFactory, errReader{}, &bytes.Buffer{}, config.Default())
+
+	_, err := app.Validate(context.Background())
+	require.ErrorIs(t, err, ErrAppInput)
+
+	// the mission is never run
+	mockMCFactory.AssertNotCalled(t, "Create", mock.Anything)
+}
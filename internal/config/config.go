@@ -3,14 +3,78 @@ package config
 import (
 	"flag"
 	"fmt"
+	"mars/internal/render"
+	"mars/internal/rover"
+	"math"
+	"os"
+	"time"
 )
 
 type OpMode int
 
 const (
-	DefaultServerAddr = ":8080"
-	DefaultMinSizeX   = 2
-	DefaultMinSizeY   = 2
+	DefaultServerAddr      = ":8080"
+	DefaultMinSizeX        = 2
+	DefaultMinSizeY        = 2
+	DefaultBoundaryPolicy  = rover.BoundaryStop
+	DefaultCollisionPolicy = rover.CollisionSkip
+	DefaultExecMode        = ExecSequential
+	DefaultOutputFormat    = render.FormatText
+	DefaultParallel        = 1
+)
+
+// web server timeouts, zero means no timeout
+const (
+	DefaultReadHeaderTimeout = 5 * time.Second
+	DefaultReadTimeout       = 10 * time.Second
+	DefaultWriteTimeout      = 30 * time.Second
+	DefaultIdleTimeout       = 60 * time.Second
+	DefaultShutdownTimeout   = 30 * time.Second
+	DefaultSessionTimeout    = 30 * time.Minute
+)
+
+// images drawn by the png and gif output formats
+const (
+	DefaultCellSize   = 32
+	DefaultFrameDelay = 250 * time.Millisecond
+	MinCellSize       = 4
+	MaxCellSize       = 128
+	MinFrameDelay     = 10 * time.Millisecond // the smallest delay a GIF frame can have
+	MaxFrameDelay     = 10 * time.Second
+)
+
+// mission resource limits, zero means no limit
+const (
+	DefaultMaxPlateauX      = 10_000
+	DefaultMaxPlateauY      = 10_000
+	DefaultMaxObstacles     = 1_000
+	DefaultMaxRovers        = 1_000
+	DefaultMaxRoverCommands = 100_000
+	DefaultMaxTotalCommands = 1_000_000
+)
+
+// random missions written by the generate command
+const (
+	DefaultGenerateMissions = 1
+	DefaultGeneratePlateauX = 5
+	DefaultGeneratePlateauY = 5
+	DefaultGenerateRovers   = 2
+	DefaultGenerateCommands = 10
+)
+
+// commands of the mars binary, each one with its own flags
+const (
+	CommandRun      = "run"
+	CommandValidate = "validate"
+	CommandRender   = "render"
+	CommandServe    = "serve"
+	CommandGenerate = "generate"
+)
+
+// execution modes: rovers run one after another or all together one command per tick
+const (
+	ExecSequential = "sequential"
+	ExecLockstep   = "lockstep"
 )
 
 const (
@@ -20,52 +84,180 @@ const (
 )
 
 type Config struct {
-	FilePath    string
-	MinPlateauX int
-	MinPlateauY int
-	OpMode      OpMode
-	SrvAddr     string
+	Command         string // command of the mars binary being run
+	FilePath        string
+	MinPlateauX     int
+	MinPlateauY     int
+	OpMode          OpMode
+	SrvAddr         string
+	BoundaryPolicy  string
+	CollisionPolicy string
+	Trace           bool
+	ExecMode        string
+	AllErrors       bool
+	Strict          bool
+	OutputFormat    string
+	Batch           bool          // the input holds several missions, each one starting with a "---" separator line
+	Parallel        int           // number of batch missions run at the same time
+	MissionTimeout  time.Duration // time limit of every batch mission, zero means none
+	StreamDelay     time.Duration // pause after every rover step when streaming a mission from the web API
+	Timeouts        Timeouts
+	Limits          Limits
+	Image           Image
+	Generate        Generator
+}
+
+// Timeouts holds the web server timeouts, zero means no timeout
+type Timeouts struct {
+	ReadHeader time.Duration // reading the request headers
+	Read       time.Duration // reading the whole request, body included
+	Write      time.Duration // writing the response, streamed missions are not limited
+	Idle       time.Duration // keeping an idle keep-alive connection open
+	Shutdown   time.Duration // draining in-flight requests once the server is asked to stop
+	Session    time.Duration // keeping a mission session no request has used, zero keeps it until it is deleted
+}
+
+// Image holds the settings of the png and gif output formats
+type Image struct {
+	CellSize   int           // side of a plateau cell, in pixels
+	FrameDelay time.Duration // time every frame of an animation is shown
+}
+
+// Limits holds the upper bounds a mission must stay within to be run, zero means no limit
+type Limits struct {
+	PlateauX      int // plateau width, the x coordinate of its upper-right corner
+	PlateauY      int // plateau height, the y coordinate of its upper-right corner
+	Obstacles     int // obstacles placed on the plateau
+	Rovers        int // rovers in a mission
+	RoverCommands int // commands given to a single rover, or in a single batch of a mission session
+	TotalCommands int // commands given to all the rovers of a mission or mission session
+}
+
+// Generator holds the shape of the random missions written by the generate command
+type Generator struct {
+	Missions int    // missions written, more than one makes a batch input
+	PlateauX int    // x coordinate of the upper-right corner of every plateau
+	PlateauY int    // y coordinate of the upper-right corner of every plateau
+	Rovers   int    // rovers of every mission, deployed on distinct cells
+	Commands int    // commands given to every rover
+	Seed     uint64 // seed of the random missions, zero for a different one every time
+}
+
+// DefaultGenerator returns the default shape of generated missions
+func DefaultGenerator() Generator {
+	return Generator{
+		Missions: DefaultGenerateMissions,
+		PlateauX: DefaultGeneratePlateauX,
+		PlateauY: DefaultGeneratePlateauY,
+		Rovers:   DefaultGenerateRovers,
+		Commands: DefaultGenerateCommands,
+	}
+}
+
+// DefaultImage returns the default settings of the images drawn
+func DefaultImage() Image {
+	return Image{
+		CellSize:   DefaultCellSize,
+		FrameDelay: DefaultFrameDelay,
+	}
+}
+
+// DefaultLimits returns the default mission resource limits
+func DefaultLimits() Limits {
+	return Limits{
+		PlateauX:      DefaultMaxPlateauX,
+		PlateauY:      DefaultMaxPlateauY,
+		Obstacles:     DefaultMaxObstacles,
+		Rovers:        DefaultMaxRovers,
+		RoverCommands: DefaultMaxRoverCommands,
+		TotalCommands: DefaultMaxTotalCommands,
+	}
 }
 
-// New returns a pointer to a new Config struct from a filePath, minPlateauX and minPlateauY
+// DefaultTimeouts returns the default web server timeouts
+func DefaultTimeouts() Timeouts {
+	return Timeouts{
+		ReadHeader: DefaultReadHeaderTimeout,
+		Read:       DefaultReadTimeout,
+		Write:      DefaultWriteTimeout,
+		Idle:       DefaultIdleTimeout,
+		Shutdown:   DefaultShutdownTimeout,
+		Session:    DefaultSessionTimeout,
+	}
+}
+
+// New returns a pointer to a new Config struct from a filePath, minPlateauX and minPlateauY. Simulation policies take their default values
 // Note: The returned config is not validated. Call Validate() to check
 func New(minPlateauX, minPlateauY int, filePath string, opMode OpMode, srvAddr string) *Config {
+	command := CommandRun
+	if opMode == ModeWebAPI {
+		command = CommandServe
+	}
+
 	return &Config{
-		FilePath:    filePath,
-		MinPlateauX: minPlateauX,
-		MinPlateauY: minPlateauY,
-		OpMode:      opMode,
-		SrvAddr:     srvAddr,
+		Command:         command,
+		FilePath:        filePath,
+		MinPlateauX:     minPlateauX,
+		MinPlateauY:     minPlateauY,
+		OpMode:          opMode,
+		SrvAddr:         srvAddr,
+		BoundaryPolicy:  DefaultBoundaryPolicy,
+		CollisionPolicy: DefaultCollisionPolicy,
+		ExecMode:        DefaultExecMode,
+		OutputFormat:    DefaultOutputFormat,
+		Parallel:        DefaultParallel,
+		Timeouts:        DefaultTimeouts(),
+		Limits:          DefaultLimits(),
+		Image:           DefaultImage(),
+		Generate:        DefaultGenerator(),
 	}
 }
 
 // Default returns a pointer to a new Config struct with predefined sensible (ModeCLI) defaults
 func Default() *Config {
 	return &Config{
-		MinPlateauX: DefaultMinSizeX,
-		MinPlateauY: DefaultMinSizeY,
-		OpMode:      ModeCLI,
-		SrvAddr:     DefaultServerAddr,
+		Command:         CommandRun,
+		MinPlateauX:     DefaultMinSizeX,
+		MinPlateauY:     DefaultMinSizeY,
+		OpMode:          ModeCLI,
+		SrvAddr:         DefaultServerAddr,
+		BoundaryPolicy:  DefaultBoundaryPolicy,
+		CollisionPolicy: DefaultCollisionPolicy,
+		ExecMode:        DefaultExecMode,
+		OutputFormat:    DefaultOutputFormat,
+		Parallel:        DefaultParallel,
+		Timeouts:        DefaultTimeouts(),
+		Limits:          DefaultLimits(),
+		Image:           DefaultImage(),
+		Generate:        DefaultGenerator(),
 	}
 }
 
-// ParseFlags returns a pointer to a new Config struct from user provided cli flags
+// ParseFlags returns a pointer to a new Config struct from user provided cli flags, a config file and MARS_* environment variables, see Load
 func ParseFlags(args []string) (*Config, error) {
-	cfg := &Config{}
+	return Load(args, os.LookupEnv)
+}
 
-	flags := flag.NewFlagSet("mars-rovers", flag.ContinueOnError)
+// Load returns a pointer to a new Config struct merged from, by increasing precedence: the defaults, the config file given by -config or MARS_CONFIG,
+// the MARS_* environment variables read with lookupEnv and the cli flags in args. The merged configuration is validated.
+// Args hold the flags of both the run and serve commands, -webapi selecting the latter
+func Load(args []string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	cfg := Default()
 
-	// flags for cli mode
-	flags.StringVar(&cfg.FilePath, "file", "", "Input file. If not provided, reads from stdin.")
-	flags.IntVar(&cfg.MinPlateauX, "min-size-x", DefaultMinSizeX, "Minimum size X for plateau (optional)")
-	flags.IntVar(&cfg.MinPlateauY, "min-size-y", DefaultMinSizeY, "Minimum size Y for plateau (optional)")
+	flags := flag.NewFlagSet("mars-rovers", flag.ContinueOnError)
+	configFile := addConfigFlag(flags)
+	addInputFlags(flags, cfg)
+	addBatchFlags(flags, cfg)
+	addSimulationFlags(flags, cfg)
+	addOutputFlags(flags, cfg)
+	addLimitFlags(flags, cfg)
 
 	// flags for webapi mode
-	webAPIFlag := flags.Bool("webapi", false, "run in webapi server mode")
-	flags.StringVar(&cfg.SrvAddr, "addr", DefaultServerAddr, "port for webapi server")
+	webAPIFlag := flags.Bool(webAPIFlagName, false, "run in webapi server mode")
+	addServerFlags(flags, cfg)
 
-	if err := flags.Parse(args); err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrParserInvalidValue, err)
+	if err := load(flags, configFile, args, lookupEnv); err != nil {
+		return nil, err
 	}
 
 	// assign operating mode based on -webapi flag being present
@@ -74,9 +266,11 @@ func ParseFlags(args []string) (*Config, error) {
 			return nil, ErrParserFlagsIncompatible
 		}
 		cfg.OpMode = ModeWebAPI
+		cfg.Command = CommandServe
 
 	} else {
 		cfg.OpMode = ModeCLI
+		cfg.Command = CommandRun
 	}
 
 	if err := cfg.Validate(); err != nil {
@@ -86,6 +280,36 @@ func ParseFlags(args []string) (*Config, error) {
 	return cfg, nil
 }
 
+// load parses the flags in args, then gives the flags left unset the values found in the config file and the environment
+func load(flags *flag.FlagSet, configFile *string, args []string, lookupEnv func(string) (string, bool)) error {
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%w: %w", ErrParserInvalidValue, err)
+	}
+
+	// flags given on the command line win over the config file and the environment
+	explicit := map[string]bool{}
+	flags.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+
+	path := *configFile
+	if !explicit[configFlag] {
+		path, _ = lookupEnv(envName(configFlag))
+	}
+
+	if path != "" {
+		values, err := readFile(path)
+		if err != nil {
+			return err
+		}
+		if err := apply(flags, values, explicit, path); err != nil {
+			return err
+		}
+	}
+
+	return apply(flags, envValues(flags, lookupEnv), explicit, "environment")
+}
+
 // Validate checks if the configuration is valid
 func (c *Config) Validate() error {
 	if c == nil {
@@ -96,6 +320,12 @@ func (c *Config) Validate() error {
 		return ErrParserModeUnknown
 	}
 
+	switch c.Command {
+	case CommandRun, CommandValidate, CommandRender, CommandServe, CommandGenerate:
+	default:
+		return fmt.Errorf("%w: (got %q)", ErrParserCommand, c.Command)
+	}
+
 	if c.MinPlateauX < 1 || c.MinPlateauY < 1 {
 		return fmt.Errorf("%w: (got %dx%d)", ErrParserPlateauDimensions, c.MinPlateauX, c.MinPlateauY)
 	}
@@ -104,5 +334,134 @@ func (c *Config) Validate() error {
 		return ErrParserServerAddr
 	}
 
+	// policies and output formats are checked by the packages they belong to
+	if _, err := rover.NewBoundaryPolicy(c.BoundaryPolicy); err != nil {
+		return fmt.Errorf("%w: (got %q)", ErrParserBoundaryPolicy, c.BoundaryPolicy)
+	}
+
+	if _, err := rover.NewCollisionPolicy(c.CollisionPolicy); err != nil {
+		return fmt.Errorf("%w: (got %q)", ErrParserCollisionPolicy, c.CollisionPolicy)
+	}
+
+	switch c.ExecMode {
+	case ExecSequential, ExecLockstep:
+	default:
+		return fmt.Errorf("%w: (got %q)", ErrParserExecMode, c.ExecMode)
+	}
+
+	if _, err := render.New(c.OutputFormat); err != nil {
+		return fmt.Errorf("%w: (got %q)", ErrParserOutputFormat, c.OutputFormat)
+	}
+
+	if c.Batch && (c.OutputFormat == render.FormatPNG || c.OutputFormat == render.FormatGIF) {
+		return fmt.Errorf("%w: (got %q)", ErrParserImageBatch, c.OutputFormat)
+	}
+
+	if c.Image.CellSize < MinCellSize || c.Image.CellSize > MaxCellSize || c.Image.FrameDelay < MinFrameDelay || c.Image.FrameDelay > MaxFrameDelay {
+		return fmt.Errorf("%w: (got %d pixels and %s)", ErrParserImage, c.Image.CellSize, c.Image.FrameDelay)
+	}
+
+	if c.Parallel < 1 {
+		return fmt.Errorf("%w: (got %d)", ErrParserParallel, c.Parallel)
+	}
+
+	if c.MissionTimeout < 0 {
+		return fmt.Errorf("%w: (got %s)", ErrParserMissionTimeout, c.MissionTimeout)
+	}
+
+	if c.StreamDelay < 0 {
+		return fmt.Errorf("%w: (got %s)", ErrParserStreamDelay, c.StreamDelay)
+	}
+
+	limits := []struct {
+		name  string
+		limit int
+	}{
+		{"plateau width", c.Limits.PlateauX},
+		{"plateau height", c.Limits.PlateauY},
+		{"obstacles", c.Limits.Obstacles},
+		{"rovers", c.Limits.Rovers},
+		{"rover commands", c.Limits.RoverCommands},
+		{"total commands", c.Limits.TotalCommands},
+	}
+
+	for _, l := range limits {
+		if l.limit < 0 {
+			return fmt.Errorf("%w: %s (got %d)", ErrParserLimits, l.name, l.limit)
+		}
+	}
+
+	if (c.Limits.PlateauX > 0 && c.Limits.PlateauX < c.MinPlateauX) || (c.Limits.PlateauY > 0 && c.Limits.PlateauY < c.MinPlateauY) {
+		return fmt.Errorf("%w: maximum plateau %dx%d is below the minimum %dx%d", ErrParserLimits, c.Limits.PlateauX, c.Limits.PlateauY, c.MinPlateauX, c.MinPlateauY)
+	}
+
+	if c.Command == CommandGenerate {
+		if err := c.Generate.validate(c.MinPlateauX, c.MinPlateauY, c.Limits); err != nil {
+			return err
+		}
+	}
+
+	timeouts := []struct {
+		name    string
+		timeout time.Duration
+	}{
+		{"read header", c.Timeouts.ReadHeader},
+		{"read", c.Timeouts.Read},
+		{"write", c.Timeouts.Write},
+		{"idle", c.Timeouts.Idle},
+		{"shutdown", c.Timeouts.Shutdown},
+		{"session", c.Timeouts.Session},
+	}
+
+	for _, t := range timeouts {
+		if t.timeout < 0 {
+			return fmt.Errorf("%w: %s timeout (got %s)", ErrParserTimeout, t.name, t.timeout)
+		}
+	}
+
+	return nil
+}
+
+// NeedsTrace reports whether missions must record every command applied to the rovers, either because it was asked for or because the output format draws their paths
+func (c *Config) NeedsTrace() bool {
+	switch c.OutputFormat {
+	case render.FormatGrid, render.FormatGridSteps, render.FormatSVG, render.FormatPNG, render.FormatGIF:
+		return true
+	default:
+		return c.Trace
+	}
+}
+
+// validate checks the generated missions can be parsed back with the given minimum plateau size and run within the limits
+func (g Generator) validate(minPlateauX, minPlateauY int, limits Limits) error {
+	if g.Missions < 1 || g.Rovers < 1 || g.Commands < 0 {
+		return fmt.Errorf("%w: %d missions of %d rovers with %d commands", ErrParserGenerator, g.Missions, g.Rovers, g.Commands)
+	}
+
+	if g.PlateauX < minPlateauX || g.PlateauY < minPlateauY {
+		return fmt.Errorf("%w: plateau %dx%d is below the minimum %dx%d", ErrParserGenerator, g.PlateauX, g.PlateauY, minPlateauX, minPlateauY)
+	}
+
+	if (limits.PlateauX > 0 && g.PlateauX > limits.PlateauX) || (limits.PlateauY > 0 && g.PlateauY > limits.PlateauY) {
+		return fmt.Errorf("%w: plateau %dx%d is over the maximum %dx%d", ErrParserGenerator, g.PlateauX, g.PlateauY, limits.PlateauX, limits.PlateauY)
+	}
+
+	if limits.Rovers > 0 && g.Rovers > limits.Rovers {
+		return fmt.Errorf("%w: %d rovers is over the maximum %d", ErrParserGenerator, g.Rovers, limits.Rovers)
+	}
+
+	if limits.RoverCommands > 0 && g.Commands > limits.RoverCommands {
+		return fmt.Errorf("%w: %d commands per rover is over the maximum %d", ErrParserGenerator, g.Commands, limits.RoverCommands)
+	}
+
+	// the commands of a mission are counted without multiplying, which could overflow
+	if limits.TotalCommands > 0 && g.Commands > limits.TotalCommands/g.Rovers {
+		return fmt.Errorf("%w: %d rovers with %d commands each is over the maximum of %d commands", ErrParserGenerator, g.Rovers, g.Commands, limits.TotalCommands)
+	}
+
+	// every rover needs a cell of its own, a plateau as high as an int can be has room for any of them
+	if g.PlateauY < math.MaxInt && (g.Rovers-1)/(g.PlateauY+1) > g.PlateauX {
+		return fmt.Errorf("%w: %d rovers do not fit on a %dx%d plateau", ErrParserGenerator, g.Rovers, g.PlateauX, g.PlateauY)
+	}
 	return nil
 }
@@ -1,7 +1,14 @@
 package config
 
 import (
+	"flag"
+	"mars/internal/render"
+	"mars/internal/rover"
+	"math"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -40,6 +47,193 @@ func TestParseFlags(t *testing.T) {
 			wantConfig: New(5, 6, "", ModeCLI, DefaultServerAddr),
 			wantErr:    nil,
 		},
+		"ok - with boundary policy": {
+			args: []string{"-boundary", "lost"},
+			wantConfig: func() *Config {
+				cfg := New(DefaultMinSizeX, DefaultMinSizeY, "", ModeCLI, DefaultServerAddr)
+				cfg.BoundaryPolicy = rover.BoundaryLost
+				return cfg
+			}(),
+			wantErr: nil,
+		},
+		"ok - with collision policy": {
+			args: []string{"-collision", "swap"},
+			wantConfig: func() *Config {
+				cfg := New(DefaultMinSizeX, DefaultMinSizeY, "", ModeCLI, DefaultServerAddr)
+				cfg.CollisionPolicy = rover.CollisionSwap
+				return cfg
+			}(),
+			wantErr: nil,
+		},
+		"ok - with trace": {
+			args: []string{"-trace"},
+			wantConfig: func() *Config {
+				cfg := New(DefaultMinSizeX, DefaultMinSizeY, "", ModeCLI, DefaultServerAddr)
+				cfg.Trace = true
+				return cfg
+			}(),
+			wantErr: nil,
+		},
+		"ok - with lockstep execution": {
+			args: []string{"-exec", "lockstep"},
+			wantConfig: func() *Config {
+				cfg := New(DefaultMinSizeX, DefaultMinSizeY, "", ModeCLI, DefaultServerAddr)
+				cfg.ExecMode = ExecLockstep
+				return cfg
+			}(),
+			wantErr: nil,
+		},
+		"ok - with all errors": {
+			args: []string{"-all-errors"},
+			wantConfig: func() *Config {
+				cfg := New(DefaultMinSizeX, DefaultMinSizeY, "", ModeCLI, DefaultServerAddr)
+				cfg.AllErrors = true
+				return cfg
+			}(),
+			wantErr: nil,
+		},
+		"ok - with strict parsing": {
+			args: []string{"-strict"},
+			wantConfig: func() *Config {
+				cfg := New(DefaultMinSizeX, DefaultMinSizeY, "", ModeCLI, DefaultServerAddr)
+				cfg.Strict = true
+				return cfg
+			}(),
+			wantErr: nil,
+		},
+		"ok - with stream delay": {
+			args: []string{"-webapi", "-stream-delay", "250ms"},
+			wantConfig: func() *Config {
+				cfg := New(DefaultMinSizeX, DefaultMinSizeY, "", ModeWebAPI, DefaultServerAddr)
+				cfg.StreamDelay = 250 * time.Millisecond
+				return cfg
+			}(),
+			wantErr: nil,
+		},
+		"err - negative stream delay": {
+			args:    []string{"-webapi", "-stream-delay", "-1s"},
+			wantErr: ErrParserStreamDelay,
+		},
+		"ok - with server timeouts": {
+			args: []string{"-webapi", "-read-timeout", "2s", "-write-timeout", "0", "-shutdown-timeout", "1m", "-session-timeout", "5m"},
+			wantConfig: func() *Config {
+				cfg := New(DefaultMinSizeX, DefaultMinSizeY, "", ModeWebAPI, DefaultServerAddr)
+				cfg.Timeouts.Read = 2 * time.Second
+				cfg.Timeouts.Write = 0
+				cfg.Timeouts.Shutdown = time.Minute
+				cfg.Timeouts.Session = 5 * time.Minute
+				return cfg
+			}(),
+			wantErr: nil,
+		},
+		"err - negative timeout": {
+			args:    []string{"-webapi", "-idle-timeout", "-1s"},
+			wantErr: ErrParserTimeout,
+		},
+		"ok - with resource limits": {
+			args: []string{"-max-plateau-x", "50", "-max-obstacles", "20", "-max-rovers", "0", "-max-commands", "500"},
+			wantConfig: func() *Config {
+				cfg := New(DefaultMinSizeX, DefaultMinSizeY, "", ModeCLI, DefaultServerAddr)
+				cfg.Limits.PlateauX = 50
+				cfg.Limits.Obstacles = 20
+				cfg.Limits.Rovers = 0
+				cfg.Limits.TotalCommands = 500
+				return cfg
+			}(),
+			wantErr: nil,
+		},
+		"err - negative limit": {
+			args:    []string{"-max-rover-commands", "-1"},
+			wantErr: ErrParserLimits,
+		},
+		"err - maximum plateau below the minimum": {
+			args:    []string{"-min-size-y", "10", "-max-plateau-y", "5"},
+			wantErr: ErrParserLimits,
+		},
+		"ok - with image settings": {
+			args: []string{"-format", "gif", "-cell-size", "16", "-frame-delay", "100ms"},
+			wantConfig: func() *Config {
+				cfg := New(DefaultMinSizeX, DefaultMinSizeY, "", ModeCLI, DefaultServerAddr)
+				cfg.OutputFormat = render.FormatGIF
+				cfg.Image = Image{CellSize: 16, FrameDelay: 100 * time.Millisecond}
+				return cfg
+			}(),
+			wantErr: nil,
+		},
+		"err - cell size too small": {
+			args:    []string{"-format", "png", "-cell-size", "2"},
+			wantErr: ErrParserImage,
+		},
+		"err - frame delay too long": {
+			args:    []string{"-format", "gif", "-frame-delay", "1m"},
+			wantErr: ErrParserImage,
+		},
+		"err - image of a batch": {
+			args:    []string{"-batch", "-format", "png"},
+			wantErr: ErrParserImageBatch,
+		},
+		"ok - batch run in parallel": {
+			args: []string{"-batch", "-parallel", "4"},
+			wantConfig: func() *Config {
+				cfg := New(DefaultMinSizeX, DefaultMinSizeY, "", ModeCLI, DefaultServerAddr)
+				cfg.Batch = true
+				cfg.Parallel = 4
+				return cfg
+			}(),
+			wantErr: nil,
+		},
+		"ok - batch with mission timeout": {
+			args: []string{"-batch", "-mission-timeout", "2s"},
+			wantConfig: func() *Config {
+				cfg := New(DefaultMinSizeX, DefaultMinSizeY, "", ModeCLI, DefaultServerAddr)
+				cfg.Batch = true
+				cfg.MissionTimeout = 2 * time.Second
+				return cfg
+			}(),
+			wantErr: nil,
+		},
+		"err - negative mission timeout": {
+			args:    []string{"-mission-timeout", "-1s"},
+			wantErr: ErrParserMissionTimeout,
+		},
+		"err - no parallel missions": {
+			args:    []string{"-batch", "-parallel", "0"},
+			wantErr: ErrParserParallel,
+		},
+		"ok - with output format": {
+			args: []string{"-format", "table"},
+			wantConfig: func() *Config {
+				cfg := New(DefaultMinSizeX, DefaultMinSizeY, "", ModeCLI, DefaultServerAddr)
+				cfg.OutputFormat = render.FormatTable
+				return cfg
+			}(),
+			wantErr: nil,
+		},
+		"ok - with grid output format": {
+			args: []string{"-format", "grid-steps"},
+			wantConfig: func() *Config {
+				cfg := New(DefaultMinSizeX, DefaultMinSizeY, "", ModeCLI, DefaultServerAddr)
+				cfg.OutputFormat = render.FormatGridSteps
+				return cfg
+			}(),
+			wantErr: nil,
+		},
+		"err - unknown output format": {
+			args:    []string{"-format", "yaml"},
+			wantErr: ErrParserOutputFormat,
+		},
+		"err - unknown execution mode": {
+			args:    []string{"-exec", "parallel"},
+			wantErr: ErrParserExecMode,
+		},
+		"err - unknown collision policy": {
+			args:    []string{"-collision", "bounce"},
+			wantErr: ErrParserCollisionPolicy,
+		},
+		"err - unknown boundary policy": {
+			args:    []string{"-boundary", "bounce"},
+			wantErr: ErrParserBoundaryPolicy,
+		},
 		"err - negative dimensions": {
 			args:    []string{"-min-size-x", "-1", "-min-size-y", "5"},
 			wantErr: ErrParserPlateauDimensions,
@@ -73,6 +267,337 @@ func TestParseFlags(t *testing.T) {
 	}
 }
 
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	jsonFile := writeFile("mars.json", `{"boundary": "wrap", "min-size-x": 4, "trace": true, "mission-timeout": "2s"}`)
+	tomlFile := writeFile("mars.toml", "# mission defaults\nboundary = \"wrap\"\nmin-size-x = 4 # wider plateaus\ntrace = true\n'mission-timeout' = '2s'\n")
+	webFile := writeFile("web.toml", "webapi = true\naddr = \":9000\"\n")
+
+	fromFile := func() *Config {
+		cfg := New(4, DefaultMinSizeY, "", ModeCLI, DefaultServerAddr)
+		cfg.BoundaryPolicy = rover.BoundaryWrap
+		cfg.Trace = true
+		cfg.MissionTimeout = 2 * time.Second
+		return cfg
+	}
+
+	testCases := map[string]struct {
+		args       []string
+		env        map[string]string
+		wantConfig *Config
+		wantErr    error
+	}{
+		"ok - json file": {
+			args:       []string{"-config", jsonFile},
+			wantConfig: fromFile(),
+		},
+		"ok - toml file": {
+			args:       []string{"-config", tomlFile},
+			wantConfig: fromFile(),
+		},
+		"ok - file from the environment": {
+			env:        map[string]string{"MARS_CONFIG": tomlFile},
+			wantConfig: fromFile(),
+		},
+		"ok - operating mode from the file": {
+			args:       []string{"-config", webFile},
+			wantConfig: New(DefaultMinSizeX, DefaultMinSizeY, "", ModeWebAPI, ":9000"),
+		},
+		"ok - environment over the file": {
+			args: []string{"-config", jsonFile},
+			env:  map[string]string{"MARS_BOUNDARY": "lost", "MARS_MIN_SIZE_Y": "3"},
+			wantConfig: func() *Config {
+				cfg := fromFile()
+				cfg.BoundaryPolicy = rover.BoundaryLost
+				cfg.MinPlateauY = 3
+				return cfg
+			}(),
+		},
+		"ok - flags over the environment and the file": {
+			args: []string{"-config", jsonFile, "-boundary", "strict", "-trace=false"},
+			env:  map[string]string{"MARS_BOUNDARY": "lost", "MARS_COLLISION": "halt"},
+			wantConfig: func() *Config {
+				cfg := fromFile()
+				cfg.BoundaryPolicy = rover.BoundaryStrict
+				cfg.CollisionPolicy = rover.CollisionHalt
+				cfg.Trace = false
+				return cfg
+			}(),
+		},
+		"ok - config flag over the environment": {
+			args:       []string{"-config", jsonFile},
+			env:        map[string]string{"MARS_CONFIG": filepath.Join(dir, "missing.json")},
+			wantConfig: fromFile(),
+		},
+		"err - unknown setting in the file": {
+			args:    []string{"-config", writeFile("unknown.json", `{"speed": 3}`)},
+			wantErr: ErrParserConfigKey,
+		},
+		"err - config file set from a config file": {
+			args:    []string{"-config", writeFile("nested.toml", `config = "other.toml"`)},
+			wantErr: ErrParserConfigKey,
+		},
+		"err - invalid value in the file": {
+			args:    []string{"-config", writeFile("invalid.json", `{"min-size-x": "wide"}`)},
+			wantErr: ErrParserInvalidValue,
+		},
+		"err - invalid value in the environment": {
+			env:     map[string]string{"MARS_PARALLEL": "many"},
+			wantErr: ErrParserInvalidValue,
+		},
+		"err - merged config validated": {
+			args:    []string{"-config", jsonFile},
+			env:     map[string]string{"MARS_COLLISION": "bounce"},
+			wantErr: ErrParserCollisionPolicy,
+		},
+		"err - file and webapi from different sources": {
+			args:    []string{"-file", "data.txt"},
+			env:     map[string]string{"MARS_WEBAPI": "true"},
+			wantErr: ErrParserFlagsIncompatible,
+		},
+		"err - unsupported file format": {
+			args:    []string{"-config", writeFile("mars.yaml", "boundary: wrap")},
+			wantErr: ErrParserConfigFormat,
+		},
+		"err - missing file": {
+			args:    []string{"-config", filepath.Join(dir, "missing.json")},
+			wantErr: ErrParserConfigFile,
+		},
+		"err - malformed toml": {
+			args:    []string{"-config", writeFile("tables.toml", "[mission]\nboundary = \"wrap\"\n")},
+			wantErr: ErrParserConfigFile,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			lookupEnv := func(key string) (string, bool) {
+				v, ok := tc.env[key]
+				return v, ok
+			}
+
+			gotConfig, err := Load(tc.args, lookupEnv)
+
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantConfig, gotConfig)
+		})
+	}
+}
+
+func TestLoadArgs(t *testing.T) {
+	t.Parallel()
+
+	shared := filepath.Join(t.TempDir(), "mars.toml")
+	require.NoError(t, os.WriteFile(shared, []byte("addr = \":9000\"\nboundary = \"wrap\"\nrovers = 3\n"), 0o600))
+
+	command := func(name string, opMode OpMode, change func(cfg *Config)) *Config {
+		cfg := Default()
+		cfg.Command = name
+		cfg.OpMode = opMode
+		if change != nil {
+			change(cfg)
+		}
+		return cfg
+	}
+
+	testCases := map[string]struct {
+		args       []string
+		env        map[string]string
+		wantConfig *Config
+		wantErr    error
+	}{
+		"ok - run with its flags": {
+			args: []string{"run", "-file", "data.txt", "-batch", "-collision", "halt"},
+			wantConfig: command(CommandRun, ModeCLI, func(cfg *Config) {
+				cfg.FilePath = "data.txt"
+				cfg.Batch = true
+				cfg.CollisionPolicy = rover.CollisionHalt
+			}),
+		},
+		"ok - validate reports every problem by default": {
+			args: []string{"validate", "-strict"},
+			wantConfig: command(CommandValidate, ModeCLI, func(cfg *Config) {
+				cfg.AllErrors = true
+				cfg.Strict = true
+			}),
+		},
+		"ok - render as a table by default": {
+			args: []string{"render", "-trace"},
+			wantConfig: command(CommandRender, ModeCLI, func(cfg *Config) {
+				cfg.OutputFormat = render.FormatTable
+				cfg.Trace = true
+			}),
+		},
+		"ok - serve with environment": {
+			args: []string{"serve", "-boundary", "lost"},
+			env:  map[string]string{"MARS_ADDR": ":7000"},
+			wantConfig: command(CommandServe, ModeWebAPI, func(cfg *Config) {
+				cfg.SrvAddr = ":7000"
+				cfg.BoundaryPolicy = rover.BoundaryLost
+			}),
+		},
+		"ok - generate": {
+			args: []string{"generate", "-missions", "3", "-rovers", "4", "-seed", "42"},
+			wantConfig: command(CommandGenerate, ModeCLI, func(cfg *Config) {
+				cfg.Generate.Missions = 3
+				cfg.Generate.Rovers = 4
+				cfg.Generate.Seed = 42
+			}),
+		},
+		"ok - generated plateau whose cells overflow an int": {
+			args: []string{"generate", "-plateau-x", "9223372036854775807", "-plateau-y", "2", "-max-plateau-x", "0", "-rovers", "1"},
+			wantConfig: command(CommandGenerate, ModeCLI, func(cfg *Config) {
+				cfg.Generate.PlateauX = math.MaxInt
+				cfg.Generate.PlateauY = 2
+				cfg.Generate.Rovers = 1
+				cfg.Limits.PlateauX = 0
+			}),
+		},
+		"ok - config file shared by several commands": {
+			args: []string{"run", "-config", shared},
+			wantConfig: command(CommandRun, ModeCLI, func(cfg *Config) {
+				cfg.BoundaryPolicy = rover.BoundaryWrap
+			}),
+		},
+		"ok - environment of other commands ignored": {
+			args:       []string{"validate"},
+			env:        map[string]string{"MARS_MISSIONS": "many"},
+			wantConfig: command(CommandValidate, ModeCLI, func(cfg *Config) { cfg.AllErrors = true }),
+		},
+		"ok - flags without a command": {
+			args:       []string{"-webapi", "-addr", ":5000"},
+			wantConfig: New(DefaultMinSizeX, DefaultMinSizeY, "", ModeWebAPI, ":5000"),
+		},
+		"ok - no args": {
+			args:       []string{},
+			wantConfig: Default(),
+		},
+		"err - unknown command": {
+			args:    []string{"launch"},
+			wantErr: ErrParserCommand,
+		},
+		"err - flag of another command": {
+			args:    []string{"run", "-addr", ":5000"},
+			wantErr: ErrParserInvalidValue,
+		},
+		"err - unexpected argument": {
+			args:    []string{"run", "data.txt"},
+			wantErr: ErrParserInvalidValue,
+		},
+		"err - help": {
+			args:    []string{"help", "run"},
+			wantErr: flag.ErrHelp,
+		},
+		"err - help flag of a command": {
+			args:    []string{"serve", "-h"},
+			wantErr: flag.ErrHelp,
+		},
+		"err - rovers do not fit on the generated plateau": {
+			args:    []string{"generate", "-plateau-x", "2", "-plateau-y", "2", "-rovers", "10"},
+			wantErr: ErrParserGenerator,
+		},
+		"err - generated plateau over the limit": {
+			args:    []string{"generate", "-plateau-x", "20000"},
+			wantErr: ErrParserGenerator,
+		},
+		"err - generated rovers over the limit": {
+			args:    []string{"generate", "-rovers", "30", "-max-rovers", "20"},
+			wantErr: ErrParserGenerator,
+		},
+		"err - generated commands over the total limit": {
+			args:    []string{"generate", "-rovers", "4", "-commands", "30", "-max-commands", "100"},
+			wantErr: ErrParserGenerator,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			lookupEnv := func(key string) (string, bool) {
+				v, ok := tc.env[key]
+				return v, ok
+			}
+
+			gotConfig, err := LoadArgs(tc.args, lookupEnv)
+
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantConfig, gotConfig)
+		})
+	}
+}
+
+func TestParseTOML(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		input   string
+		want    map[string]string
+		wantErr bool
+	}{
+		"ok - strings, integers and booleans": {
+			input: "addr = \":80\" # public\nmax-rovers = 1_000\n\"all-errors\" = false\nfile = 'C:\\missions\\day1.txt'\nquote = \"say \\\"hi\\\" # not a comment\"\n",
+			want: map[string]string{
+				"addr":       ":80",
+				"max-rovers": "1000",
+				"all-errors": "false",
+				"file":       `C:\missions\day1.txt`,
+				"quote":      `say "hi" # not a comment`,
+			},
+		},
+		"err - missing equal sign": {
+			input:   "trace true",
+			wantErr: true,
+		},
+		"err - unterminated string": {
+			input:   `addr = ":80`,
+			wantErr: true,
+		},
+		"err - unsupported value": {
+			input:   "boundary = [\"wrap\"]",
+			wantErr: true,
+		},
+		"err - text after the value": {
+			input:   `addr = ":80" ":81"`,
+			wantErr: true,
+		},
+		"err - key set twice": {
+			input:   "trace = true\ntrace = false",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseTOML([]byte(tc.input))
+
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
 func TestNew(t *testing.T) {
 	t.Parallel()
 
@@ -107,6 +632,16 @@ func TestDefault(t *testing.T) {
 	assert.Equal(t, defaultFilePath, cfgDefault.FilePath)
 	assert.Equal(t, ModeCLI, cfgDefault.OpMode)
 	assert.Equal(t, DefaultServerAddr, cfgDefault.SrvAddr)
+	assert.Equal(t, DefaultBoundaryPolicy, cfgDefault.BoundaryPolicy)
+	assert.Equal(t, DefaultCollisionPolicy, cfgDefault.CollisionPolicy)
+	assert.Equal(t, DefaultExecMode, cfgDefault.ExecMode)
+	assert.Equal(t, DefaultOutputFormat, cfgDefault.OutputFormat)
+	assert.Equal(t, DefaultParallel, cfgDefault.Parallel)
+	assert.Equal(t, DefaultTimeouts(), cfgDefault.Timeouts)
+	assert.Equal(t, DefaultLimits(), cfgDefault.Limits)
+	assert.Equal(t, DefaultImage(), cfgDefault.Image)
+	assert.Equal(t, DefaultGenerator(), cfgDefault.Generate)
+	assert.Equal(t, CommandRun, cfgDefault.Command)
 }
 
 func TestValidate(t *testing.T) {
@@ -136,6 +671,22 @@ func TestValidate(t *testing.T) {
 			config:  New(5, 5, "", ModeWebAPI, ""),
 			wantErr: ErrParserServerAddr,
 		},
+		"err - ErrParserCommand": {
+			config: func() *Config {
+				cfg := Default()
+				cfg.Command = "launch"
+				return cfg
+			}(),
+			wantErr: ErrParserCommand,
+		},
+		"err - ErrParserBoundaryPolicy": {
+			config: func() *Config {
+				cfg := Default()
+				cfg.BoundaryPolicy = ""
+				return cfg
+			}(),
+			wantErr: ErrParserBoundaryPolicy,
+		},
 	}
 
 	for name, tc := range testCases {
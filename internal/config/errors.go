@@ -1,6 +1,10 @@
 package config
 
-import "errors"
+import (
+	"errors"
+	"mars/internal/render"
+	"mars/internal/rover"
+)
 
 var (
 	ErrParserFlagsIncompatible = errors.New("cannot use -file and -webapi flags at the same time")
@@ -9,4 +13,20 @@ var (
 	ErrParserInvalidValue      = errors.New("invalid values given to parser")
 	ErrParserNilConfig         = errors.New("config must not be nil")
 	ErrParserModeUnknown       = errors.New("operating mode must be valid")
+	ErrParserBoundaryPolicy    = rover.ErrBoundaryPolicyUnknown
+	ErrParserCollisionPolicy   = rover.ErrCollisionPolicyUnknown
+	ErrParserExecMode          = errors.New("execution mode must be one of sequential, lockstep")
+	ErrParserOutputFormat      = render.ErrFormatUnknown
+	ErrParserImageBatch        = errors.New("png and gif formats draw a single mission, they cannot be used for a batch")
+	ErrParserImage             = errors.New("image cell size must be between 4 and 128 pixels and frame delay between 10ms and 10s")
+	ErrParserParallel          = errors.New("parallel missions must be at least 1")
+	ErrParserMissionTimeout    = errors.New("mission timeout must not be negative")
+	ErrParserStreamDelay       = errors.New("stream delay must not be negative")
+	ErrParserLimits            = errors.New("resource limits must not be negative nor below the minimum plateau size")
+	ErrParserTimeout           = errors.New("server timeouts must not be negative")
+	ErrParserConfigFile        = errors.New("cannot read config file")
+	ErrParserConfigFormat      = errors.New("config file must be a .json or .toml file")
+	ErrParserConfigKey         = errors.New("unknown config setting")
+	ErrParserCommand           = errors.New("command must be one of run, validate, render, serve, generate")
+	ErrParserGenerator         = errors.New("generated missions must have at least one mission and one rover, no negative commands, and a plateau at least the minimum size, all within the mission limits")
 )
@@ -0,0 +1,207 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// configFlag names the flag giving the config file, it cannot be set from the file itself
+	configFlag = "config"
+	// EnvPrefix starts the name of every environment variable read by Load, e.g. MARS_MIN_SIZE_X for -min-size-x
+	EnvPrefix = "MARS_"
+)
+
+// envName returns the environment variable setting the flag with the given name
+func envName(flagName string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// envValues returns the flag values set in the environment, by flag name
+func envValues(flags *flag.FlagSet, lookupEnv func(string) (string, bool)) map[string]string {
+	values := map[string]string{}
+	flags.VisitAll(func(f *flag.Flag) {
+		if f.Name == configFlag {
+			return
+		}
+		if v, ok := lookupEnv(envName(f.Name)); ok {
+			values[f.Name] = v
+		}
+	})
+	return values
+}
+
+// apply sets the flags to the values read from source, leaving alone the flags given on the command line and the settings of other commands
+func apply(flags *flag.FlagSet, values map[string]string, explicit map[string]bool, source string) error {
+	// sorted so the first problem reported does not change from one run to the next
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		if name == configFlag || !knownFlag(name) {
+			return fmt.Errorf("%w: %s: %q", ErrParserConfigKey, source, name)
+		}
+		if explicit[name] || flags.Lookup(name) == nil {
+			continue
+		}
+		if err := flags.Set(name, values[name]); err != nil {
+			return fmt.Errorf("%w: %s: %s: %v", ErrParserInvalidValue, source, name, err)
+		}
+	}
+	return nil
+}
+
+// readFile returns the flag values held in a .json or .toml config file, by flag name
+func readFile(path string) (map[string]string, error) {
+	var parse func([]byte) (map[string]string, error)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		parse = parseJSON
+	case ".toml":
+		parse = parseTOML
+	default:
+		return nil, fmt.Errorf("%w: (got %q)", ErrParserConfigFormat, path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrParserConfigFile, err)
+	}
+
+	values, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrParserConfigFile, path, err)
+	}
+	return values, nil
+}
+
+// parseJSON reads a JSON object of strings, numbers and booleans
+func parseJSON(data []byte) (map[string]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(doc))
+	for key, v := range doc {
+		switch v := v.(type) {
+		case string:
+			values[key] = v
+		case json.Number:
+			values[key] = v.String()
+		case bool:
+			values[key] = strconv.FormatBool(v)
+		default:
+			return nil, fmt.Errorf("%s: value must be a string, a number or a boolean", key)
+		}
+	}
+	return values, nil
+}
+
+// parseTOML reads the flat subset of TOML a config file needs: key = value lines of strings, integers and booleans, with comments and blank lines.
+// Tables, arrays and multi-line strings are not supported
+func parseTOML(data []byte) (map[string]string, error) {
+	values := map[string]string{}
+
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			return nil, fmt.Errorf("line %d: tables are not supported", i+1)
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", i+1)
+		}
+
+		key = strings.TrimSpace(key)
+		if unquoted, err := strconv.Unquote(key); err == nil {
+			key = unquoted
+		} else if len(key) > 1 && strings.HasPrefix(key, "'") && strings.HasSuffix(key, "'") {
+			key = key[1 : len(key)-1]
+		}
+
+		v, err := tomlValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s: %v", i+1, key, err)
+		}
+		if _, dup := values[key]; dup {
+			return nil, fmt.Errorf("line %d: %s set twice", i+1, key)
+		}
+		values[key] = v
+	}
+	return values, nil
+}
+
+// tomlValue returns the value of a TOML string, integer or boolean, followed by an optional comment
+func tomlValue(s string) (string, error) {
+	var value, rest string
+
+	switch {
+	case strings.HasPrefix(s, `"`):
+		end := closingQuote(s)
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string")
+		}
+		v, err := strconv.Unquote(s[:end+1])
+		if err != nil {
+			return "", fmt.Errorf("invalid string %s", s[:end+1])
+		}
+		value, rest = v, s[end+1:]
+
+	case strings.HasPrefix(s, "'"):
+		end := strings.Index(s[1:], "'")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string")
+		}
+		value, rest = s[1:end+1], s[end+2:]
+
+	default:
+		s, _, _ = strings.Cut(s, "#")
+		s = strings.TrimSpace(s)
+
+		switch s {
+		case "true", "false":
+			return s, nil
+		}
+		n, err := strconv.ParseInt(strings.ReplaceAll(s, "_", ""), 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("value must be a string, an integer or a boolean: given %s", s)
+		}
+		return strconv.FormatInt(n, 10), nil
+	}
+
+	if rest = strings.TrimSpace(rest); rest != "" && !strings.HasPrefix(rest, "#") {
+		return "", fmt.Errorf("unexpected %s after the value", rest)
+	}
+	return value, nil
+}
+
+// closingQuote returns the index of the double quote closing the basic string s starts with, -1 if there is none
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,276 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"mars/internal/render"
+	"os"
+	"strings"
+)
+
+// webAPIFlagName names the flag selecting the web API when no command is given
+const webAPIFlagName = "webapi"
+
+// command is a command of the mars binary with the flags it understands
+type command struct {
+	name     string
+	summary  string
+	mode     OpMode
+	defaults func(cfg *Config) // command specific defaults, applied before the flags are registered
+	flags    func(flags *flag.FlagSet, cfg *Config)
+}
+
+// commands lists the commands of the mars binary in the order of the help text
+var commands = []command{
+	{
+		name:    CommandRun,
+		summary: "Run a mission, or a batch of missions, and write the final position of every rover",
+		mode:    ModeCLI,
+		flags: func(flags *flag.FlagSet, cfg *Config) {
+			addInputFlags(flags, cfg)
+			addBatchFlags(flags, cfg)
+			addSimulationFlags(flags, cfg)
+			addOutputFlags(flags, cfg)
+			addLimitFlags(flags, cfg)
+		},
+	},
+	{
+		name:    CommandValidate,
+		summary: "Dry-run a mission, parsing and simulating it without writing results, and report every problem found",
+		mode:    ModeCLI,
+		defaults: func(cfg *Config) {
+			cfg.AllErrors = true
+		},
+		flags: func(flags *flag.FlagSet, cfg *Config) {
+			addInputFlags(flags, cfg)
+			addSimulationFlags(flags, cfg)
+			addLimitFlags(flags, cfg)
+		},
+	},
+	{
+		name:    CommandRender,
+		summary: "Run a mission and present its outcome, as a table by default",
+		mode:    ModeCLI,
+		defaults: func(cfg *Config) {
+			cfg.OutputFormat = render.FormatTable
+		},
+		flags: func(flags *flag.FlagSet, cfg *Config) {
+			addInputFlags(flags, cfg)
+			addSimulationFlags(flags, cfg)
+			addOutputFlags(flags, cfg)
+			addLimitFlags(flags, cfg)
+		},
+	},
+	{
+		name:    CommandServe,
+		summary: "Start the web API, the simulation flags giving the defaults of every request",
+		mode:    ModeWebAPI,
+		flags: func(flags *flag.FlagSet, cfg *Config) {
+			addServerFlags(flags, cfg)
+			addSimulationFlags(flags, cfg)
+			addParsingFlags(flags, cfg)
+			addLimitFlags(flags, cfg)
+		},
+	},
+	{
+		name:    CommandGenerate,
+		summary: "Write random missions, e.g. to benchmark or stress the other commands",
+		mode:    ModeCLI,
+		flags: func(flags *flag.FlagSet, cfg *Config) {
+			addGeneratorFlags(flags, cfg)
+			addLimitFlags(flags, cfg)
+		},
+	},
+}
+
+// ParseArgs returns a pointer to a new Config struct for the command named by the first of the user provided args, see LoadArgs
+func ParseArgs(args []string) (*Config, error) {
+	return LoadArgs(args, os.LookupEnv)
+}
+
+// LoadArgs returns a pointer to a new Config struct for the command named by args[0], its flags following it. Like Load, the flags are merged over
+// the config file and the MARS_* environment variables and the result is validated. Args not starting with a command are handed over to Load.
+// Asking for help prints it and returns an error wrapping flag.ErrHelp
+func LoadArgs(args []string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	if len(args) == 0 {
+		return Load(args, lookupEnv)
+	}
+
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		return nil, help(os.Stderr, args[1:])
+	}
+
+	if strings.HasPrefix(args[0], "-") {
+		return Load(args, lookupEnv)
+	}
+
+	c, ok := lookupCommand(args[0])
+	if !ok {
+		usage(os.Stderr)
+		return nil, fmt.Errorf("%w: (got %q)", ErrParserCommand, args[0])
+	}
+
+	cfg := Default()
+	cfg.Command = c.name
+	cfg.OpMode = c.mode
+	if c.defaults != nil {
+		c.defaults(cfg)
+	}
+
+	flags, configFile := c.flagSet(cfg)
+	if err := load(flags, configFile, args[1:], lookupEnv); err != nil {
+		return nil, err
+	}
+
+	if flags.NArg() > 0 {
+		return nil, fmt.Errorf("%w: unexpected argument %q", ErrParserInvalidValue, flags.Arg(0))
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// flagSet returns the flags of the command bound to cfg, and the config file flag common to every command
+func (c command) flagSet(cfg *Config) (*flag.FlagSet, *string) {
+	flags := flag.NewFlagSet("mars "+c.name, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: mars %s [flags]\n\n%s.\n\nFlags:\n", c.name, c.summary)
+		flags.PrintDefaults()
+	}
+
+	configFile := addConfigFlag(flags)
+	c.flags(flags, cfg)
+	return flags, configFile
+}
+
+// lookupCommand returns the command with the given name
+func lookupCommand(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+// help writes the help text of the command named in args, or the list of commands, and returns an error wrapping flag.ErrHelp
+func help(w io.Writer, args []string) error {
+	if len(args) > 0 {
+		c, ok := lookupCommand(args[0])
+		if !ok {
+			usage(w)
+			return fmt.Errorf("%w: (got %q)", ErrParserCommand, args[0])
+		}
+
+		flags, _ := c.flagSet(Default())
+		flags.SetOutput(w)
+		flags.Usage()
+	} else {
+		usage(w)
+	}
+	return fmt.Errorf("%w: %w", ErrParserInvalidValue, flag.ErrHelp)
+}
+
+// usage writes the list of commands
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: mars <command> [flags]\n\nCommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-9s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintf(w, "\nRun \"mars help <command>\" for the flags of a command. Without a command, mars runs a mission, or starts the web API given -webapi.\n")
+}
+
+// knownFlag reports whether any command, or the command-less mode, has a flag with the given name. A config file shared by several commands
+// may hold settings the running command does not have
+func knownFlag(name string) bool {
+	if name == webAPIFlagName {
+		return true
+	}
+
+	for _, c := range commands {
+		if flags, _ := c.flagSet(Default()); flags.Lookup(name) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// addConfigFlag registers the flag naming the config file
+func addConfigFlag(flags *flag.FlagSet) *string {
+	return flags.String(configFlag, "", "Config file (.json or .toml) holding flag values by flag name, overridden by MARS_* environment variables and flags")
+}
+
+// addInputFlags registers the flags reading and parsing a mission input
+func addInputFlags(flags *flag.FlagSet, cfg *Config) {
+	flags.StringVar(&cfg.FilePath, "file", cfg.FilePath, "Input file. If not provided, reads from stdin.")
+	addParsingFlags(flags, cfg)
+}
+
+// addParsingFlags registers the flags telling how strictly a mission is parsed
+func addParsingFlags(flags *flag.FlagSet, cfg *Config) {
+	flags.IntVar(&cfg.MinPlateauX, "min-size-x", cfg.MinPlateauX, "Minimum size X for plateau (optional)")
+	flags.IntVar(&cfg.MinPlateauY, "min-size-y", cfg.MinPlateauY, "Minimum size Y for plateau (optional)")
+	flags.BoolVar(&cfg.AllErrors, "all-errors", cfg.AllErrors, "Report every problem found in the input instead of stopping at the first one")
+	flags.BoolVar(&cfg.Strict, "strict", cfg.Strict, "Only accept the original format: no comments, blank lines only as empty commands lines")
+}
+
+// addBatchFlags registers the flags running several missions from one input
+func addBatchFlags(flags *flag.FlagSet, cfg *Config) {
+	flags.BoolVar(&cfg.Batch, "batch", cfg.Batch, "The input holds several missions, each one starting with a \"---\" line, run independently of each other")
+	flags.IntVar(&cfg.Parallel, "parallel", cfg.Parallel, "Number of batch missions run at the same time")
+	flags.DurationVar(&cfg.MissionTimeout, "mission-timeout", cfg.MissionTimeout, "Time limit of every batch mission, e.g. 2s, 0 for none")
+}
+
+// addSimulationFlags registers the flags for simulation policies
+func addSimulationFlags(flags *flag.FlagSet, cfg *Config) {
+	flags.StringVar(&cfg.BoundaryPolicy, "boundary", cfg.BoundaryPolicy, "What happens when a rover reaches the plateau edge: stop, wrap, lost or strict")
+	flags.StringVar(&cfg.CollisionPolicy, "collision", cfg.CollisionPolicy, "What happens when a rover's move is blocked: skip, halt, abort, push or swap")
+	flags.BoolVar(&cfg.Trace, "trace", cfg.Trace, "Print every command applied to each rover before the final positions")
+	flags.StringVar(&cfg.ExecMode, "exec", cfg.ExecMode, "How rovers are run: sequential (one after another) or lockstep (one command per rover per tick)")
+}
+
+// addOutputFlags registers the flags for output
+func addOutputFlags(flags *flag.FlagSet, cfg *Config) {
+	flags.StringVar(&cfg.OutputFormat, "format", cfg.OutputFormat, "Output format: text, json, csv, table, grid, grid-steps, svg, png or gif")
+	flags.IntVar(&cfg.Image.CellSize, "cell-size", cfg.Image.CellSize, "Side of a plateau cell in the png and gif formats, in pixels")
+	flags.DurationVar(&cfg.Image.FrameDelay, "frame-delay", cfg.Image.FrameDelay, "Time every frame of the gif format is shown, e.g. 100ms")
+}
+
+// addLimitFlags registers the flags for mission resource limits
+func addLimitFlags(flags *flag.FlagSet, cfg *Config) {
+	flags.IntVar(&cfg.Limits.PlateauX, "max-plateau-x", cfg.Limits.PlateauX, "Maximum plateau width, 0 for no limit")
+	flags.IntVar(&cfg.Limits.PlateauY, "max-plateau-y", cfg.Limits.PlateauY, "Maximum plateau height, 0 for no limit")
+	flags.IntVar(&cfg.Limits.Obstacles, "max-obstacles", cfg.Limits.Obstacles, "Maximum number of obstacles on the plateau, 0 for no limit")
+	flags.IntVar(&cfg.Limits.Rovers, "max-rovers", cfg.Limits.Rovers, "Maximum number of rovers in a mission, 0 for no limit")
+	flags.IntVar(&cfg.Limits.RoverCommands, "max-rover-commands", cfg.Limits.RoverCommands, "Maximum number of commands given to a single rover, 0 for no limit")
+	flags.IntVar(&cfg.Limits.TotalCommands, "max-commands", cfg.Limits.TotalCommands, "Maximum number of commands given to all the rovers of a mission, 0 for no limit")
+}
+
+// addServerFlags registers the flags for the web API server
+func addServerFlags(flags *flag.FlagSet, cfg *Config) {
+	flags.StringVar(&cfg.SrvAddr, "addr", cfg.SrvAddr, "port for webapi server")
+	flags.DurationVar(&cfg.StreamDelay, "stream-delay", cfg.StreamDelay, "Default pause after every rover step when streaming a mission, e.g. 200ms")
+	flags.DurationVar(&cfg.Timeouts.ReadHeader, "read-header-timeout", cfg.Timeouts.ReadHeader, "Maximum time to read request headers, 0 for none")
+	flags.DurationVar(&cfg.Timeouts.Read, "read-timeout", cfg.Timeouts.Read, "Maximum time to read a whole request, 0 for none")
+	flags.DurationVar(&cfg.Timeouts.Write, "write-timeout", cfg.Timeouts.Write, "Maximum time to write a response, streamed missions excepted, 0 for none")
+	flags.DurationVar(&cfg.Timeouts.Idle, "idle-timeout", cfg.Timeouts.Idle, "Maximum time to keep an idle connection open, 0 for none")
+	flags.DurationVar(&cfg.Timeouts.Shutdown, "shutdown-timeout", cfg.Timeouts.Shutdown, "Maximum time to wait for in-flight requests when stopping, 0 for no limit")
+	flags.DurationVar(&cfg.Timeouts.Session, "session-timeout", cfg.Timeouts.Session, "Time a mission session is kept once no request uses it, 0 to keep it until deleted")
+}
+
+// addGeneratorFlags registers the flags shaping generated missions
+func addGeneratorFlags(flags *flag.FlagSet, cfg *Config) {
+	flags.IntVar(&cfg.Generate.Missions, "missions", cfg.Generate.Missions, "Number of missions written, more than one makes a batch input")
+	flags.IntVar(&cfg.Generate.PlateauX, "plateau-x", cfg.Generate.PlateauX, "X coordinate of the upper-right corner of every plateau")
+	flags.IntVar(&cfg.Generate.PlateauY, "plateau-y", cfg.Generate.PlateauY, "Y coordinate of the upper-right corner of every plateau")
+	flags.IntVar(&cfg.Generate.Rovers, "rovers", cfg.Generate.Rovers, "Number of rovers of every mission")
+	flags.IntVar(&cfg.Generate.Commands, "commands", cfg.Generate.Commands, "Number of commands given to every rover")
+	flags.Uint64Var(&cfg.Generate.Seed, "seed", cfg.Generate.Seed, "Seed of the random missions, the same seed always gives the same missions, 0 for a random one")
+	flags.IntVar(&cfg.MinPlateauX, "min-size-x", cfg.MinPlateauX, "Minimum size X for plateau the missions are checked against")
+	flags.IntVar(&cfg.MinPlateauY, "min-size-y", cfg.MinPlateauY, "Minimum size Y for plateau the missions are checked against")
+}
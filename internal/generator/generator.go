@@ -0,0 +1,67 @@
+package generator
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"mars/internal/config"
+	"math"
+	"math/rand/v2"
+)
+
+// headings a generated rover may start with
+var headings = []string{"N", "E", "S", "W"}
+
+// commands is the alphabet of generated commands, moving forward more often than turning so the rovers travel
+const commands = "LRMMM"
+
+// Write writes random missions shaped by g in the text input format, starting every mission with a "---" batch separator line when there is more than one.
+// Rovers are deployed on distinct cells so every mission can be parsed, and run within the limits the settings were validated against. The same non-zero seed always gives the same missions
+func Write(w io.Writer, g config.Generator) error {
+	seed := g.Seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	rng := rand.New(rand.NewPCG(seed, seed))
+
+	bw := bufio.NewWriter(w)
+	for i := range g.Missions {
+		if g.Missions > 1 {
+			fmt.Fprintf(bw, "--- mission-%d\n", i+1)
+		}
+		writeMission(bw, rng, g)
+	}
+	return bw.Flush()
+}
+
+// writeMission writes the plateau, then the position and commands of every rover
+func writeMission(w *bufio.Writer, rng *rand.Rand, g config.Generator) {
+	fmt.Fprintf(w, "%d %d\n", g.PlateauX, g.PlateauY)
+
+	taken := make(map[[2]int]bool, g.Rovers)
+	for range g.Rovers {
+		var cell [2]int
+		for {
+			cell = [2]int{coordinate(rng, g.PlateauX), coordinate(rng, g.PlateauY)}
+			if !taken[cell] {
+				break
+			}
+		}
+		taken[cell] = true
+
+		fmt.Fprintf(w, "%d %d %s\n", cell[0], cell[1], headings[rng.IntN(len(headings))])
+
+		for range g.Commands {
+			w.WriteByte(commands[rng.IntN(len(commands))])
+		}
+		w.WriteByte('\n')
+	}
+}
+
+// coordinate returns a random coordinate from 0 to upper included, without overflowing on the largest plateau
+func coordinate(rng *rand.Rand, upper int) int {
+	if upper == math.MaxInt {
+		return rng.Int()
+	}
+	return rng.IntN(upper + 1)
+}
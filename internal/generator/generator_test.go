@@ -0,0 +1,82 @@
+package generator
+
+import (
+	"mars/internal/config"
+	"mars/internal/parser"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		generator      config.Generator
+		wantSeparators int
+	}{
+		"ok - single mission": {
+			generator:      config.Generator{Missions: 1, PlateauX: 5, PlateauY: 5, Rovers: 2, Commands: 10, Seed: 7},
+			wantSeparators: 0,
+		},
+		"ok - batch of missions": {
+			generator:      config.Generator{Missions: 3, PlateauX: 8, PlateauY: 4, Rovers: 4, Commands: 20, Seed: 7},
+			wantSeparators: 3,
+		},
+		"ok - every cell taken": {
+			generator:      config.Generator{Missions: 1, PlateauX: 2, PlateauY: 2, Rovers: 9, Commands: 0, Seed: 7},
+			wantSeparators: 0,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var first, second strings.Builder
+			require.NoError(t, Write(&first, tc.generator))
+			require.NoError(t, Write(&second, tc.generator))
+
+			assert.Equal(t, first.String(), second.String(), "same seed, same missions")
+			assert.Equal(t, tc.wantSeparators, strings.Count(first.String(), "--- mission-"))
+
+			// every mission parses back with the rovers asked for
+			missions := strings.Split(first.String(), "---")
+			if tc.wantSeparators > 0 {
+				missions = missions[1:]
+			}
+			for _, m := range missions {
+				if tc.wantSeparators > 0 {
+					_, m, _ = strings.Cut(m, "\n")
+				}
+
+				plateau, instructions, err := parser.New().Parse(m, config.Default())
+				require.NoError(t, err)
+				assert.Equal(t, tc.generator.PlateauX, plateau.MaxX())
+				assert.Equal(t, tc.generator.PlateauY, plateau.MaxY())
+				assert.Len(t, instructions, tc.generator.Rovers)
+				for _, instr := range instructions {
+					assert.Len(t, instr.Commands, tc.generator.Commands)
+				}
+			}
+		})
+	}
+}
+
+func TestWrite_LargestPlateau(t *testing.T) {
+	t.Parallel()
+
+	g := config.Generator{Missions: 1, PlateauX: math.MaxInt, PlateauY: math.MaxInt, Rovers: 3, Commands: 5, Seed: 7}
+
+	var sb strings.Builder
+	require.NoError(t, Write(&sb, g))
+
+	// the mission parses back once the limits are lifted
+	cfg := config.Default()
+	cfg.Limits = config.Limits{}
+	plateau, instructions, err := parser.New().Parse(sb.String(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, math.MaxInt, plateau.MaxX())
+	assert.Len(t, instructions, 3)
+}
@@ -0,0 +1,248 @@
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mars/internal/parser"
+	"mars/internal/rover"
+	"slices"
+	"strings"
+)
+
+// Severity tells how serious a finding is
+type Severity int
+
+const (
+	SeverityInfo    Severity = iota // harmless but worth a look, e.g. commands that do nothing
+	SeverityWarning                 // the mission runs, but not the way it is written, e.g. blocked moves
+	SeverityError                   // the mission cannot run, or a rover does not survive it
+)
+
+// String returns the name of the severity
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText encodes the severity by name
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Finding is a problem found in a mission, either while parsing it or while simulating it
+type Finding struct {
+	Severity Severity `json:"severity"`
+	Line     int      `json:"line,omitempty"`   // 1-based line of the input, 0 for findings of the simulation
+	Column   int      `json:"column,omitempty"` // 1-based byte column within the line
+	Rover    string   `json:"rover,omitempty"`  // name or deployment number of the rover, empty for the whole mission
+	Step     int      `json:"step,omitempty"`   // 1-based index of the first command concerned, 0 for the rover as a whole
+	Message  string   `json:"message"`
+	Hint     string   `json:"hint,omitempty"`
+}
+
+// Report lists the findings of a mission, parse findings first then rover by rover in deployment order
+type Report struct {
+	Findings []Finding
+}
+
+// Add appends findings to the report
+func (r *Report) Add(findings ...Finding) {
+	r.Findings = append(r.Findings, findings...)
+}
+
+// Count returns the number of findings of the given severity
+func (r *Report) Count(s Severity) int {
+	var n int
+	for _, f := range r.Findings {
+		if f.Severity == s {
+			n++
+		}
+	}
+	return n
+}
+
+// Valid reports whether the mission can be run as it is, warnings and infos aside
+func (r *Report) Valid() bool {
+	return r.Count(SeverityError) == 0
+}
+
+// WriteText writes one "name:line:col: severity: message" line per finding, followed by its hint if any, and a summary line.
+// The input name stands alone for findings not tied to a line
+func (r *Report) WriteText(w io.Writer, name string) error {
+	var sb strings.Builder
+
+	for _, f := range r.Findings {
+		sb.WriteString(name)
+		if f.Line > 0 {
+			fmt.Fprintf(&sb, ":%d:%d", f.Line, f.Column)
+		}
+		fmt.Fprintf(&sb, ": %s: ", f.Severity)
+
+		switch {
+		case f.Rover != "" && f.Step > 0:
+			fmt.Fprintf(&sb, "rover %s step %d: ", f.Rover, f.Step)
+		case f.Rover != "":
+			fmt.Fprintf(&sb, "rover %s: ", f.Rover)
+		}
+		sb.WriteString(f.Message)
+		sb.WriteByte('\n')
+
+		if f.Hint != "" {
+			fmt.Fprintf(&sb, "\thint: %s\n", f.Hint)
+		}
+	}
+
+	if len(r.Findings) == 0 {
+		fmt.Fprintf(&sb, "%s: ok\n", name)
+	} else {
+		fmt.Fprintf(&sb, "%s: %s, %s, %s\n", name,
+			plural(r.Count(SeverityError), "error"), plural(r.Count(SeverityWarning), "warning"), plural(r.Count(SeverityInfo), "info"))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// plural returns the count followed by the noun, in the plural unless the count is one. Infos stay infos
+func plural(n int, noun string) string {
+	if n == 1 || noun == "info" {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// ParseError returns the error findings of a mission that could not be parsed, one per diagnostic when err holds parser.Diagnostics
+func ParseError(err error) []Finding {
+	var diags parser.Diagnostics
+	if !errors.As(err, &diags) {
+		return []Finding{{Severity: SeverityError, Message: err.Error()}}
+	}
+
+	findings := make([]Finding, 0, len(diags))
+	for _, d := range diags {
+		findings = append(findings, Finding{
+			Severity: SeverityError,
+			Line:     d.Line,
+			Column:   d.Column,
+			Message:  d.Err.Error(),
+			Hint:     d.Hint,
+		})
+	}
+	return findings
+}
+
+// Commands returns an info finding for every run of turns that could be written with fewer commands, such as "LR" doing nothing at all or "LLL" being a single "R"
+func Commands(label, commands string) []Finding {
+	var findings []Finding
+
+	for start := 0; start < len(commands); start++ {
+		if !isTurn(commands[start]) {
+			continue
+		}
+
+		end := start
+		for end < len(commands) && isTurn(commands[end]) {
+			end++
+		}
+
+		run := commands[start:end]
+		if shortest := shortestTurn(run); len(shortest) < len(run) {
+			f := Finding{Severity: SeverityInfo, Rover: label, Step: start + 1}
+			if shortest == "" {
+				f.Message = fmt.Sprintf("commands %d-%d %q leave the rover facing the way it was, they do nothing", start+1, end, run)
+			} else {
+				f.Message = fmt.Sprintf("commands %d-%d %q can be written %q", start+1, end, run, shortest)
+			}
+			findings = append(findings, f)
+		}
+		start = end
+	}
+	return findings
+}
+
+// isTurn reports whether the command turns the rover
+func isTurn(c byte) bool {
+	return rover.Command(c) == rover.CmdLeft || rover.Command(c) == rover.CmdRight
+}
+
+// shortestTurn returns the shortest run of turns with the same effect as the given one
+func shortestTurn(run string) string {
+	var quarters int
+	for i := range len(run) {
+		if rover.Command(run[i]) == rover.CmdRight {
+			quarters++
+		} else {
+			quarters--
+		}
+	}
+
+	switch (quarters%4 + 4) % 4 {
+	case 1:
+		return string(rover.CmdRight)
+	case 2:
+		// both ways take two turns, keep the one written first
+		return run[:1] + run[:1]
+	case 3:
+		return string(rover.CmdLeft)
+	default:
+		return ""
+	}
+}
+
+// Result returns the findings of a simulated rover, its trace recorded: a warning for every move that was blocked, an error if the rover was lost or aborted and a warning if it was halted
+func Result(r rover.RoverResult) []Finding {
+	label := r.Label()
+	var findings []Finding
+
+	for _, step := range r.Trace {
+		var message string
+		switch step.Outcome {
+		case rover.OutcomeBlockedByBoundary:
+			message = fmt.Sprintf("move blocked by the edge of the plateau, the rover stays at %s", step.Before.String())
+		case rover.OutcomeBlockedByRover:
+			message = fmt.Sprintf("move blocked by another rover, the rover stays at %s", step.Before.String())
+		case rover.OutcomeBlockedByObstacle:
+			message = fmt.Sprintf("move blocked by an obstacle, the rover stays at %s", step.Before.String())
+		case rover.OutcomeLost:
+			findings = append(findings, Finding{Severity: SeverityError, Rover: label, Step: step.Step, Message: fmt.Sprintf("rover drives off the plateau from %s and is lost", step.Before.String())})
+			continue
+		default:
+			continue
+		}
+		findings = append(findings, Finding{Severity: SeverityWarning, Rover: label, Step: step.Step, Message: message})
+	}
+
+	switch r.Status {
+	case rover.StatusHalted:
+		findings = append(findings, Finding{Severity: SeverityWarning, Rover: label, Message: fmt.Sprintf("rover halts at %s once blocked, its remaining commands are not run", r.Position.String())})
+	case rover.StatusAborted:
+		findings = append(findings, Finding{Severity: SeverityError, Rover: label, Message: fmt.Sprintf("rover aborts the mission at %s: %v", r.Position.String(), r.Err)})
+	}
+
+	return findings
+}
+
+// SortByStep orders the findings of a single rover by step, findings about the rover as a whole last
+func SortByStep(findings []Finding) {
+	slices.SortStableFunc(findings, func(a, b Finding) int {
+		switch {
+		case a.Step == b.Step:
+			return 0
+		case a.Step == 0:
+			return 1
+		case b.Step == 0:
+			return -1
+		default:
+			return a.Step - b.Step
+		}
+	})
+}
@@ -0,0 +1,110 @@
+package lint
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommands(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		commands string
+		want     []string
+	}{
+		"ok - nothing to simplify": {
+			commands: "LMRMLLMRRM",
+			want:     nil,
+		},
+		"ok - turns that cancel out": {
+			commands: "MLRMRRRRM",
+			want: []string{
+				`commands 2-3 "LR" leave the rover facing the way it was, they do nothing`,
+				`commands 5-8 "RRRR" leave the rover facing the way it was, they do nothing`,
+			},
+		},
+		"ok - turns with a shorter way": {
+			commands: "LLLMRRRLLMRLRR",
+			want: []string{
+				`commands 1-3 "LLL" can be written "R"`,
+				`commands 5-9 "RRRLL" can be written "R"`,
+				`commands 11-14 "RLRR" can be written "RR"`,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			for _, f := range Commands("1", tc.commands) {
+				assert.Equal(t, SeverityInfo, f.Severity)
+				assert.Equal(t, "1", f.Rover)
+				got = append(got, f.Message)
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestReport_WriteText(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		findings []Finding
+		want     string
+	}{
+		"ok - no finding": {
+			want: "mission.txt: ok\n",
+		},
+		"ok - findings of every kind": {
+			findings: []Finding{
+				{Severity: SeverityError, Line: 2, Column: 5, Message: "invalid direction", Hint: "headings are N, E, S and W"},
+				{Severity: SeverityWarning, Rover: "alpha", Step: 3, Message: "move blocked"},
+				{Severity: SeverityWarning, Rover: "2", Message: "rover halts"},
+				{Severity: SeverityInfo, Message: "mission note"},
+			},
+			want: "mission.txt:2:5: error: invalid direction\n" +
+				"\thint: headings are N, E, S and W\n" +
+				"mission.txt: warning: rover alpha step 3: move blocked\n" +
+				"mission.txt: warning: rover 2: rover halts\n" +
+				"mission.txt: info: mission note\n" +
+				"mission.txt: 1 error, 2 warnings, 1 info\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			report := &Report{}
+			report.Add(tc.findings...)
+
+			var sb strings.Builder
+			require.NoError(t, report.WriteText(&sb, "mission.txt"))
+			assert.Equal(t, tc.want, sb.String())
+		})
+	}
+}
+
+func TestReport_Valid(t *testing.T) {
+	t.Parallel()
+
+	report := &Report{}
+	report.Add(Finding{Severity: SeverityWarning}, Finding{Severity: SeverityInfo})
+	assert.True(t, report.Valid())
+
+	report.Add(ParseError(errors.New("mission must declare at least one rover"))...)
+	assert.False(t, report.Valid())
+	assert.Equal(t, 1, report.Count(SeverityError))
+}
+
+func TestFinding_JSON(t *testing.T) {
+	t.Parallel()
+
+	got, err := json.Marshal(Finding{Severity: SeverityWarning, Rover: "1", Step: 2, Message: "move blocked"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"severity":"warning","rover":"1","step":2,"message":"move blocked"}`, string(got))
+}
@@ -0,0 +1,278 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mars/internal/rover"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ContentType is the media type of the text exposition format written by WriteTo
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the request latency histogram buckets
+var DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Metrics collects the web API and rover counters and writes them in the Prometheus text exposition format.
+// It is an EventSink so the rover counters come straight from MissionControl events. All methods are safe for concurrent use
+type Metrics struct {
+	requests *counterVec // HTTP requests by status code
+	latency  *histogram  // HTTP request latency in seconds
+
+	missions        atomic.Uint64 // missions executed successfully
+	rovers          atomic.Uint64 // rovers placed on a plateau
+	commands        atomic.Uint64 // rover commands processed
+	boundaryBlocked atomic.Uint64 // moves blocked by the edge of the plateau
+	collisions      *counterVec   // moves blocked by another rover or an obstacle, by blocker
+}
+
+// New returns a Metrics with every counter at zero
+func New() *Metrics {
+	collisions := newCounterVec()
+	collisions.add("rover", 0)
+	collisions.add("obstacle", 0)
+
+	return &Metrics{
+		requests:   newCounterVec(),
+		latency:    newHistogram(DefaultLatencyBuckets),
+		collisions: collisions,
+	}
+}
+
+// ObserveRequest records a served HTTP request with its status code and how long it took
+func (m *Metrics) ObserveRequest(code int, d time.Duration) {
+	m.requests.add(strconv.Itoa(code), 1)
+	m.latency.observe(d.Seconds())
+}
+
+// MissionExecuted records a mission that ran to completion
+func (m *Metrics) MissionExecuted() {
+	m.missions.Add(1)
+}
+
+// HandleEvent updates the rover counters. Every command processed emits exactly one of: a turn, a move of the rover itself, a rejected move or, for the command that lost or aborted a rover, its RoverFinished event
+func (m *Metrics) HandleEvent(e rover.Event) {
+	switch ev := e.(type) {
+	case rover.RoverPlaced:
+		m.rovers.Add(1)
+
+	case rover.RoverTurned:
+		m.commands.Add(1)
+
+	case rover.RoverMoved:
+		// rovers pushed or swapped did not process a command of their own
+		if ev.MovedBy == 0 {
+			m.commands.Add(1)
+		}
+
+	case rover.MoveRejected:
+		m.commands.Add(1)
+		m.blocked(ev.Reason)
+
+	case rover.RoverFinished:
+		switch {
+		case ev.Status == rover.StatusLost:
+			m.commands.Add(1)
+		case ev.Status == rover.StatusAborted && ev.Err != nil:
+			m.commands.Add(1)
+			m.blocked(ev.Err)
+		}
+	}
+}
+
+// blocked counts a move blocked for the given reason
+func (m *Metrics) blocked(reason error) {
+	switch {
+	case errors.Is(reason, rover.ErrPositionOutOfBounds), errors.Is(reason, rover.ErrScentProtected):
+		m.boundaryBlocked.Add(1)
+	case errors.Is(reason, rover.ErrRoverCollision):
+		m.collisions.add("rover", 1)
+	case errors.Is(reason, rover.ErrObstacleCollision):
+		m.collisions.add("obstacle", 1)
+	}
+}
+
+// Middleware records the status code and latency of every request served by next
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sr := &statusRecorder{ResponseWriter: w, code: http.StatusOK}
+
+		next.ServeHTTP(sr, r)
+
+		m.ObserveRequest(sr.code, time.Since(start))
+	})
+}
+
+// Handler serves the metrics in the text exposition format
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		if _, err := m.WriteTo(w); err != nil {
+			http.Error(w, "An internal server error occurred.", http.StatusInternalServerError)
+		}
+	})
+}
+
+// WriteTo writes every metric in the Prometheus text exposition format
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	ew := &errWriter{w: w}
+
+	ew.header("mars_http_requests_total", "Number of HTTP requests served, by status code.", "counter")
+	m.requests.write(ew, "mars_http_requests_total", "code")
+
+	ew.header("mars_http_request_duration_seconds", "Time taken to serve HTTP requests.", "histogram")
+	m.latency.write(ew, "mars_http_request_duration_seconds")
+
+	ew.header("mars_missions_total", "Number of missions executed to completion.", "counter")
+	ew.printf("mars_missions_total %d\n", m.missions.Load())
+
+	ew.header("mars_rovers_total", "Number of rovers placed on a plateau.", "counter")
+	ew.printf("mars_rovers_total %d\n", m.rovers.Load())
+
+	ew.header("mars_commands_total", "Number of rover commands processed.", "counter")
+	ew.printf("mars_commands_total %d\n", m.commands.Load())
+
+	ew.header("mars_moves_blocked_boundary_total", "Number of moves blocked by the edge of the plateau.", "counter")
+	ew.printf("mars_moves_blocked_boundary_total %d\n", m.boundaryBlocked.Load())
+
+	ew.header("mars_moves_blocked_collision_total", "Number of moves blocked by another rover or an obstacle, by blocker.", "counter")
+	m.collisions.write(ew, "mars_moves_blocked_collision_total", "blocker")
+
+	return ew.n, ew.err
+}
+
+// counterVec is a set of counters told apart by the value of a single label
+type counterVec struct {
+	mu     sync.Mutex
+	values map[string]uint64
+}
+
+func newCounterVec() *counterVec {
+	return &counterVec{
+		values: make(map[string]uint64),
+	}
+}
+
+func (c *counterVec) add(label string, delta uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[label] += delta
+}
+
+// write writes one sample per label value, sorted by label value
+func (c *counterVec) write(ew *errWriter, name, labelName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	labels := make([]string, 0, len(c.values))
+	for label := range c.values {
+		labels = append(labels, label)
+	}
+	slices.Sort(labels)
+
+	for _, label := range labels {
+		ew.printf("%s{%s=%q} %d\n", name, labelName, label, c.values[label])
+	}
+}
+
+// histogram counts observations in cumulative buckets
+type histogram struct {
+	mu      sync.Mutex
+	bounds  []float64 // upper bounds, sorted
+	buckets []uint64  // observations per bucket, not cumulative. The last one is the +Inf bucket
+	sum     float64
+	count   uint64
+}
+
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{
+		bounds:  bounds,
+		buckets: make([]uint64, len(bounds)+1),
+	}
+}
+
+func (h *histogram) observe(v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	i, _ := slices.BinarySearch(h.bounds, v)
+	h.buckets[i]++
+	h.sum += v
+	h.count++
+}
+
+// write writes the cumulative buckets followed by the sum and count
+func (h *histogram) write(ew *errWriter, name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var cumulative uint64
+	for i, bucket := range h.buckets {
+		cumulative += bucket
+
+		le := "+Inf"
+		if i < len(h.bounds) {
+			le = formatFloat(h.bounds[i])
+		}
+		ew.printf("%s_bucket{le=%q} %d\n", name, le, cumulative)
+	}
+
+	ew.printf("%s_sum %s\n", name, formatFloat(h.sum))
+	ew.printf("%s_count %d\n", name, h.count)
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// errWriter keeps the first write error and the number of bytes written so the exposition can be written without checking every call
+type errWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (ew *errWriter) printf(format string, args ...any) {
+	if ew.err != nil {
+		return
+	}
+
+	n, err := fmt.Fprintf(ew.w, format, args...)
+	ew.n += int64(n)
+	ew.err = err
+}
+
+func (ew *errWriter) header(name, help, kind string) {
+	ew.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// statusRecorder keeps the status code written through the ResponseWriter
+type statusRecorder struct {
+	http.ResponseWriter
+	code        int
+	wroteHeader bool
+}
+
+func (sr *statusRecorder) WriteHeader(code int) {
+	if !sr.wroteHeader {
+		sr.code = code
+		sr.wroteHeader = true
+	}
+	sr.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap lets http.ResponseController reach the underlying ResponseWriter, streamed missions flush through it
+func (sr *statusRecorder) Unwrap() http.ResponseWriter {
+	return sr.ResponseWriter
+}
@@ -0,0 +1,175 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"mars/internal/rover"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics_HandleEvent(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		opts         []rover.Option
+		input        string // rover lines on a 5 x 5 plateau with a rock at 2 2, "x y heading commands" one rover per line
+		wantRovers   uint64
+		wantCommands uint64
+		wantBoundary uint64
+		wantRover    uint64
+		wantObstacle uint64
+	}{
+		"ok - turns, moves and blocked moves": {
+			input:        "5 4 N RMLMM\n2 1 N M\n2 0 N M",
+			wantRovers:   3,
+			wantCommands: 7,
+			wantBoundary: 2,
+			wantRover:    1,
+			wantObstacle: 1,
+		},
+		"ok - pushed rover does not count a command": {
+			opts:         []rover.Option{rover.WithCollisionPolicy(rover.PushOnCollision{})},
+			input:        "1 0 N\n0 0 E M",
+			wantRovers:   2,
+			wantCommands: 1,
+		},
+		"ok - lost rover counts its last command": {
+			opts:         []rover.Option{rover.WithBoundaryPolicy(rover.LoseOffEdge{})},
+			input:        "0 0 S MM",
+			wantRovers:   1,
+			wantCommands: 1,
+		},
+		"ok - strict boundary counts a blocked move": {
+			opts:         []rover.Option{rover.WithBoundaryPolicy(rover.AbortAtEdge{})},
+			input:        "0 0 S RM",
+			wantRovers:   1,
+			wantCommands: 2,
+			wantBoundary: 1,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			m := New()
+
+			plateau, err := rover.NewPlateau(5, 5, 2, 2)
+			require.NoError(t, err)
+			require.NoError(t, plateau.AddObstacle(rover.NewObstacle(rover.Rock, rover.NewCoordinates(2, 2))))
+
+			mc, err := rover.NewMissionControl(plateau, append(tc.opts, rover.WithEventSink(m))...)
+			require.NoError(t, err)
+
+			_, err = mc.Simulate(context.Background(), &rover.MissionControlInput{Instructions: instructions(t, plateau, tc.input)})
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.wantRovers, m.rovers.Load(), "rovers")
+			assert.Equal(t, tc.wantCommands, m.commands.Load(), "commands")
+			assert.Equal(t, tc.wantBoundary, m.boundaryBlocked.Load(), "blocked by boundary")
+			assert.Equal(t, tc.wantRover, m.collisions.values["rover"], "blocked by rover")
+			assert.Equal(t, tc.wantObstacle, m.collisions.values["obstacle"], "blocked by obstacle")
+		})
+	}
+}
+
+func TestMetrics_WriteTo(t *testing.T) {
+	t.Parallel()
+
+	m := New()
+	m.ObserveRequest(http.StatusOK, 20*time.Millisecond)
+	m.ObserveRequest(http.StatusOK, 3*time.Second)
+	m.ObserveRequest(http.StatusBadRequest, 20*time.Second)
+	m.MissionExecuted()
+	m.HandleEvent(rover.RoverPlaced{RoverID: 1})
+	m.HandleEvent(rover.MoveRejected{RoverID: 1, Reason: rover.ErrRoverCollision})
+
+	var sb strings.Builder
+	n, err := m.WriteTo(&sb)
+	require.NoError(t, err)
+	assert.Equal(t, int64(sb.Len()), n)
+
+	want := `# HELP mars_http_requests_total Number of HTTP requests served, by status code.
+# TYPE mars_http_requests_total counter
+mars_http_requests_total{code="200"} 2
+mars_http_requests_total{code="400"} 1
+# HELP mars_http_request_duration_seconds Time taken to serve HTTP requests.
+# TYPE mars_http_request_duration_seconds histogram
+mars_http_request_duration_seconds_bucket{le="0.005"} 0
+mars_http_request_duration_seconds_bucket{le="0.01"} 0
+mars_http_request_duration_seconds_bucket{le="0.025"} 1
+mars_http_request_duration_seconds_bucket{le="0.05"} 1
+mars_http_request_duration_seconds_bucket{le="0.1"} 1
+mars_http_request_duration_seconds_bucket{le="0.25"} 1
+mars_http_request_duration_seconds_bucket{le="0.5"} 1
+mars_http_request_duration_seconds_bucket{le="1"} 1
+mars_http_request_duration_seconds_bucket{le="2.5"} 1
+mars_http_request_duration_seconds_bucket{le="5"} 2
+mars_http_request_duration_seconds_bucket{le="10"} 2
+mars_http_request_duration_seconds_bucket{le="+Inf"} 3
+mars_http_request_duration_seconds_sum 23.02
+mars_http_request_duration_seconds_count 3
+# HELP mars_missions_total Number of missions executed to completion.
+# TYPE mars_missions_total counter
+mars_missions_total 1
+# HELP mars_rovers_total Number of rovers placed on a plateau.
+# TYPE mars_rovers_total counter
+mars_rovers_total 1
+# HELP mars_commands_total Number of rover commands processed.
+# TYPE mars_commands_total counter
+mars_commands_total 1
+# HELP mars_moves_blocked_boundary_total Number of moves blocked by the edge of the plateau.
+# TYPE mars_moves_blocked_boundary_total counter
+mars_moves_blocked_boundary_total 0
+# HELP mars_moves_blocked_collision_total Number of moves blocked by another rover or an obstacle, by blocker.
+# TYPE mars_moves_blocked_collision_total counter
+mars_moves_blocked_collision_total{blocker="obstacle"} 0
+mars_moves_blocked_collision_total{blocker="rover"} 1
+`
+	assert.Equal(t, want, sb.String())
+}
+
+func TestMetrics_Middleware(t *testing.T) {
+	t.Parallel()
+
+	m := New()
+	handler := m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+
+	for _, path := range []string{"/", "/", "/missing"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	assert.Equal(t, map[string]uint64{"200": 2, "404": 1}, m.requests.values)
+	assert.Equal(t, uint64(3), m.latency.count)
+}
+
+// instructions builds one rover instruction per "x y heading [commands]" line
+func instructions(t *testing.T, plateau *rover.Plateau, input string) []rover.RoverInstruction {
+	t.Helper()
+
+	headings := map[string]rover.Direction{"N": rover.N, "E": rover.E, "S": rover.S, "W": rover.W}
+
+	var out []rover.RoverInstruction
+	for _, line := range strings.Split(input, "\n") {
+		var x, y int
+		var heading, commands string
+		_, _ = fmt.Sscan(line, &x, &y, &heading, &commands)
+
+		position, err := rover.NewPosition(plateau, rover.NewCoordinates(x, y), headings[heading])
+		require.NoError(t, err)
+
+		out = append(out, rover.RoverInstruction{InitialPosition: position, Commands: commands})
+	}
+	return out
+}
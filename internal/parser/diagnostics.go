@@ -0,0 +1,196 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"mars/internal/rover"
+	"strings"
+	"unicode"
+)
+
+// Diagnostic locates a problem found while parsing a mission
+type Diagnostic struct {
+	Line   int    // 1-based line in the input, 0 when the problem is not tied to a line
+	Column int    // 1-based byte column within the line
+	Text   string // offending text, empty when something is missing
+	Hint   string // suggestion on how to fix the problem, if any
+	Err    error
+}
+
+// Error implements the error interface as "line:column: message", or just the message when the line is unknown
+func (d Diagnostic) Error() string {
+	if d.Line == 0 {
+		return d.Err.Error()
+	}
+	return fmt.Sprintf("%d:%d: %v", d.Line, d.Column, d.Err)
+}
+
+func (d Diagnostic) Unwrap() error {
+	return d.Err
+}
+
+// Diagnostics is the error returned by Parser.Parse, holding the first problem found or all of them when config.AllErrors is set
+type Diagnostics []Diagnostic
+
+// Error implements the error interface, one diagnostic per line
+func (ds Diagnostics) Error() string {
+	msgs := make([]string, 0, len(ds))
+	for _, d := range ds {
+		msgs = append(msgs, d.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap makes errors.Is and errors.As look into every diagnostic
+func (ds Diagnostics) Unwrap() []error {
+	errs := make([]error, 0, len(ds))
+	for _, d := range ds {
+		errs = append(errs, d)
+	}
+	return errs
+}
+
+// Offset returns a copy of the diagnostics with their lines moved down by n, for input parsed on its own out of a larger document. Diagnostics not tied to a line keep no line
+func (ds Diagnostics) Offset(n int) Diagnostics {
+	shifted := make(Diagnostics, 0, len(ds))
+	for _, d := range ds {
+		if d.Line > 0 {
+			d.Line += n
+		}
+		shifted = append(shifted, d)
+	}
+	return shifted
+}
+
+// hints are matched in order against the cause of a diagnostic, the first match wins
+var hints = []struct {
+	err  error
+	hint string
+}{
+	{ErrParseInvalidFormat, "a mission is a plateau line followed by a position line and a commands line per rover"},
+	{ErrParsePlateauFormat, `the plateau line holds the upper-right coordinates, e.g. "5 5"`},
+	{ErrParsePlateauX, "plateau coordinates must be whole numbers"},
+	{ErrParsePlateauY, "plateau coordinates must be whole numbers"},
+	{rover.ErrPlateauTooSmall, "use a bigger plateau or lower the minimum with -min-size-x and -min-size-y"},
+	{ErrParsePositionFormat, `a position line is "x y heading", optionally prefixed with "name:", e.g. "1 2 N"`},
+	{ErrParsePositionX, "coordinates must be whole numbers"},
+	{ErrParsePositionY, "coordinates must be whole numbers"},
+	{ErrParseInvalidDirection, "headings are N, E, S and W"},
+	{ErrParseInvalidCommand, "commands are L (turn left), R (turn right) and M (move forward)"},
+	{rover.ErrPositionOutOfBounds, "coordinates must lie within the plateau, from 0 0 to its upper-right corner"},
+	{ErrParseObstacleFormat, `obstacles are "ROCK x y", "CRATER x y", "NOGO x y" or "ZONE x1 y1 x2 y2"`},
+	{ErrParseObstacleCoordinate, "coordinates must be whole numbers"},
+	{rover.ErrObstacleOutOfBounds, "obstacles must lie within the plateau"},
+	{ErrParseObstaclePlacement, "move the obstacle lines between the plateau line and the first rover"},
+	{ErrParseRoverName, `names use letters, digits, '-', '_' and '.', e.g. "alpha-1: 1 2 N"`},
+	{ErrParseRoverNumber, `names need at least one letter or symbol, e.g. "r1: 1 2 N"`},
+	{ErrParseDuplicateRoverName, "give each rover its own name"},
+	{ErrParsePlateauTooLarge, "use a smaller plateau or raise the limit with -max-plateau-x and -max-plateau-y"},
+	{ErrParseTooManyObstacles, "merge neighbouring obstacles into zones or raise the limit with -max-obstacles"},
+	{ErrParseTooManyRovers, "split the mission or raise the limit with -max-rovers"},
+	{ErrParseCommandsTooLong, "split the commands or raise the limit with -max-rover-commands"},
+	{ErrParseTooManyCommands, "split the mission or raise the limit with -max-commands"},
+}
+
+// hintFor returns the hint matching err, or an empty string
+func hintFor(err error) string {
+	for _, h := range hints {
+		if errors.Is(err, h.err) {
+			return h.hint
+		}
+	}
+	return ""
+}
+
+// line is a line of the input along with its 1-based number
+type line struct {
+	number int
+	text   string
+}
+
+// splitLines splits the input into numbered lines, dropping blank lines at the start and the end
+func splitLines(input string) []line {
+	raw := strings.Split(input, "\n")
+
+	first, last := 0, len(raw)-1
+	for first <= last && strings.TrimSpace(raw[first]) == "" {
+		first++
+	}
+	for last >= first && strings.TrimSpace(raw[last]) == "" {
+		last--
+	}
+
+	lines := make([]line, 0, last-first+1)
+	for i := first; i <= last; i++ {
+		lines = append(lines, line{number: i + 1, text: raw[i]})
+	}
+	return lines
+}
+
+// token is a whitespace separated field of a line along with its 1-based column
+type token struct {
+	text   string
+	column int
+}
+
+// tokenize splits a line around whitespace like strings.Fields, keeping track of where each field starts
+func tokenize(s string) []token {
+	var tokens []token
+
+	start := -1
+	for i, char := range s {
+		if unicode.IsSpace(char) {
+			if start >= 0 {
+				tokens = append(tokens, token{text: s[start:i], column: start + 1})
+				start = -1
+			}
+			continue
+		}
+
+		if start < 0 {
+			start = i
+		}
+	}
+
+	if start >= 0 {
+		tokens = append(tokens, token{text: s[start:], column: start + 1})
+	}
+	return tokens
+}
+
+// locate attaches the position of the offending token to err
+func locate(tok token, err error) error {
+	return Diagnostic{Column: tok.column, Text: tok.text, Err: err}
+}
+
+// collector gathers the diagnostics of a single Parse call
+type collector struct {
+	all   bool
+	diags Diagnostics
+}
+
+// add records err as found on the given line and reports whether parsing must stop. Errors without a location point at the first non blank character of the line
+func (c *collector) add(l line, err error) bool {
+	d := Diagnostic{Line: l.number, Err: err}
+
+	var located Diagnostic
+	if errors.As(err, &located) {
+		d.Column, d.Text, d.Err = located.Column, located.Text, located.Err
+	} else {
+		trimmed := strings.TrimSpace(l.text)
+		d.Column = strings.Index(l.text, trimmed) + 1
+		d.Text = trimmed
+	}
+
+	d.Hint = hintFor(d.Err)
+	c.diags = append(c.diags, d)
+	return !c.all
+}
+
+// err returns the collected diagnostics, or nil if there are none
+func (c *collector) err() error {
+	if len(c.diags) == 0 {
+		return nil
+	}
+	return c.diags
+}
@@ -0,0 +1,198 @@
+package parser
+
+import (
+	"errors"
+	"mars/internal/config"
+	"mars/internal/rover"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Diagnostics(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		input     string
+		allErrors bool
+		strict    bool
+		limits    *config.Limits
+		wantDiags []Diagnostic // Err holds the sentinel the diagnostic must wrap
+	}{
+		"err - line numbers count leading blank lines": {
+			input: "\n\n5 5\n1 2 N\nLMXM",
+			wantDiags: []Diagnostic{
+				{Line: 5, Column: 3, Text: "X", Err: ErrParseInvalidCommand},
+			},
+		},
+		"err - stops at the first problem by default": {
+			input: "5 5\n1 2 Q\nMM\n9 1 N\nMZ",
+			wantDiags: []Diagnostic{
+				{Line: 2, Column: 5, Text: "Q", Err: ErrParseInvalidDirection},
+			},
+		},
+		"err - all errors collected": {
+			input:     "5 5\n1 2 Q\nMM\n9 1 N\nMZ",
+			allErrors: true,
+			wantDiags: []Diagnostic{
+				{Line: 2, Column: 5, Text: "Q", Err: ErrParseInvalidDirection},
+				{Line: 4, Column: 1, Text: "9", Err: rover.ErrPositionOutOfBounds},
+				{Line: 5, Column: 2, Text: "Z", Err: ErrParseInvalidCommand},
+			},
+		},
+		"err - invalid plateau does not stop collecting": {
+			input:     "5 X\n1 2 N\nQ",
+			allErrors: true,
+			wantDiags: []Diagnostic{
+				{Line: 1, Column: 3, Text: "X", Err: ErrParsePlateauY},
+				{Line: 3, Column: 1, Text: "Q", Err: ErrParseInvalidCommand},
+			},
+		},
+		"err - columns account for the rover name": {
+			input: "5 5\nalpha: 1 X N\nM",
+			wantDiags: []Diagnostic{
+				{Line: 2, Column: 10, Text: "X", Err: ErrParsePositionY},
+			},
+		},
+		"err - duplicate name points at the second rover": {
+			input: "5 5\na: 1 2 N\nM\na: 3 3 E\nM",
+			wantDiags: []Diagnostic{
+				{Line: 4, Column: 1, Text: "a:", Err: ErrParseDuplicateRoverName},
+			},
+		},
+		"err - line numbers count comment lines": {
+			input: "# header\r\n5 5\r\n1 2 N # start\r\nMXM\r\n",
+			wantDiags: []Diagnostic{
+				{Line: 4, Column: 2, Text: "X", Err: ErrParseInvalidCommand},
+			},
+		},
+		"err - obstacle coordinate": {
+			input: "5 5\nROCK 1 Y\n1 2 N\nM",
+			wantDiags: []Diagnostic{
+				{Line: 2, Column: 8, Text: "Y", Err: ErrParseObstacleCoordinate},
+			},
+		},
+		"err - missing commands line points past the end": {
+			input:     "5 5\n1 2 N\nM\n3 3 E",
+			allErrors: true,
+			strict:    true,
+			wantDiags: []Diagnostic{
+				{Line: 5, Column: 1, Text: "", Err: ErrParseInvalidFormat},
+			},
+		},
+		"err - plateau too large": {
+			input:  "20 5\n1 2 N\nM",
+			limits: &config.Limits{PlateauX: 10, PlateauY: 10},
+			wantDiags: []Diagnostic{
+				{Line: 1, Column: 1, Text: "20 5", Err: ErrParsePlateauTooLarge},
+			},
+		},
+		"err - too many obstacles": {
+			input:     "5 5\nROCK 1 1\nZONE 2 2 3 3\nROCK 1 Y\n1 2 N\nM",
+			allErrors: true,
+			limits:    &config.Limits{Obstacles: 1},
+			wantDiags: []Diagnostic{
+				{Line: 3, Column: 1, Text: "ZONE 2 2 3 3", Err: ErrParseTooManyObstacles},
+			},
+		},
+		"err - malformed position after a rover without commands": {
+			input:     "5 5\n1 2 N\n1 2 Q\nM\n3 3 E\n1 2",
+			allErrors: true,
+			wantDiags: []Diagnostic{
+				{Line: 3, Column: 5, Text: "Q", Err: ErrParseInvalidDirection},
+				{Line: 6, Column: 1, Text: "1 2", Err: ErrParsePositionFormat},
+			},
+		},
+		"err - obstacles after the rovers": {
+			input:     "5 5\n1 2 N\nROCK 1 1\n3 3 E\nMM\nZONE 0 0 1 1\n4 4 N",
+			allErrors: true,
+			wantDiags: []Diagnostic{
+				{Line: 3, Column: 1, Text: "ROCK", Err: ErrParseObstaclePlacement},
+				{Line: 6, Column: 1, Text: "ZONE", Err: ErrParseObstaclePlacement},
+			},
+		},
+		"err - too many rovers stops collecting": {
+			input:     "5 5\n1 2 N\n2 2 N\n3 3 Q",
+			allErrors: true,
+			limits:    &config.Limits{Rovers: 2},
+			wantDiags: []Diagnostic{
+				{Line: 4, Column: 1, Text: "3 3 Q", Err: ErrParseTooManyRovers},
+			},
+		},
+		"err - too many commands for a rover": {
+			input:  "5 5\n1 2 N\nMMMM",
+			limits: &config.Limits{RoverCommands: 3},
+			wantDiags: []Diagnostic{
+				{Line: 3, Column: 1, Text: "MMMM", Err: ErrParseCommandsTooLong},
+			},
+		},
+		"err - too many commands in total": {
+			input:  "5 5\n1 2 N\nMM\n2 2 N\nMM",
+			limits: &config.Limits{RoverCommands: 3, TotalCommands: 3},
+			wantDiags: []Diagnostic{
+				{Line: 5, Column: 1, Text: "MM", Err: ErrParseTooManyCommands},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.AllErrors = tc.allErrors
+			cfg.Strict = tc.strict
+			if tc.limits != nil {
+				cfg.Limits = *tc.limits
+			}
+
+			_, _, err := New().Parse(tc.input, cfg)
+
+			var diags Diagnostics
+			require.True(t, errors.As(err, &diags), "want Diagnostics, got %T", err)
+			require.Len(t, diags, len(tc.wantDiags))
+
+			for i, want := range tc.wantDiags {
+				got := diags[i]
+				assert.Equal(t, want.Line, got.Line, "line")
+				assert.Equal(t, want.Column, got.Column, "column")
+				assert.Equal(t, want.Text, got.Text, "text")
+				assert.ErrorIs(t, got, want.Err)
+				assert.NotEmpty(t, got.Hint)
+			}
+		})
+	}
+}
+
+func TestDiagnosticsError(t *testing.T) {
+	t.Parallel()
+
+	diags := Diagnostics{
+		{Line: 2, Column: 5, Err: ErrParseInvalidDirection},
+		{Line: 5, Column: 2, Err: ErrParseInvalidCommand},
+	}
+
+	assert.Equal(t, "2:5: invalid direction given, must be N, E, S, W\n5:2: invalid command character given, must be L, R, M", diags.Error())
+	assert.ErrorIs(t, diags, ErrParseInvalidCommand)
+}
+
+func TestDiagnosticsOffset(t *testing.T) {
+	t.Parallel()
+
+	diags := Diagnostics{
+		{Line: 2, Column: 5, Err: ErrParseInvalidDirection},
+		{Err: ErrParseTooManyRovers},
+	}
+
+	assert.Equal(t, Diagnostics{
+		{Line: 7, Column: 5, Err: ErrParseInvalidDirection},
+		{Err: ErrParseTooManyRovers},
+	}, diags.Offset(5))
+	assert.Equal(t, 2, diags[0].Line)
+}
+
+func TestTokenize(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []token{{text: "1", column: 2}, {text: "22", column: 4}, {text: "N", column: 9}}, tokenize("\t1 22\t  N "))
+	assert.Nil(t, tokenize("   "))
+}
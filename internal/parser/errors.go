@@ -1,15 +1,39 @@
 package parser
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrParseInvalidFormat      = errors.New("must have a plateau line first and pairs of rover lines")
+	ErrParsePlateauFormat      = errors.New("wrong plateau element count, must be X Y")
+	ErrParsePositionFormat     = errors.New("wrong rover position element count, must be x y direction")
+	ErrParsePlateauX           = errors.New("invalid plateau width")
+	ErrParsePlateauY           = errors.New("invalid plateau height")
+	ErrParsePositionX          = errors.New("invalid position given for X coordinate")
+	ErrParsePositionY          = errors.New("invalid position given for Y coordinate")
+	ErrParseInvalidDirection   = errors.New("invalid direction given, must be N, E, S, W")
+	ErrParseInvalidCommand     = errors.New("invalid command character given, must be L, R, M")
+	ErrParseObstacleFormat     = errors.New("wrong obstacle element count, must be KIND x y or ZONE x1 y1 x2 y2")
+	ErrParseObstacleKind       = errors.New("invalid obstacle kind given, must be ROCK, CRATER, NOGO, ZONE")
+	ErrParseObstacleCoordinate = errors.New("invalid obstacle coordinate given")
+	ErrParseObstaclePlacement  = errors.New("obstacles must directly follow the plateau line")
+	ErrParseJSON               = errors.New("invalid JSON mission document")
+	ErrParseJSONPlateau        = errors.New("mission must declare plateau x and y")
+	ErrParseJSONNoRovers       = errors.New("mission must declare at least one rover")
+	ErrParseRoverName          = errors.New("invalid rover name, must only contain letters, digits, '-', '_' or '.'")
+	ErrParseRoverNumber        = errors.New("rover name must not be a number, numbers are kept for the rovers without a name")
+	ErrParseDuplicateRoverName = errors.New("rover name must be unique")
+)
+
+// ErrParseLimit is wrapped by every error of a mission exceeding one of the resource limits of config.Limits
+var ErrParseLimit = errors.New("mission exceeds a resource limit")
 
 var (
-	ErrParseInvalidFormat    = errors.New("must have a plateau line first and pairs of rover lines")
-	ErrParsePlateauFormat    = errors.New("wrong plateau element count, must be X Y")
-	ErrParsePositionFormat   = errors.New("wrong rover position element count, must be x y direction")
-	ErrParsePlateauX         = errors.New("invalid plateau width")
-	ErrParsePlateauY         = errors.New("invalid plateau height")
-	ErrParsePositionX        = errors.New("invalid position given for X coordinate")
-	ErrParsePositionY        = errors.New("invalid position given for Y coordinate")
-	ErrParseInvalidDirection = errors.New("invalid direction given, must be N, E, S, W")
-	ErrParseInvalidCommand   = errors.New("invalid command character given, must be L, R, M")
+	ErrParsePlateauTooLarge  = fmt.Errorf("%w: plateau is too large", ErrParseLimit)
+	ErrParseTooManyObstacles = fmt.Errorf("%w: too many obstacles", ErrParseLimit)
+	ErrParseTooManyRovers    = fmt.Errorf("%w: too many rovers", ErrParseLimit)
+	ErrParseCommandsTooLong  = fmt.Errorf("%w: too many commands for a single rover", ErrParseLimit)
+	ErrParseTooManyCommands  = fmt.Errorf("%w: too many commands in total", ErrParseLimit)
 )
@@ -0,0 +1,254 @@
+package parser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mars/internal/config"
+	"mars/internal/rover"
+	"strings"
+)
+
+// JSONParser decodes a structured mission document into the same plateau and instructions produced by Parser
+type JSONParser struct{}
+
+// missionDocument is the JSON representation of a mission
+type missionDocument struct {
+	Plateau   *plateauDocument   `json:"plateau"`
+	Obstacles []obstacleDocument `json:"obstacles"`
+	Rovers    []roverDocument    `json:"rovers"`
+}
+
+type plateauDocument struct {
+	X *int `json:"x"`
+	Y *int `json:"y"`
+}
+
+// obstacleDocument describes a single cell obstacle at x, y or, for kind "zone", a rectangle from x, y to toX, toY
+type obstacleDocument struct {
+	Kind string `json:"kind"`
+	X    int    `json:"x"`
+	Y    int    `json:"y"`
+	ToX  *int   `json:"toX"`
+	ToY  *int   `json:"toY"`
+}
+
+// sessionDocument describes the plateau of a mission session, rovers are deployed later on one at a time
+type sessionDocument struct {
+	Plateau   *plateauDocument   `json:"plateau"`
+	Obstacles []obstacleDocument `json:"obstacles"`
+}
+
+// commandsDocument holds a batch of commands sent to a rover already deployed
+type commandsDocument struct {
+	Commands string `json:"commands"`
+}
+
+type roverDocument struct {
+	Name     string `json:"name"`
+	X        int    `json:"x"`
+	Y        int    `json:"y"`
+	Heading  string `json:"heading"`
+	Commands string `json:"commands"`
+}
+
+func NewJSON() *JSONParser {
+	return &JSONParser{}
+}
+
+// Parse takes a JSON mission document and returns a Plateau pointer and the rover instructions or an error should the document be malformed or fail validation
+func (p *JSONParser) Parse(input string, cfg *config.Config) (*rover.Plateau, []rover.RoverInstruction, error) {
+	var doc missionDocument
+	if err := decodeStrict(input, &doc); err != nil {
+		return nil, nil, err
+	}
+
+	if doc.Plateau == nil || doc.Plateau.X == nil || doc.Plateau.Y == nil {
+		return nil, nil, ErrParseJSONPlateau
+	}
+
+	if len(doc.Rovers) == 0 {
+		return nil, nil, ErrParseJSONNoRovers
+	}
+
+	plateau, err := newPlateau(doc.Plateau, doc.Obstacles, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	limit := newLimiter(cfg)
+	names := nameRegistry{}
+	instructions := make([]rover.RoverInstruction, 0, len(doc.Rovers))
+	for i, rd := range doc.Rovers {
+		if err := limit.rover(); err != nil {
+			return nil, nil, fmt.Errorf("rover %d: %w", i+1, err)
+		}
+
+		if err := names.add(i+1, rd.Name); err != nil {
+			return nil, nil, fmt.Errorf("rover %d: %w", i+1, err)
+		}
+
+		instruction, err := rd.instruction(plateau)
+		if err != nil {
+			return nil, nil, fmt.Errorf("rover %d: %w", i+1, err)
+		}
+
+		if err := limit.roverCommands(instruction.Commands); err != nil {
+			return nil, nil, fmt.Errorf("rover %d: %w", i+1, err)
+		}
+
+		instructions = append(instructions, instruction)
+	}
+
+	return plateau, instructions, nil
+}
+
+// ParsePlateau takes a JSON session document holding a plateau and its optional obstacles and returns a Plateau pointer or an error should the document be malformed or fail validation
+func (p *JSONParser) ParsePlateau(input string, cfg *config.Config) (*rover.Plateau, error) {
+	var doc sessionDocument
+	if err := decodeStrict(input, &doc); err != nil {
+		return nil, err
+	}
+
+	if doc.Plateau == nil || doc.Plateau.X == nil || doc.Plateau.Y == nil {
+		return nil, ErrParseJSONPlateau
+	}
+
+	return newPlateau(doc.Plateau, doc.Obstacles, cfg)
+}
+
+// ParseRover takes a JSON rover document and returns the instruction deploying it on the given plateau or an error should the document be malformed, fail validation or give the rover more commands than cfg.Limits allows
+func (p *JSONParser) ParseRover(input string, plateau *rover.Plateau, cfg *config.Config) (rover.RoverInstruction, error) {
+	var rd roverDocument
+	if err := decodeStrict(input, &rd); err != nil {
+		return rover.RoverInstruction{}, err
+	}
+
+	instruction, err := rd.instruction(plateau)
+	if err != nil {
+		return rover.RoverInstruction{}, err
+	}
+
+	if err := newLimiter(cfg).roverCommands(instruction.Commands); err != nil {
+		return rover.RoverInstruction{}, err
+	}
+	return instruction, nil
+}
+
+// ParseCommands takes a JSON commands document and returns its validated, upper-cased commands, a batch being limited like the commands of a single rover
+func (p *JSONParser) ParseCommands(input string, cfg *config.Config) (string, error) {
+	var doc commandsDocument
+	if err := decodeStrict(input, &doc); err != nil {
+		return "", err
+	}
+
+	cmds, err := parseCommandsLine(doc.Commands)
+	if err != nil {
+		return "", err
+	}
+
+	if err := newLimiter(cfg).roverCommands(cmds); err != nil {
+		return "", err
+	}
+	return cmds, nil
+}
+
+// decodeStrict decodes the JSON input into v, rejecting unknown fields and anything but whitespace after the document
+func decodeStrict(input string, v any) error {
+	decoder := json.NewDecoder(strings.NewReader(input))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("%w: %v", ErrParseJSON, err)
+	}
+
+	end := decoder.InputOffset()
+	if err := decoder.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: trailing data after the document ending at offset %d", ErrParseJSON, end)
+	}
+	return nil
+}
+
+// newPlateau creates the plateau described by the document and adds its obstacles
+func newPlateau(pd *plateauDocument, obstacles []obstacleDocument, cfg *config.Config) (*rover.Plateau, error) {
+	plateau, err := rover.NewPlateau(*pd.X, *pd.Y, cfg.MinPlateauX, cfg.MinPlateauY)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := newLimiter(cfg)
+	if err := limit.plateau(*pd.X, *pd.Y); err != nil {
+		return nil, err
+	}
+
+	for i, od := range obstacles {
+		if err := limit.obstacle(); err != nil {
+			return nil, fmt.Errorf("obstacle %d: %w", i+1, err)
+		}
+
+		obstacle, err := od.obstacle()
+		if err != nil {
+			return nil, fmt.Errorf("obstacle %d: %w", i+1, err)
+		}
+
+		if err := plateau.AddObstacle(obstacle); err != nil {
+			return nil, fmt.Errorf("obstacle %d: %w", i+1, err)
+		}
+	}
+
+	return plateau, nil
+}
+
+// instruction converts the document into the instruction deploying the rover on the given plateau
+func (rd roverDocument) instruction(plateau *rover.Plateau) (rover.RoverInstruction, error) {
+	if rd.Name != "" {
+		if err := validateRoverName(rd.Name); err != nil {
+			return rover.RoverInstruction{}, err
+		}
+	}
+
+	dir, err := parseDirection(rd.Heading)
+	if err != nil {
+		return rover.RoverInstruction{}, err
+	}
+
+	position, err := rover.NewPosition(plateau, rover.NewCoordinates(rd.X, rd.Y), dir)
+	if err != nil {
+		return rover.RoverInstruction{}, err
+	}
+
+	cmds, err := parseCommandsLine(rd.Commands)
+	if err != nil {
+		return rover.RoverInstruction{}, err
+	}
+
+	return rover.RoverInstruction{
+		Name:            rd.Name,
+		InitialPosition: position,
+		Commands:        cmds,
+	}, nil
+}
+
+// obstacle converts the document into a rover.Obstacle, zones must declare both corners and single cells only one
+func (od obstacleDocument) obstacle() (rover.Obstacle, error) {
+	kind, err := parseObstacleKind(od.Kind)
+	if err != nil {
+		return rover.Obstacle{}, err
+	}
+
+	hasCorner := od.ToX != nil && od.ToY != nil
+	if kind == rover.Zone && !hasCorner {
+		return rover.Obstacle{}, fmt.Errorf("%w: zone must declare toX and toY", ErrParseObstacleFormat)
+	}
+
+	if kind != rover.Zone && (od.ToX != nil || od.ToY != nil) {
+		return rover.Obstacle{}, fmt.Errorf("%w: only zones may declare toX and toY", ErrParseObstacleFormat)
+	}
+
+	from := rover.NewCoordinates(od.X, od.Y)
+	if kind == rover.Zone {
+		return rover.NewZone(from, rover.NewCoordinates(*od.ToX, *od.ToY)), nil
+	}
+	return rover.NewObstacle(kind, from), nil
+}
@@ -0,0 +1,207 @@
+package parser
+
+import (
+	"mars/internal/config"
+	"mars/internal/rover"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONParser_Parse(t *testing.T) {
+	t.Parallel()
+	testPlateau := createTestPlateau(t, 5, 5)
+
+	testCases := map[string]struct {
+		input            string
+		wantPlateau      *rover.Plateau
+		wantInstructions []rover.RoverInstruction
+		wantErr          error
+	}{
+		"ok - nominal case for problem description": {
+			input: `{
+				"plateau": {"x": 5, "y": 5},
+				"rovers": [
+					{"x": 1, "y": 2, "heading": "N", "commands": "LMLMLMLMM"},
+					{"x": 3, "y": 3, "heading": "E", "commands": "MMRMMRMRRM"}
+				]
+			}`,
+			wantPlateau: testPlateau,
+			wantInstructions: []rover.RoverInstruction{
+				*createTestSingleRoverInstruction(t, testPlateau, 1, 2, rover.N, "LMLMLMLMM"),
+				*createTestSingleRoverInstruction(t, testPlateau, 3, 3, rover.E, "MMRMMRMRRM"),
+			},
+			wantErr: nil,
+		},
+		"ok - lower case heading and commands": {
+			input:       `{"plateau": {"x": 5, "y": 5}, "rovers": [{"x": 1, "y": 2, "heading": "n", "commands": "lmr"}]}`,
+			wantPlateau: testPlateau,
+			wantInstructions: []rover.RoverInstruction{
+				*createTestSingleRoverInstruction(t, testPlateau, 1, 2, rover.N, "LMR"),
+			},
+			wantErr: nil,
+		},
+		"ok - obstacles": {
+			input: `{
+				"plateau": {"x": 5, "y": 5},
+				"obstacles": [{"kind": "rock", "x": 2, "y": 2}, {"kind": "zone", "x": 0, "y": 4, "toX": 1, "toY": 5}],
+				"rovers": [{"x": 1, "y": 2, "heading": "N", "commands": "M"}]
+			}`,
+			wantPlateau: func() *rover.Plateau {
+				p := createTestPlateau(t, 5, 5)
+				_ = p.AddObstacle(rover.NewObstacle(rover.Rock, rover.NewCoordinates(2, 2)))
+				_ = p.AddObstacle(rover.NewZone(rover.NewCoordinates(0, 4), rover.NewCoordinates(1, 5)))
+				return p
+			}(),
+			wantInstructions: []rover.RoverInstruction{
+				*createTestSingleRoverInstruction(t, testPlateau, 1, 2, rover.N, "M"),
+			},
+			wantErr: nil,
+		},
+		"ok - named rover": {
+			input:       `{"plateau": {"x": 5, "y": 5}, "rovers": [{"name": "alpha", "x": 1, "y": 2, "heading": "N", "commands": "M"}]}`,
+			wantPlateau: testPlateau,
+			wantInstructions: []rover.RoverInstruction{
+				func() rover.RoverInstruction {
+					ri := *createTestSingleRoverInstruction(t, testPlateau, 1, 2, rover.N, "M")
+					ri.Name = "alpha"
+					return ri
+				}(),
+			},
+		},
+		"err - ErrParseDuplicateRoverName": {
+			input:   `{"plateau": {"x": 5, "y": 5}, "rovers": [{"name": "alpha", "x": 1, "y": 2, "heading": "N"}, {"name": "alpha", "x": 3, "y": 3, "heading": "E"}]}`,
+			wantErr: ErrParseDuplicateRoverName,
+		},
+		"err - ErrParseRoverName": {
+			input:   `{"plateau": {"x": 5, "y": 5}, "rovers": [{"name": "al pha", "x": 1, "y": 2, "heading": "N"}]}`,
+			wantErr: ErrParseRoverName,
+		},
+		"err - ErrParseObstacleFormat - zone without corner": {
+			input:   `{"plateau": {"x": 5, "y": 5}, "obstacles": [{"kind": "zone", "x": 0, "y": 4}], "rovers": [{"x": 1, "y": 2, "heading": "N", "commands": "M"}]}`,
+			wantErr: ErrParseObstacleFormat,
+		},
+		"err - ErrParseObstacleFormat - rock with corner": {
+			input:   `{"plateau": {"x": 5, "y": 5}, "obstacles": [{"kind": "rock", "x": 0, "y": 4, "toX": 1}], "rovers": [{"x": 1, "y": 2, "heading": "N", "commands": "M"}]}`,
+			wantErr: ErrParseObstacleFormat,
+		},
+		"err - ErrParseObstacleKind": {
+			input:   `{"plateau": {"x": 5, "y": 5}, "obstacles": [{"kind": "tree", "x": 0, "y": 4}], "rovers": [{"x": 1, "y": 2, "heading": "N", "commands": "M"}]}`,
+			wantErr: ErrParseObstacleKind,
+		},
+		"err - ErrParseJSON - malformed": {
+			input:   `{"plateau": `,
+			wantErr: ErrParseJSON,
+		},
+		"err - ErrParseJSON - unknown field": {
+			input:   `{"plateau": {"x": 5, "y": 5}, "robots": []}`,
+			wantErr: ErrParseJSON,
+		},
+		"err - ErrParseJSON - second document": {
+			input:   `{"plateau": {"x": 5, "y": 5}, "rovers": [{"x": 1, "y": 2, "heading": "N"}]} {"plateau": {"x": 5, "y": 5}}`,
+			wantErr: ErrParseJSON,
+		},
+		"err - ErrParseJSON - trailing garbage": {
+			input:   `{"plateau": {"x": 5, "y": 5}, "rovers": [{"x": 1, "y": 2, "heading": "N"}]} garbage`,
+			wantErr: ErrParseJSON,
+		},
+		"err - ErrParseJSONPlateau - missing plateau": {
+			input:   `{"rovers": [{"x": 1, "y": 2, "heading": "N", "commands": "M"}]}`,
+			wantErr: ErrParseJSONPlateau,
+		},
+		"err - ErrParseJSONPlateau - missing y": {
+			input:   `{"plateau": {"x": 5}, "rovers": [{"x": 1, "y": 2, "heading": "N", "commands": "M"}]}`,
+			wantErr: ErrParseJSONPlateau,
+		},
+		"err - ErrParseJSONNoRovers": {
+			input:   `{"plateau": {"x": 5, "y": 5}, "rovers": []}`,
+			wantErr: ErrParseJSONNoRovers,
+		},
+		"err - ErrPlateauTooSmall": {
+			input:   `{"plateau": {"x": 1, "y": 1}, "rovers": [{"x": 1, "y": 1, "heading": "N", "commands": "M"}]}`,
+			wantErr: rover.ErrPlateauTooSmall,
+		},
+		"err - ErrParseInvalidDirection": {
+			input:   `{"plateau": {"x": 5, "y": 5}, "rovers": [{"x": 1, "y": 2, "heading": "Q", "commands": "M"}]}`,
+			wantErr: ErrParseInvalidDirection,
+		},
+		"err - ErrPositionOutOfBounds": {
+			input:   `{"plateau": {"x": 5, "y": 5}, "rovers": [{"x": 6, "y": 2, "heading": "N", "commands": "M"}]}`,
+			wantErr: rover.ErrPositionOutOfBounds,
+		},
+		"err - ErrParseInvalidCommand": {
+			input:   `{"plateau": {"x": 5, "y": 5}, "rovers": [{"x": 1, "y": 2, "heading": "N", "commands": "MXM"}]}`,
+			wantErr: ErrParseInvalidCommand,
+		},
+	}
+
+	cfg := config.Default()
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			p := NewJSON()
+
+			gotPlateau, gotInstructions, err := p.Parse(tc.input, cfg)
+
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantPlateau, gotPlateau)
+			assert.Equal(t, tc.wantInstructions, gotInstructions)
+		})
+	}
+}
+
+func TestJSONParser_Session(t *testing.T) {
+	t.Parallel()
+	testPlateau := createTestPlateau(t, 5, 5)
+	p := NewJSON()
+	cfg := config.Default()
+
+	plateau, err := p.ParsePlateau(`{"plateau": {"x": 5, "y": 5}}`, config.Default())
+	require.NoError(t, err)
+	assert.Equal(t, testPlateau, plateau)
+
+	_, err = p.ParsePlateau(`{"plateau": {"x": 5, "y": 5}, "rovers": []}`, config.Default())
+	require.ErrorIs(t, err, ErrParseJSON)
+
+	_, err = p.ParsePlateau(`{"obstacles": []}`, config.Default())
+	require.ErrorIs(t, err, ErrParseJSONPlateau)
+
+	instruction, err := p.ParseRover(`{"x": 1, "y": 2, "heading": "n", "commands": "mr"}`, testPlateau, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, *createTestSingleRoverInstruction(t, testPlateau, 1, 2, rover.N, "MR"), instruction)
+
+	_, err = p.ParseRover(`{"name": "a b", "x": 1, "y": 2, "heading": "N"}`, testPlateau, cfg)
+	require.ErrorIs(t, err, ErrParseRoverName)
+
+	_, err = p.ParseRover(`{"name": "3", "x": 1, "y": 2, "heading": "N"}`, testPlateau, cfg)
+	require.ErrorIs(t, err, ErrParseRoverNumber)
+
+	commands, err := p.ParseCommands(`{"commands": "lmR"}`, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "LMR", commands)
+
+	_, err = p.ParseCommands(`{"commands": "LMX"}`, cfg)
+	require.ErrorIs(t, err, ErrParseInvalidCommand)
+
+	cfg.Limits = config.Limits{PlateauX: 10, PlateauY: 10, Obstacles: 1, RoverCommands: 2}
+
+	_, err = p.ParsePlateau(`{"plateau": {"x": 5, "y": 11}}`, cfg)
+	require.ErrorIs(t, err, ErrParsePlateauTooLarge)
+
+	_, err = p.ParsePlateau(`{"plateau": {"x": 5, "y": 5}, "obstacles": [{"kind": "rock", "x": 1, "y": 1}, {"kind": "rock", "x": 2, "y": 2}]}`, cfg)
+	require.ErrorIs(t, err, ErrParseTooManyObstacles)
+	assert.ErrorContains(t, err, "obstacle 2")
+
+	_, err = p.ParseRover(`{"x": 1, "y": 2, "heading": "N", "commands": "MMM"}`, testPlateau, cfg)
+	require.ErrorIs(t, err, ErrParseCommandsTooLong)
+
+	_, err = p.ParseCommands(`{"commands": "LMR"}`, cfg)
+	require.ErrorIs(t, err, ErrParseCommandsTooLong)
+	require.ErrorIs(t, err, ErrParseLimit)
+}
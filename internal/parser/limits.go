@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"fmt"
+	"mars/internal/config"
+)
+
+// limiter enforces the resource limits of a single mission, counting the obstacles, rovers and commands seen so far
+type limiter struct {
+	limits    config.Limits
+	obstacles int
+	rovers    int
+	commands  int
+}
+
+func newLimiter(cfg *config.Config) *limiter {
+	return &limiter{limits: cfg.Limits}
+}
+
+// plateau checks the upper-right corner of the plateau
+func (l *limiter) plateau(maxX, maxY int) error {
+	if l.limits.PlateauX > 0 && maxX > l.limits.PlateauX {
+		return fmt.Errorf("%w: %d wide, at most %d", ErrParsePlateauTooLarge, maxX, l.limits.PlateauX)
+	}
+
+	if l.limits.PlateauY > 0 && maxY > l.limits.PlateauY {
+		return fmt.Errorf("%w: %d high, at most %d", ErrParsePlateauTooLarge, maxY, l.limits.PlateauY)
+	}
+	return nil
+}
+
+// obstacle counts one more obstacle
+func (l *limiter) obstacle() error {
+	l.obstacles++
+
+	if l.limits.Obstacles > 0 && l.obstacles > l.limits.Obstacles {
+		return fmt.Errorf("%w: at most %d", ErrParseTooManyObstacles, l.limits.Obstacles)
+	}
+	return nil
+}
+
+// rover counts one more rover
+func (l *limiter) rover() error {
+	l.rovers++
+
+	if l.limits.Rovers > 0 && l.rovers > l.limits.Rovers {
+		return fmt.Errorf("%w: at most %d", ErrParseTooManyRovers, l.limits.Rovers)
+	}
+	return nil
+}
+
+// roverCommands counts the commands given to one rover
+func (l *limiter) roverCommands(commands string) error {
+	n := len(commands)
+	l.commands += n
+
+	if l.limits.RoverCommands > 0 && n > l.limits.RoverCommands {
+		return fmt.Errorf("%w: %d, at most %d", ErrParseCommandsTooLong, n, l.limits.RoverCommands)
+	}
+
+	if l.limits.TotalCommands > 0 && l.commands > l.limits.TotalCommands {
+		return fmt.Errorf("%w: at most %d", ErrParseTooManyCommands, l.limits.TotalCommands)
+	}
+	return nil
+}
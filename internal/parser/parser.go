@@ -7,6 +7,7 @@ import (
 
 	"strconv"
 	"strings"
+	"unicode"
 )
 
 type Parser struct{}
@@ -15,37 +16,119 @@ func New() *Parser {
 	return &Parser{}
 }
 
+// Parse takes a mission in the text format and returns a Plateau pointer and the rover instructions. Problems are reported as Diagnostics, holding the first one found or every one of them when cfg.AllErrors is set.
+// Unless cfg.Strict is set, comments starting with '#', blank lines and CRLF line endings are ignored and a rover without commands may leave out its commands line.
+// A mission exceeding one of cfg.Limits stops parsing straight away with an error wrapping ErrParseLimit
 func (p *Parser) Parse(input string, cfg *config.Config) (*rover.Plateau, []rover.RoverInstruction, error) {
-	lines := strings.Split(strings.TrimSpace(input), "\n")
+	lines := splitLines(input)
+	if !cfg.Strict {
+		lines = cleanLines(lines)
+	}
+	c := &collector{all: cfg.AllErrors}
+	limit := newLimiter(cfg)
 
 	// reject inputs that are not one plateau line + n * pair of instruction lines (a pair per rover with a min of 1 pair)
-	if len(lines) < 3 || (len(lines)-1)%2 != 0 {
-		return nil, nil, ErrParseInvalidFormat
+	if len(lines) == 0 || (cfg.Strict && len(lines) < 3) {
+		c.add(missingLine(lines), ErrParseInvalidFormat)
+		return nil, nil, c.err()
 	}
 
-	// parse plateau
-	plateau, err := parsePlateauLine(lines[0], cfg)
-	if err != nil {
-		return nil, nil, err
+	// parse plateau, when it is invalid the remaining lines can still be checked but not against its bounds
+	plateau, err := parsePlateauLine(lines[0].text, cfg)
+	if err != nil && c.add(lines[0], err) {
+		return nil, nil, c.err()
 	}
 
-	// parse rover instructions
-	instructions := make([]rover.RoverInstruction, 0, (len(lines)-1)/2)
-	for i := 1; i < len(lines); i += 2 {
-		positionLine := lines[i]
-		commandsLine := lines[i+1]
+	if plateau != nil {
+		if err := limit.plateau(plateau.MaxX(), plateau.MaxY()); err != nil {
+			c.add(lines[0], err)
+			return nil, nil, c.err()
+		}
+	}
 
-		position, err := parsePositionLine(positionLine, plateau)
-		if err != nil {
-			return nil, nil, err
+	// parse the optional obstacle section directly following the plateau line
+	next := 1
+	for ; next < len(lines) && isObstacleLine(lines[next].text); next++ {
+		if err := limit.obstacle(); err != nil {
+			c.add(lines[next], err)
+			return nil, nil, c.err()
 		}
 
-		cmds, err := parseCommandsLine(commandsLine)
+		obstacle, err := parseObstacleLine(lines[next].text)
 		if err != nil {
-			return nil, nil, err
+			if c.add(lines[next], err) {
+				return nil, nil, c.err()
+			}
+			continue
+		}
+
+		if plateau == nil {
+			continue
+		}
+
+		if err := plateau.AddObstacle(obstacle); err != nil && c.add(lines[next], err) {
+			return nil, nil, c.err()
+		}
+	}
+
+	var rovers []roverLines
+	if cfg.Strict {
+		rovers, err = pairRoverLines(lines[next:])
+	} else {
+		rovers, err = groupRoverLines(lines[next:])
+	}
+
+	if err != nil && (c.add(missingLine(lines), err) || len(rovers) == 0) {
+		return nil, nil, c.err()
+	}
+
+	// parse rover instructions
+	names := nameRegistry{}
+	instructions := make([]rover.RoverInstruction, 0, len(rovers))
+	for _, rl := range rovers {
+		if isObstacleLine(rl.position.text) {
+			if c.add(rl.position, locate(firstToken(rl.position.text), ErrParseObstaclePlacement)) {
+				return nil, nil, c.err()
+			}
+			continue
+		}
+
+		if err := limit.rover(); err != nil {
+			c.add(rl.position, err)
+			return nil, nil, c.err()
+		}
+
+		name, positionText, err := parseRoverName(rl.position.text)
+		if err != nil && c.add(rl.position, err) {
+			return nil, nil, c.err()
+		}
+
+		if err == nil {
+			if err := names.add(len(instructions)+1, name); err != nil && c.add(rl.position, locate(firstToken(rl.position.text), err)) {
+				return nil, nil, c.err()
+			}
+		}
+
+		position, err := parsePositionLine(positionText, plateau)
+		if err != nil && c.add(rl.position, err) {
+			return nil, nil, c.err()
+		}
+
+		var cmds string
+		if rl.commands != nil {
+			cmds, err = parseCommandsLine(rl.commands.text)
+			if err != nil && c.add(*rl.commands, err) {
+				return nil, nil, c.err()
+			}
+
+			if err := limit.roverCommands(cmds); err != nil {
+				c.add(*rl.commands, err)
+				return nil, nil, c.err()
+			}
 		}
 
 		instruction := rover.RoverInstruction{
+			Name:            name,
 			InitialPosition: position,
 			Commands:        cmds,
 		}
@@ -53,55 +136,213 @@ func (p *Parser) Parse(input string, cfg *config.Config) (*rover.Plateau, []rove
 		instructions = append(instructions, instruction)
 	}
 
+	if err := c.err(); err != nil {
+		return nil, nil, err
+	}
+
 	return plateau, instructions, nil
 }
 
+// roverLines holds the lines describing a single rover, commands is nil when the rover has no commands line
+type roverLines struct {
+	position line
+	commands *line
+}
+
+// pairRoverLines groups the rover section of the strict format, where every rover is a position line followed by a commands line. A trailing position line without commands is dropped and reported
+func pairRoverLines(lines []line) ([]roverLines, error) {
+	if len(lines) < 2 {
+		return nil, ErrParseInvalidFormat
+	}
+
+	rovers := make([]roverLines, 0, len(lines)/2)
+	for i := 0; i+1 < len(lines); i += 2 {
+		rovers = append(rovers, roverLines{position: lines[i], commands: &lines[i+1]})
+	}
+
+	if len(lines)%2 != 0 {
+		return rovers, fmt.Errorf("%w: rover on line %d has no commands line", ErrParseInvalidFormat, lines[len(lines)-1].number)
+	}
+	return rovers, nil
+}
+
+// groupRoverLines groups the rover section of the relaxed format by the shape of its lines: every position line starts a new rover and may be followed by a commands line.
+// Position and obstacle lines are never taken as commands, an obstacle line stands on its own so it can be reported as misplaced
+func groupRoverLines(lines []line) ([]roverLines, error) {
+	var rovers []roverLines
+
+	for i := 0; i < len(lines); i++ {
+		rl := roverLines{position: lines[i]}
+		if i+1 < len(lines) && isCommandsLine(lines[i+1].text) {
+			i++
+			rl.commands = &lines[i]
+		}
+		rovers = append(rovers, rl)
+	}
+
+	if len(rovers) == 0 {
+		return nil, ErrParseInvalidFormat
+	}
+	return rovers, nil
+}
+
+// isPositionLine reports whether the line looks like a rover position rather than commands: it is named or starts with a coordinate. Commands lines hold neither colons nor digits
+func isPositionLine(s string) bool {
+	if strings.Contains(s, ":") {
+		return true
+	}
+
+	tok := firstToken(s)
+	return tok.text != "" && (tok.text[0] == '-' || tok.text[0] == '+' || unicode.IsDigit(rune(tok.text[0])))
+}
+
+// isCommandsLine reports whether the line can hold the commands of a rover, it looks like neither a position nor an obstacle
+func isCommandsLine(s string) bool {
+	return !isPositionLine(s) && !isObstacleLine(s)
+}
+
+// cleanLines strips comments and carriage returns from the lines and drops the ones left blank
+func cleanLines(lines []line) []line {
+	cleaned := make([]line, 0, len(lines))
+
+	for _, l := range lines {
+		text, _, _ := strings.Cut(l.text, "#")
+		text = strings.TrimRight(text, "\r")
+
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		cleaned = append(cleaned, line{number: l.number, text: text})
+	}
+	return cleaned
+}
+
+// missingLine returns the line following the last one, where something was expected but the input ended
+func missingLine(lines []line) line {
+	if len(lines) == 0 {
+		return line{number: 1}
+	}
+	return line{number: lines[len(lines)-1].number + 1}
+}
+
+// firstToken returns the first field of the line, or an empty token at column 1 for blank lines
+func firstToken(s string) token {
+	tokens := tokenize(s)
+	if len(tokens) == 0 {
+		return token{column: 1}
+	}
+	return tokens[0]
+}
+
+// parseRoverName splits the optional "name:" prefix off a rover position line, returning the name (empty if there is none) and the rest of the line. The prefix is blanked out rather than cut so columns in the rest of the line still match the input
+func parseRoverName(line string) (string, string, error) {
+	prefix, _, found := strings.Cut(line, ":")
+	if !found {
+		return "", line, nil
+	}
+
+	name := strings.TrimSpace(prefix)
+	if err := validateRoverName(name); err != nil {
+		tok := firstToken(prefix)
+		if name == "" {
+			tok = token{column: len(prefix) + 1, text: ":"}
+		}
+		return "", "", locate(tok, err)
+	}
+	return name, strings.Repeat(" ", len(prefix)+1) + line[len(prefix)+1:], nil
+}
+
+// validateRoverName checks a rover name is made of letters, digits, '-', '_' and '.' only. Names made of digits alone are taken by the numbers of rovers without a name
+func validateRoverName(name string) error {
+	if name == "" {
+		return fmt.Errorf("%w: name is empty", ErrParseRoverName)
+	}
+
+	if strings.Trim(name, "0123456789") == "" {
+		return fmt.Errorf("%w: given %q", ErrParseRoverNumber, name)
+	}
+
+	for _, char := range name {
+		switch {
+		case char >= 'a' && char <= 'z', char >= 'A' && char <= 'Z', char >= '0' && char <= '9', char == '-', char == '_', char == '.':
+			continue
+		default:
+			return fmt.Errorf("%w: given %q", ErrParseRoverName, name)
+		}
+	}
+	return nil
+}
+
+// nameRegistry makes sure every rover can be told apart. Rovers without a name are known by their deployment number, which no name can be
+type nameRegistry map[string]int
+
+// add registers the rover with the given deployment number and name, failing if another rover already has the name
+func (n nameRegistry) add(number int, name string) error {
+	if name == "" {
+		return nil
+	}
+
+	if first, ok := n[name]; ok {
+		return fmt.Errorf("%w: %q used by rovers %d and %d", ErrParseDuplicateRoverName, name, first, number)
+	}
+	n[name] = number
+	return nil
+}
+
 // parsePlateauLine takes a string and returns a Plateau pointer or an error if the given data is not a line of pair of integers
 func parsePlateauLine(line string, cfg *config.Config) (*rover.Plateau, error) {
-	parts := strings.Fields(strings.TrimSpace(line))
+	parts := tokenize(line)
 
 	if len(parts) != 2 {
 		return nil, fmt.Errorf("%w: want 2 elements, got %d", ErrParsePlateauFormat, len(parts))
 	}
 
-	maxX, err := strconv.Atoi(parts[0])
+	maxX, err := strconv.Atoi(parts[0].text)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v %v", ErrParsePlateauX, parts[0], err)
+		return nil, locate(parts[0], fmt.Errorf("%w: %v %v", ErrParsePlateauX, parts[0].text, err))
 	}
 
-	maxY, err := strconv.Atoi(parts[1])
+	maxY, err := strconv.Atoi(parts[1].text)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v %v", ErrParsePlateauY, parts[1], err)
+		return nil, locate(parts[1], fmt.Errorf("%w: %v %v", ErrParsePlateauY, parts[1].text, err))
 	}
 
 	return rover.NewPlateau(maxX, maxY, cfg.MinPlateauX, cfg.MinPlateauY)
 }
 
-// parsePositionLine
+// parsePositionLine takes an "x y direction" line and returns the Position it describes. With a nil plateau the line is only checked for syntax and no Position is returned
 func parsePositionLine(line string, plateau *rover.Plateau) (*rover.Position, error) {
-	parts := strings.Fields(line)
+	parts := tokenize(line)
 
 	if len(parts) != 3 {
 		return nil, fmt.Errorf("%w: want 3 elements, got %d", ErrParsePositionFormat, len(parts))
 	}
 
-	x, err := strconv.Atoi(parts[0])
+	x, err := strconv.Atoi(parts[0].text)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrParsePositionX, err)
+		return nil, locate(parts[0], fmt.Errorf("%w: %v", ErrParsePositionX, err))
 	}
 
-	y, err := strconv.Atoi(parts[1])
+	y, err := strconv.Atoi(parts[1].text)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrParsePositionY, err)
+		return nil, locate(parts[1], fmt.Errorf("%w: %v", ErrParsePositionY, err))
 	}
 
-	dir, err := parseDirection(parts[2])
+	dir, err := parseDirection(parts[2].text)
 	if err != nil {
-		return nil, err
+		return nil, locate(parts[2], err)
+	}
+
+	if plateau == nil {
+		return nil, nil
 	}
 
 	coords := rover.NewCoordinates(x, y)
-	return rover.NewPosition(plateau, coords, dir)
+	position, err := rover.NewPosition(plateau, coords, dir)
+	if err != nil {
+		return nil, locate(parts[0], err)
+	}
+	return position, nil
 }
 
 // parseDirection
@@ -120,20 +361,82 @@ func parseDirection(dir string) (rover.Direction, error) {
 	return rover.UnknownDirection, fmt.Errorf("%w: given %s", ErrParseInvalidDirection, dir)
 }
 
-// parseCommandsLine
+// parseCommandsLine takes a line of L, R and M commands (case-insensitive) and returns them upper-cased
 func parseCommandsLine(line string) (string, error) {
-	// make it case-insensitive as a convenience feature
-	upperLine := strings.ToUpper(strings.TrimSpace(line))
+	trimmed := strings.TrimSpace(line)
+	offset := strings.Index(line, trimmed)
 
-	for i, char := range upperLine {
-
-		switch rover.Command(char) {
+	for i, char := range trimmed {
+		// make it case-insensitive as a convenience feature
+		switch rover.Command(unicode.ToUpper(char)) {
 		case rover.CmdLeft, rover.CmdRight, rover.CmdMove:
 			continue
 
 		default:
-			return "", fmt.Errorf("%w: character %v at position %d", ErrParseInvalidCommand, char, i)
+			return "", locate(token{text: string(char), column: offset + i + 1}, fmt.Errorf("%w: given %q", ErrParseInvalidCommand, char))
 		}
 	}
-	return upperLine, nil
+	return strings.ToUpper(trimmed), nil
+}
+
+// parseObstacleKind takes an obstacle keyword (case-insensitive) and returns the matching ObstacleKind or an error if the keyword is unknown
+func parseObstacleKind(kind string) (rover.ObstacleKind, error) {
+	switch strings.ToUpper(strings.TrimSpace(kind)) {
+	case "ROCK":
+		return rover.Rock, nil
+	case "CRATER":
+		return rover.Crater, nil
+	case "NOGO":
+		return rover.NoGo, nil
+	case "ZONE":
+		return rover.Zone, nil
+	}
+	return rover.UnknownObstacle, fmt.Errorf("%w: given %s", ErrParseObstacleKind, kind)
+}
+
+// isObstacleLine reports whether the line starts with an obstacle keyword
+func isObstacleLine(line string) bool {
+	parts := strings.Fields(line)
+	if len(parts) == 0 {
+		return false
+	}
+
+	_, err := parseObstacleKind(parts[0])
+	return err == nil
+}
+
+// parseObstacleLine takes a line in the form "KIND x y" or "ZONE x1 y1 x2 y2" and returns the Obstacle it describes
+func parseObstacleLine(line string) (rover.Obstacle, error) {
+	parts := tokenize(line)
+	if len(parts) == 0 {
+		return rover.Obstacle{}, fmt.Errorf("%w: want at least 3 elements, got 0", ErrParseObstacleFormat)
+	}
+
+	kind, err := parseObstacleKind(parts[0].text)
+	if err != nil {
+		return rover.Obstacle{}, locate(parts[0], err)
+	}
+
+	wantParts := 3
+	if kind == rover.Zone {
+		wantParts = 5
+	}
+
+	if len(parts) != wantParts {
+		return rover.Obstacle{}, fmt.Errorf("%w: want %d elements, got %d", ErrParseObstacleFormat, wantParts, len(parts))
+	}
+
+	values := make([]int, 0, wantParts-1)
+	for _, part := range parts[1:] {
+		v, err := strconv.Atoi(part.text)
+		if err != nil {
+			return rover.Obstacle{}, locate(part, fmt.Errorf("%w: %v", ErrParseObstacleCoordinate, err))
+		}
+		values = append(values, v)
+	}
+
+	if kind == rover.Zone {
+		return rover.NewZone(rover.NewCoordinates(values[0], values[1]), rover.NewCoordinates(values[2], values[3])), nil
+	}
+	return rover.NewObstacle(kind, rover.NewCoordinates(values[0], values[1])), nil
 }
@@ -222,11 +222,46 @@ func TestParsePlateauLine(t *testing.T) {
 	}
 }
 
+func TestParseObstacleLine(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		line         string
+		wantObstacle rover.Obstacle
+		wantErr      error
+	}{
+		"ok - rock":                    {line: "ROCK 1 2", wantObstacle: rover.NewObstacle(rover.Rock, rover.NewCoordinates(1, 2))},
+		"ok - crater lower case":       {line: "crater 3 4", wantObstacle: rover.NewObstacle(rover.Crater, rover.NewCoordinates(3, 4))},
+		"ok - nogo":                    {line: "\tNOGO 0 0 ", wantObstacle: rover.NewObstacle(rover.NoGo, rover.NewCoordinates(0, 0))},
+		"ok - zone":                    {line: "ZONE 1 1 3 4", wantObstacle: rover.NewZone(rover.NewCoordinates(1, 1), rover.NewCoordinates(3, 4))},
+		"err - ErrParseObstacleKind":   {line: "TREE 1 1", wantErr: ErrParseObstacleKind},
+		"err - ErrParseObstacleFormat": {line: "ROCK 1 1 1", wantErr: ErrParseObstacleFormat},
+		"err - ErrParseObstacleFormat - zone with cell coordinates": {line: "ZONE 1 1", wantErr: ErrParseObstacleFormat},
+		"err - ErrParseObstacleCoordinate":                          {line: "ROCK X 1", wantErr: ErrParseObstacleCoordinate},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+
+			obstacle, err := parseObstacleLine(tc.line)
+
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.wantObstacle, obstacle)
+		})
+	}
+}
+
 func TestParse(t *testing.T) {
 	testPlateau := createTestPlateau(t, 5, 5)
 
 	testCases := map[string]struct {
 		input            string
+		strict           bool
 		wantPlateau      *rover.Plateau
 		wantInstructions []rover.RoverInstruction
 		wantErr          error
@@ -248,6 +283,7 @@ MMRMMRMRRM`,
 			input: `
 5 5
 1 2 N`,
+			strict:           true,
 			wantPlateau:      nil,
 			wantInstructions: nil,
 			wantErr:          ErrParseInvalidFormat,
@@ -271,6 +307,161 @@ LMLM`,
 			// This error comes from rover.NewPosition, which is called by parsePositionLine
 			wantErr: rover.ErrPositionOutOfBounds,
 		},
+		"ok - obstacle section": {
+			input: `
+5 5
+ROCK 2 2
+zone 0 4 1 5
+1 2 N
+LMLMLMLMM`,
+			wantPlateau: func() *rover.Plateau {
+				p := createTestPlateau(t, 5, 5)
+				_ = p.AddObstacle(rover.NewObstacle(rover.Rock, rover.NewCoordinates(2, 2)))
+				_ = p.AddObstacle(rover.NewZone(rover.NewCoordinates(0, 4), rover.NewCoordinates(1, 5)))
+				return p
+			}(),
+			wantInstructions: []rover.RoverInstruction{
+				*createTestSingleRoverInstruction(t, testPlateau, 1, 2, rover.N, "LMLMLMLMM"),
+			},
+			wantErr: nil,
+		},
+		"error - obstacle section without rovers": {
+			input: `
+5 5
+ROCK 2 2
+CRATER 3 3`,
+			wantPlateau:      nil,
+			wantInstructions: nil,
+			wantErr:          ErrParseInvalidFormat,
+		},
+		"error - obstacle out of bounds": {
+			input: `
+5 5
+ROCK 6 2
+1 2 N
+M`,
+			wantPlateau:      nil,
+			wantInstructions: nil,
+			wantErr:          rover.ErrObstacleOutOfBounds,
+		},
+		"ok - named rovers": {
+			input: `
+5 5
+alpha: 1 2 N
+LMLMLMLMM
+3 3 E
+MMRMMRMRRM`,
+			wantPlateau: testPlateau,
+			wantInstructions: []rover.RoverInstruction{
+				func() rover.RoverInstruction {
+					ri := *createTestSingleRoverInstruction(t, testPlateau, 1, 2, rover.N, "LMLMLMLMM")
+					ri.Name = "alpha"
+					return ri
+				}(),
+				*createTestSingleRoverInstruction(t, testPlateau, 3, 3, rover.E, "MMRMMRMRRM"),
+			},
+			wantErr: nil,
+		},
+		"error - duplicate rover name": {
+			input: `
+5 5
+alpha: 1 2 N
+M
+alpha: 3 3 E
+M`,
+			wantErr: ErrParseDuplicateRoverName,
+		},
+		"error - rover name is a number": {
+			input: `
+5 5
+1 2 N
+M
+3: 3 3 E
+M`,
+			wantErr: ErrParseRoverNumber,
+		},
+		"error - invalid rover name": {
+			input: `
+5 5
+rover one: 1 2 N
+M`,
+			wantErr: ErrParseRoverName,
+		},
+		"error - empty rover name": {
+			input: `
+5 5
+: 1 2 N
+M`,
+			wantErr: ErrParseRoverName,
+		},
+		"ok - comments, blank lines and CRLF": {
+			input:       "# mission 42\r\n5 5 # plateau\r\n\r\n1 2 N\r\n  # first rover\r\nLMLMLMLMM # loop\r\n\r\n3 3 E\r\nMMRMMRMRRM\r\n",
+			wantPlateau: testPlateau,
+			wantInstructions: []rover.RoverInstruction{
+				*createTestSingleRoverInstruction(t, testPlateau, 1, 2, rover.N, "LMLMLMLMM"),
+				*createTestSingleRoverInstruction(t, testPlateau, 3, 3, rover.E, "MMRMMRMRRM"),
+			},
+			wantErr: nil,
+		},
+		"ok - rovers without commands line": {
+			input: `
+5 5
+1 2 N
+3 3 E
+
+alpha: 4 4 S
+M`,
+			wantPlateau: testPlateau,
+			wantInstructions: []rover.RoverInstruction{
+				*createTestSingleRoverInstruction(t, testPlateau, 1, 2, rover.N, ""),
+				*createTestSingleRoverInstruction(t, testPlateau, 3, 3, rover.E, ""),
+				func() rover.RoverInstruction {
+					ri := *createTestSingleRoverInstruction(t, testPlateau, 4, 4, rover.S, "M")
+					ri.Name = "alpha"
+					return ri
+				}(),
+			},
+			wantErr: nil,
+		},
+		"ok - strict keeps blank lines as empty commands": {
+			input:       "5 5\n1 2 N\n\n3 3 E\nM",
+			strict:      true,
+			wantPlateau: testPlateau,
+			wantInstructions: []rover.RoverInstruction{
+				*createTestSingleRoverInstruction(t, testPlateau, 1, 2, rover.N, ""),
+				*createTestSingleRoverInstruction(t, testPlateau, 3, 3, rover.E, "M"),
+			},
+			wantErr: nil,
+		},
+		"error - strict rejects comments": {
+			input:   "5 5\n1 2 N\nM # forward",
+			strict:  true,
+			wantErr: ErrParseInvalidCommand,
+		},
+		"error - only comments": {
+			input:   "# nothing to see\n\n# here",
+			wantErr: ErrParseInvalidFormat,
+		},
+		"error - plateau without rovers": {
+			input:   "5 5 # empty mission",
+			wantErr: ErrParseInvalidFormat,
+		},
+		"error - malformed position line after a rover without commands": {
+			input: `
+5 5
+1 2 N
+1 2`,
+			wantErr: ErrParsePositionFormat,
+		},
+		"error - obstacle line after the rovers": {
+			input: `
+5 5
+1 2 N
+ROCK 1 1
+3 3 E
+M`,
+			wantErr: ErrParseObstaclePlacement,
+		},
 		"error - invalid command line": {
 			input: `
 5 5
@@ -282,12 +473,13 @@ LMXLM`, // 'X' is an invalid command
 		},
 	}
 
-	cfg := config.Default()
-
 	for name, tc := range testCases {
 		t.Run(name, func(t *testing.T) {
 			p := New() // Create a new parser instance
 
+			cfg := config.Default()
+			cfg.Strict = tc.strict
+
 			gotPlateau, gotInstructions, err := p.Parse(tc.input, cfg)
 
 			if tc.wantErr != nil {
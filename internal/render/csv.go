@@ -0,0 +1,88 @@
+package render
+
+import (
+	"encoding/csv"
+	"io"
+	"mars/internal/rover"
+	"strconv"
+)
+
+// CSV renders one row per rover after a header row, with its start and final positions, status and number of blocked moves
+type CSV struct{}
+
+var csvHeader = []string{"id", "name", "start_x", "start_y", "start_heading", "x", "y", "heading", "status", "blocked_moves", "error"}
+
+func (CSV) ContentType() string {
+	return "text/csv; charset=utf-8"
+}
+
+func (CSV) Render(w io.Writer, result *rover.MissionResult) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, rr := range result.Rovers {
+		if err := cw.Write(csvRecord(rr)); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+var csvBatchHeader = append([]string{"mission", "mission_name", "mission_ok", "mission_error"}, csvHeader...)
+
+// RenderBatch writes one row per rover of every mission, prefixed with the mission columns. A failed mission has a single row with empty rover columns
+func (CSV) RenderBatch(w io.Writer, missions []BatchMission) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(csvBatchHeader); err != nil {
+		return err
+	}
+
+	for _, m := range missions {
+		mission := []string{strconv.Itoa(m.ID), m.Name, strconv.FormatBool(m.OK()), ""}
+
+		if !m.OK() {
+			mission[3] = m.Err.Error()
+			if err := cw.Write(append(mission, make([]string, len(csvHeader))...)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, rr := range m.Result.Rovers {
+			if err := cw.Write(append(mission, csvRecord(rr)...)); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvRecord returns the columns of a rover, matching csvHeader
+func csvRecord(rr rover.RoverResult) []string {
+	var errText string
+	if rr.Err != nil {
+		errText = rr.Err.Error()
+	}
+
+	return []string{
+		strconv.Itoa(rr.ID),
+		rr.Name,
+		strconv.Itoa(rr.Start.X()),
+		strconv.Itoa(rr.Start.Y()),
+		rr.Start.Direction().String(),
+		strconv.Itoa(rr.Position.X()),
+		strconv.Itoa(rr.Position.Y()),
+		rr.Position.Direction().String(),
+		rr.Status.String(),
+		strconv.Itoa(blockedMoves(rr)),
+		errText,
+	}
+}
@@ -0,0 +1,12 @@
+package render
+
+import "errors"
+
+var (
+	ErrFormatUnknown    = errors.New("output format must be one of text, json, csv, table, grid, grid-steps, svg, png, gif")
+	ErrGridTooLarge     = errors.New("mission too large to draw as a grid")
+	ErrImageTooLarge    = errors.New("plateau too large to draw at this cell size")
+	ErrSVGTooLarge      = errors.New("mission too large to draw as an svg")
+	ErrAnimationTooLong = errors.New("mission too long to animate")
+	ErrImageBatch       = errors.New("png and gif formats draw a single mission, they cannot render a batch")
+)
@@ -0,0 +1,376 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"mars/internal/rover"
+	"strconv"
+	"strings"
+)
+
+// marks drawn in the cells of a grid that hold no rover
+const (
+	gridEmpty    = "."
+	gridObstacle = "#"
+	gridVisited  = "o"
+)
+
+const maxGridBytes = 16_000_000 // largest drawing of a mission, every step of it together
+
+// Grid draws the plateau as a character grid, north up so y grows upward as it does for the rovers.
+// Rovers are drawn as a heading arrow (^ > v <) followed by their id, obstacles as # and the cells rovers went through as o. A legend of the rovers follows the grid.
+// Every drawing is written as soon as it is done, a mission whose drawings would take over maxGridBytes fails with ErrGridTooLarge before anything is written
+type Grid struct {
+	Steps bool // draw the plateau after every step of the mission, or every tick in lockstep mode, rather than once it is over
+}
+
+func (Grid) ContentType() string {
+	return "text/plain; charset=utf-8"
+}
+
+func (g Grid) Render(w io.Writer, result *rover.MissionResult) error {
+	d := newGridDrawing(result)
+
+	drawings := 1
+	if g.Steps {
+		drawings = countFrames(result)
+	}
+	if !d.fits(drawings) {
+		return fmt.Errorf("%w: %d drawings of %d x %d cells", ErrGridTooLarge, drawings, result.Plateau.MaxX()+1, result.Plateau.MaxY()+1)
+	}
+
+	var sb strings.Builder
+	var err error
+	state := newGridState()
+	replay(result, state, func(caption string, _ []int) {
+		if !g.Steps || err != nil {
+			return
+		}
+
+		sb.Reset()
+		sb.WriteString(caption)
+		sb.WriteByte('\n')
+		d.write(&sb, state)
+		sb.WriteByte('\n')
+		_, err = io.WriteString(w, sb.String())
+	})
+	if err != nil {
+		return err
+	}
+
+	sb.Reset()
+	state.finish(result)
+	if !g.Steps {
+		d.write(&sb, state)
+	}
+
+	for _, rr := range result.Rovers {
+		sb.WriteString(legend(rr))
+		sb.WriteByte('\n')
+	}
+
+	_, err = io.WriteString(w, sb.String())
+	return err
+}
+
+// RenderBatch draws every mission after the same "--- mission" header line as the text format, failed missions only show their error
+func (g Grid) RenderBatch(w io.Writer, missions []BatchMission) error {
+	for _, m := range missions {
+		if !m.OK() {
+			if _, err := fmt.Fprintf(w, "%s (line %d): failed: %v\n", batchHeader(m), m.Line, m.Err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if _, err := fmt.Fprintf(w, "%s (line %d): ok\n", batchHeader(m), m.Line); err != nil {
+			return err
+		}
+		if err := g.Render(w, m.Result); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// gridState holds the rovers on the plateau and the cells visited so far at some point of the mission
+type gridState struct {
+	rovers  map[int]rover.Position    // rovers on the plateau by id, lost rovers are no longer on it
+	cells   map[rover.Coordinates]int // id of the rover on every occupied cell
+	visited map[rover.Coordinates]int // id of the last rover through every visited cell
+}
+
+func newGridState() *gridState {
+	return &gridState{
+		rovers:  map[int]rover.Position{},
+		cells:   map[rover.Coordinates]int{},
+		visited: map[rover.Coordinates]int{},
+	}
+}
+
+// place puts the rover at the given position, marking its cell visited
+func (s *gridState) place(id int, p rover.Position) {
+	s.remove(id)
+	s.rovers[id] = p
+	s.cells[cell(p)] = id
+	s.visited[cell(p)] = id
+}
+
+// remove takes the rover off the plateau
+func (s *gridState) remove(id int) {
+	p, ok := s.rovers[id]
+	if !ok {
+		return
+	}
+
+	delete(s.rovers, id)
+	// a rover swapped onto the cell may already have taken it over
+	if s.cells[cell(p)] == id {
+		delete(s.cells, cell(p))
+	}
+}
+
+// clearRovers takes every rover off the plateau, the cells they visited stay visited
+func (s *gridState) clearRovers() {
+	clear(s.rovers)
+	clear(s.cells)
+}
+
+// finish puts the rovers where the mission left them, pushes and swaps included
+func (s *gridState) finish(result *rover.MissionResult) {
+	s.clearRovers()
+	for _, rr := range result.Rovers {
+		if rr.Status != rover.StatusLost {
+			s.place(rr.ID, rr.Position)
+		}
+	}
+}
+
+// roverAt returns the id of the rover in the cell of p, other than the given one
+func (s *gridState) roverAt(p rover.Position, other int) (int, bool) {
+	id, ok := s.cells[cell(p)]
+	if !ok || id == other {
+		return 0, false
+	}
+	return id, true
+}
+
+// replay walks the mission from the deployment of its rovers to its end, updating state and calling frame after every change with a caption telling what changed and the ids of the rovers that may have moved.
+// Lockstep missions are replayed tick by tick, others rover by rover from their traces, which only hold the deployments when no trace was recorded
+func replay(result *rover.MissionResult, state *gridState, frame func(caption string, moved []int)) {
+	if len(result.Ticks) > 0 {
+		for _, tick := range result.Ticks {
+			moved := make([]int, 0, len(tick.Rovers))
+			state.clearRovers()
+			for _, rs := range tick.Rovers {
+				if rs.Status != rover.StatusLost {
+					state.place(rs.ID, rs.Position)
+				}
+				moved = append(moved, rs.ID)
+			}
+			frame(fmt.Sprintf("tick %d", tick.Number), moved)
+		}
+		return
+	}
+
+	for _, rr := range result.Rovers {
+		state.place(rr.ID, rr.Start)
+		frame(fmt.Sprintf("rover %s deployed at %s", rr.Label(), rr.Start.String()), []int{rr.ID})
+
+		for _, step := range rr.Trace {
+			moved := []int{rr.ID}
+			switch step.Outcome {
+			case rover.OutcomeLost:
+				state.remove(rr.ID)
+			case rover.OutcomePushed:
+				// the rover in the way moves on one cell along the heading of the one pushing it
+				if id, ok := state.roverAt(step.After, rr.ID); ok {
+					state.place(id, pushedTo(result.Plateau, state.rovers[id], step.After.Direction()))
+					moved = append(moved, id)
+				}
+				state.place(rr.ID, step.After)
+			case rover.OutcomeSwapped:
+				// the rover in the way takes the cell the one swapping with it left
+				if id, ok := state.roverAt(step.After, rr.ID); ok {
+					state.place(id, moveTo(result.Plateau, state.rovers[id], cell(step.Before)))
+					moved = append(moved, id)
+				}
+				state.place(rr.ID, step.After)
+			default:
+				state.place(rr.ID, step.After)
+			}
+			frame(fmt.Sprintf("rover %s %s", rr.Label(), step), moved)
+		}
+	}
+}
+
+// countFrames returns how many times replay calls its frame function for the mission
+func countFrames(result *rover.MissionResult) int {
+	if len(result.Ticks) > 0 {
+		return len(result.Ticks)
+	}
+
+	var frames int
+	for _, rr := range result.Rovers {
+		frames += 1 + len(rr.Trace)
+	}
+	return frames
+}
+
+// legend returns the number of the rover followed by its final position, e.g. "1: 1 3 N" or "2 alpha: 5 5 E LOST"
+func legend(rr rover.RoverResult) string {
+	if rr.Name != "" {
+		return fmt.Sprintf("%d %s", rr.ID, rr.String())
+	}
+	return fmt.Sprintf("%d: %s", rr.ID, rr.String())
+}
+
+// cell returns the coordinates of a position, whatever its heading
+func cell(p rover.Position) rover.Coordinates {
+	return rover.NewCoordinates(p.X(), p.Y())
+}
+
+// pushedTo returns the position a rover at p is pushed to by a rover heading d, one cell along d.
+// A push off the plateau only goes through when the boundary policy wraps it, the rover then comes back on the opposite edge
+func pushedTo(plateau *rover.Plateau, p rover.Position, d rover.Direction) rover.Position {
+	x, y := p.X(), p.Y()
+	switch d {
+	case rover.N:
+		y++
+	case rover.E:
+		x++
+	case rover.S:
+		y--
+	case rover.W:
+		x--
+	}
+
+	width, height := plateau.MaxX()+1, plateau.MaxY()+1
+	return moveTo(plateau, p, rover.NewCoordinates((x+width)%width, (y+height)%height))
+}
+
+// moveTo returns the position at c with the heading of p, p itself if c is off the plateau
+func moveTo(plateau *rover.Plateau, p rover.Position, c rover.Coordinates) rover.Position {
+	moved, err := rover.NewPosition(plateau, c, p.Direction())
+	if err != nil {
+		return p
+	}
+	return *moved
+}
+
+// gridDrawing draws the states of a mission on its plateau, every cell as wide as the widest rover or x label
+type gridDrawing struct {
+	plateau   *rover.Plateau
+	cellWidth int
+	rowWidth  int // width of the y labels
+}
+
+func newGridDrawing(result *rover.MissionResult) *gridDrawing {
+	d := &gridDrawing{
+		plateau:   result.Plateau,
+		cellWidth: len(strconv.Itoa(result.Plateau.MaxX())),
+		rowWidth:  len(strconv.Itoa(result.Plateau.MaxY())),
+	}
+
+	for _, rr := range result.Rovers {
+		d.cellWidth = max(d.cellWidth, len(strconv.Itoa(rr.ID))+1)
+	}
+
+	return d
+}
+
+// fits reports whether the given number of drawings, captions included, take at most maxGridBytes. Every dimension is checked before it is multiplied so a huge plateau cannot overflow the size
+func (d *gridDrawing) fits(drawings int) bool {
+	// a caption is a trace line, well within a row of a few cells
+	const captionBytes = 80
+
+	// a row is its label, every cell after a separator and a line break
+	cellBytes := d.cellWidth + 1
+	if d.plateau.MaxX() >= (maxGridBytes-d.rowWidth-1)/cellBytes {
+		return false
+	}
+	rowBytes := d.rowWidth + (d.plateau.MaxX()+1)*cellBytes + 1
+
+	// the rows of the plateau and the x labels
+	if d.plateau.MaxY() >= maxGridBytes/rowBytes-1 {
+		return false
+	}
+	size := (d.plateau.MaxY()+2)*rowBytes + captionBytes
+
+	return drawings <= maxGridBytes/size
+}
+
+// write draws the state as one line per row of the plateau from the top one down, followed by the x labels
+func (d *gridDrawing) write(sb *strings.Builder, s *gridState) {
+	drawn := make(map[rover.Coordinates]string, len(s.rovers))
+	for id, p := range s.rovers {
+		drawn[cell(p)] = arrow(p.Direction()) + strconv.Itoa(id)
+	}
+
+	for y := d.plateau.MaxY(); y >= 0; y-- {
+		row := []string{fmt.Sprintf("%*d", d.rowWidth, y)}
+		obstacles := d.obstaclesOn(y)
+
+		for x := 0; x <= d.plateau.MaxX(); x++ {
+			c := rover.NewCoordinates(x, y)
+			mark, ok := drawn[c]
+			switch {
+			case ok:
+			case covers(obstacles, x):
+				mark = gridObstacle
+			case s.visited[c] > 0:
+				mark = gridVisited
+			default:
+				mark = gridEmpty
+			}
+			row = append(row, fmt.Sprintf("%-*s", d.cellWidth, mark))
+		}
+		sb.WriteString(strings.TrimRight(strings.Join(row, " "), " "))
+		sb.WriteByte('\n')
+	}
+
+	labels := []string{strings.Repeat(" ", d.rowWidth)}
+	for x := 0; x <= d.plateau.MaxX(); x++ {
+		labels = append(labels, fmt.Sprintf("%-*d", d.cellWidth, x))
+	}
+	sb.WriteString(strings.TrimRight(strings.Join(labels, " "), " "))
+	sb.WriteByte('\n')
+}
+
+// obstaclesOn returns the obstacles covering some cell of row y
+func (d *gridDrawing) obstaclesOn(y int) []rover.Obstacle {
+	var on []rover.Obstacle
+	for _, o := range d.plateau.Obstacles() {
+		if from, to := o.Bounds(); from.Y() <= y && y <= to.Y() {
+			on = append(on, o)
+		}
+	}
+	return on
+}
+
+// covers reports whether any of the obstacles of a row covers column x of it
+func covers(obstacles []rover.Obstacle, x int) bool {
+	for _, o := range obstacles {
+		if from, to := o.Bounds(); from.X() <= x && x <= to.X() {
+			return true
+		}
+	}
+	return false
+}
+
+// arrow returns the arrow pointing the way of the heading
+func arrow(d rover.Direction) string {
+	switch d {
+	case rover.N:
+		return "^"
+	case rover.E:
+		return ">"
+	case rover.S:
+		return "v"
+	case rover.W:
+		return "<"
+	default:
+		return "?"
+	}
+}
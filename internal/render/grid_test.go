@@ -0,0 +1,239 @@
+package render_test
+
+import (
+	"context"
+	"mars/internal/config"
+	"mars/internal/parser"
+	"mars/internal/render"
+	"mars/internal/rover"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrid_Render(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		input      string
+		opts       []rover.Option
+		steps      bool
+		wantOutput string
+	}{
+		"ok - final positions and visited cells": {
+			input: "5 5\n1 2 N\nMLMRM\nalpha: 5 5 E\nM\n2 1 S\nMM",
+			opts:  []rover.Option{rover.WithTrace()},
+			wantOutput: "5 .  .  .  .  .  >2\n" +
+				"4 ^1 .  .  .  .  .\n" +
+				"3 o  o  .  .  .  .\n" +
+				"2 .  o  .  .  .  .\n" +
+				"1 .  .  o  .  .  .\n" +
+				"0 .  .  v3 .  .  .\n" +
+				"  0  1  2  3  4  5\n" +
+				"1: 0 4 N\n" +
+				"2 alpha: 5 5 E\n" +
+				"3: 2 0 S\n",
+		},
+		"ok - obstacles": {
+			input: "3 2\nROCK 0 0\nZONE 2 1 3 2\n1 0 E\nM",
+			opts:  []rover.Option{rover.WithTrace()},
+			wantOutput: "2 .  .  #  #\n" +
+				"1 .  .  #  #\n" +
+				"0 #  o  >1 .\n" +
+				"  0  1  2  3\n" +
+				"1: 2 0 E\n",
+		},
+		"ok - lost rover left off the grid": {
+			input: "2 2\n0 0 W\nM",
+			opts:  []rover.Option{rover.WithTrace(), rover.WithBoundaryPolicy(rover.LoseOffEdge{})},
+			wantOutput: "2 .  .  .\n" +
+				"1 .  .  .\n" +
+				"0 o  .  .\n" +
+				"  0  1  2\n" +
+				"1: 0 0 W LOST\n",
+		},
+		"ok - cells as wide as the x labels": {
+			input: "10 1\n0 0 N\n\n10 1 W\n",
+			wantOutput: "1 .  .  .  .  .  .  .  .  .  .  <2\n" +
+				"0 ^1 .  .  .  .  .  .  .  .  .  .\n" +
+				"  0  1  2  3  4  5  6  7  8  9  10\n" +
+				"1: 0 0 N\n" +
+				"2: 10 1 W\n",
+		},
+		"ok - cells as wide as the rover ids": {
+			input: "1 9\n0 0 E\n\n0 1 E\n\n0 2 E\n\n0 3 E\n\n0 4 E\n\n0 5 E\n\n0 6 E\n\n0 7 E\n\n0 8 E\n\n0 9 E\n\n",
+			wantOutput: "9 >10 .\n" +
+				"8 >9  .\n" +
+				"7 >8  .\n" +
+				"6 >7  .\n" +
+				"5 >6  .\n" +
+				"4 >5  .\n" +
+				"3 >4  .\n" +
+				"2 >3  .\n" +
+				"1 >2  .\n" +
+				"0 >1  .\n" +
+				"  0   1\n" +
+				"1: 0 0 E\n" +
+				"2: 0 1 E\n" +
+				"3: 0 2 E\n" +
+				"4: 0 3 E\n" +
+				"5: 0 4 E\n" +
+				"6: 0 5 E\n" +
+				"7: 0 6 E\n" +
+				"8: 0 7 E\n" +
+				"9: 0 8 E\n" +
+				"10: 0 9 E\n",
+		},
+		"ok - steps": {
+			input: "2 1\n0 0 E\nM\n2 1 S\nLM",
+			opts:  []rover.Option{rover.WithTrace()},
+			steps: true,
+			wantOutput: "rover 1 deployed at 0 0 E\n" +
+				"1 .  .  .\n0 >1 .  .\n  0  1  2\n\n" +
+				"rover 1 step 1 M: 0 0 E -> 1 0 E moved\n" +
+				"1 .  .  .\n0 o  >1 .\n  0  1  2\n\n" +
+				"rover 2 deployed at 2 1 S\n" +
+				"1 .  .  v2\n0 o  >1 .\n  0  1  2\n\n" +
+				"rover 2 step 1 L: 2 1 S -> 2 1 E turned\n" +
+				"1 .  .  >2\n0 o  >1 .\n  0  1  2\n\n" +
+				"rover 2 step 2 M: 2 1 E -> 2 1 E blocked-by-boundary\n" +
+				"1 .  .  >2\n0 o  >1 .\n  0  1  2\n\n" +
+				"1: 1 0 E\n" +
+				"2: 2 1 E\n",
+		},
+		"ok - steps of a push": {
+			input: "3 0\n1 0 E\n\n0 0 E\nM",
+			opts:  []rover.Option{rover.WithTrace(), rover.WithCollisionPolicy(rover.PushOnCollision{})},
+			steps: true,
+			wantOutput: "rover 1 deployed at 1 0 E\n" +
+				"0 .  >1 .  .\n  0  1  2  3\n\n" +
+				"rover 2 deployed at 0 0 E\n" +
+				"0 >2 >1 .  .\n  0  1  2  3\n\n" +
+				"rover 2 step 1 M: 0 0 E -> 1 0 E pushed\n" +
+				"0 o  >2 >1 .\n  0  1  2  3\n\n" +
+				"1: 2 0 E\n" +
+				"2: 1 0 E\n",
+		},
+		"ok - steps of a push across the wrap edge": {
+			input: "2 0\n0 0 N\n\n2 0 E\nM",
+			opts:  []rover.Option{rover.WithTrace(), rover.WithBoundaryPolicy(rover.WrapAround{}), rover.WithCollisionPolicy(rover.PushOnCollision{})},
+			steps: true,
+			wantOutput: "rover 1 deployed at 0 0 N\n" +
+				"0 ^1 .  .\n  0  1  2\n\n" +
+				"rover 2 deployed at 2 0 E\n" +
+				"0 ^1 .  >2\n  0  1  2\n\n" +
+				"rover 2 step 1 M: 2 0 E -> 0 0 E pushed\n" +
+				"0 >2 ^1 o\n  0  1  2\n\n" +
+				"1: 1 0 N\n" +
+				"2: 0 0 E\n",
+		},
+		"ok - push of a rover across the wrap edge": {
+			input: "2 0\n2 0 N\n\n1 0 E\nM",
+			opts:  []rover.Option{rover.WithTrace(), rover.WithBoundaryPolicy(rover.WrapAround{}), rover.WithCollisionPolicy(rover.PushOnCollision{})},
+			steps: true,
+			wantOutput: "rover 1 deployed at 2 0 N\n" +
+				"0 .  .  ^1\n  0  1  2\n\n" +
+				"rover 2 deployed at 1 0 E\n" +
+				"0 .  >2 ^1\n  0  1  2\n\n" +
+				"rover 2 step 1 M: 1 0 E -> 2 0 E pushed\n" +
+				"0 ^1 o  >2\n  0  1  2\n\n" +
+				"1: 0 0 N\n" +
+				"2: 2 0 E\n",
+		},
+		"ok - steps of a swap": {
+			input: "2 0\n1 0 N\n\n0 0 E\nM",
+			opts:  []rover.Option{rover.WithTrace(), rover.WithCollisionPolicy(rover.SwapOnCollision{})},
+			steps: true,
+			wantOutput: "rover 1 deployed at 1 0 N\n" +
+				"0 .  ^1 .\n  0  1  2\n\n" +
+				"rover 2 deployed at 0 0 E\n" +
+				"0 >2 ^1 .\n  0  1  2\n\n" +
+				"rover 2 step 1 M: 0 0 E -> 1 0 E swapped\n" +
+				"0 ^1 >2 .\n  0  1  2\n\n" +
+				"1: 0 0 N\n" +
+				"2: 1 0 E\n",
+		},
+		"ok - ticks in lockstep mode": {
+			input: "2 1\n0 0 N\nM\n2 1 W\nMM",
+			opts:  []rover.Option{rover.WithLockstep()},
+			steps: true,
+			wantOutput: "tick 0\n" +
+				"1 .  .  <2\n0 ^1 .  .\n  0  1  2\n\n" +
+				"tick 1\n" +
+				"1 ^1 <2 o\n0 o  .  .\n  0  1  2\n\n" +
+				"tick 2\n" +
+				"1 ^1 <2 o\n0 o  .  .\n  0  1  2\n\n" +
+				"1: 0 1 N\n" +
+				"2: 1 1 W\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var sb strings.Builder
+			require.NoError(t, render.Grid{Steps: tc.steps}.Render(&sb, simulate(t, tc.input, tc.opts...)))
+
+			assert.Equal(t, tc.wantOutput, sb.String())
+		})
+	}
+}
+
+func TestGrid_RenderTooLarge(t *testing.T) {
+	t.Parallel()
+
+	result := simulate(t, "300 300\n0 0 N\n"+strings.Repeat("M", 300), rover.WithTrace())
+
+	var sb strings.Builder
+	require.ErrorIs(t, render.Grid{Steps: true}.Render(&sb, result), render.ErrGridTooLarge)
+	assert.Zero(t, sb.Len())
+
+	// the final drawing alone fits
+	require.NoError(t, render.Grid{}.Render(&sb, result))
+	assert.Contains(t, sb.String(), "1: 0 300 N\n")
+}
+
+func TestGrid_RenderHugeZone(t *testing.T) {
+	t.Parallel()
+
+	result := simulate(t, "10000 10000\nZONE 0 0 9999 9999\n10000 10000 S\nL")
+
+	start := time.Now()
+	var sb strings.Builder
+	require.ErrorIs(t, render.Grid{}.Render(&sb, result), render.ErrGridTooLarge)
+	assert.Less(t, time.Since(start), time.Second, "the size of the drawing is checked before the zone is looked at")
+	assert.Zero(t, sb.Len())
+}
+
+func TestGrid_RenderHugePlateau(t *testing.T) {
+	t.Parallel()
+
+	// with the limits disabled the size of this drawing overflows to a few bytes when computed naively
+	plateau, err := rover.NewPlateau(3, 1<<62-2, 0, 0)
+	require.NoError(t, err)
+
+	var sb strings.Builder
+	require.ErrorIs(t, render.Grid{}.Render(&sb, &rover.MissionResult{Plateau: plateau}), render.ErrGridTooLarge)
+	assert.Zero(t, sb.Len())
+}
+
+// simulate parses the mission input and runs it with the given options
+func simulate(t *testing.T, input string, opts ...rover.Option) *rover.MissionResult {
+	t.Helper()
+
+	cfg := config.Default()
+	cfg.MinPlateauX, cfg.MinPlateauY = 0, 0
+
+	plateau, instructions, err := parser.New().Parse(input, cfg)
+	require.NoError(t, err)
+
+	mc, err := rover.NewMissionControl(plateau, opts...)
+	require.NoError(t, err)
+
+	result, err := mc.Simulate(context.Background(), &rover.MissionControlInput{Instructions: instructions})
+	require.NoError(t, err)
+
+	return result
+}
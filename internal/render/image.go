@@ -0,0 +1,377 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io"
+	"mars/internal/rover"
+	"math"
+	"time"
+)
+
+const (
+	defaultCellSize   = 32
+	defaultFrameDelay = 250 * time.Millisecond
+	maxImagePixels    = 16_000_000 // largest picture drawn, about 4000 x 4000 pixels
+	maxAnimationBytes = 64_000_000 // memory the frames of an animation may take before it is encoded
+	frameOverhead     = 256        // memory a frame takes besides its pixels
+	finalFrameHold    = 4          // the last frame of an animation stays up that many times longer before it starts over
+	maxGIFDelay       = 1<<16 - 1  // longest a gif frame can stay up, in hundredths of a second
+)
+
+// palette indexes of the colours of a picture, every rover then has its colour followed by the tint of the cells it visited
+const (
+	colourCell uint8 = iota
+	colourGrid
+	colourObstacle
+	colourRovers
+)
+
+// imagePalette holds the colours of the pictures, rovers taking the colours of the svg format
+var imagePalette = newImagePalette()
+
+func newImagePalette() color.Palette {
+	p := color.Palette{
+		color.RGBA{0xfa, 0xfa, 0xfa, 0xff},
+		color.RGBA{0xdd, 0xdd, 0xdd, 0xff},
+		color.RGBA{0x55, 0x55, 0x55, 0xff},
+	}
+
+	for _, hex := range svgPalette {
+		var r, g, b uint8
+		fmt.Sscanf(hex, "#%02x%02x%02x", &r, &g, &b)
+		p = append(p, color.RGBA{r, g, b, 0xff}, color.RGBA{tint(r), tint(g), tint(b), 0xff})
+	}
+	return p
+}
+
+// tint returns the channel of a colour mixed with three parts of white
+func tint(c uint8) uint8 {
+	return 0xff - (0xff-c)/4
+}
+
+// PNG draws the plateau once the mission is over as a picture: obstacles in dark grey, the cells rovers went through tinted with the colour of the last one,
+// and every rover as a triangle of its colour pointing its heading. Rovers are coloured in deployment order as in the svg format
+type PNG struct {
+	CellSize int // side of a plateau cell, in pixels
+}
+
+func (PNG) ContentType() string {
+	return "image/png"
+}
+
+func (p PNG) Render(w io.Writer, result *rover.MissionResult) error {
+	c, err := newCanvas(result, p.CellSize)
+	if err != nil {
+		return err
+	}
+
+	state := newGridState()
+	replay(result, state, func(string, []int) {})
+	state.finish(result)
+	c.drawAll(state)
+
+	return png.Encode(w, c.img)
+}
+
+// RenderBatch fails, a picture only shows a single mission
+func (PNG) RenderBatch(io.Writer, []BatchMission) error {
+	return ErrImageBatch
+}
+
+// GIF animates the mission as drawn by the png format, with one frame per step or per tick in lockstep mode. The animation loops, its last frame staying up longer.
+// Frames only hold the cells that changed and steps changing nothing, such as blocked moves, keep the previous frame up instead, so long missions stay small.
+// A frame kept up longer than a gif frame can stay is followed by a copy of one of its pixels holding it up for the rest of the time.
+// A mission whose frames would take over maxAnimationBytes fails with ErrAnimationTooLong before anything is written
+type GIF struct {
+	CellSize   int           // side of a plateau cell, in pixels
+	FrameDelay time.Duration // time every frame is shown, rounded down to hundredths of a second
+}
+
+func (GIF) ContentType() string {
+	return "image/gif"
+}
+
+func (g GIF) Render(w io.Writer, result *rover.MissionResult) error {
+	c, err := newCanvas(result, g.CellSize)
+	if err != nil {
+		return err
+	}
+
+	delay := max(1, int(g.FrameDelay/(10*time.Millisecond)))
+	bounds := c.img.Bounds()
+	anim := &gif.GIF{
+		Config: image.Config{ColorModel: imagePalette, Width: bounds.Dx(), Height: bounds.Dy()},
+	}
+
+	var size int
+	frame := func(changed image.Rectangle) error {
+		if len(anim.Image) == 0 {
+			changed = bounds
+		}
+
+		size += changed.Dx()*changed.Dy() + frameOverhead
+		if size > maxAnimationBytes {
+			return fmt.Errorf("%w: over %d frames", ErrAnimationTooLong, len(anim.Image))
+		}
+
+		anim.Image = append(anim.Image, crop(c.img, changed))
+		anim.Delay = append(anim.Delay, 0)
+		return nil
+	}
+
+	// hold keeps the last frame up that much longer, going on in a copy of one of its pixels once its delay is as long as a gif frame can have
+	hold := func(d int) error {
+		for d > 0 {
+			last := len(anim.Delay) - 1
+			if anim.Delay[last] == maxGIFDelay {
+				if err := frame(image.Rect(0, 0, 1, 1)); err != nil {
+					return err
+				}
+				continue
+			}
+
+			add := min(d, maxGIFDelay-anim.Delay[last])
+			anim.Delay[last] += add
+			d -= add
+		}
+		return nil
+	}
+
+	state := newGridState()
+	replay(result, state, func(_ string, moved []int) {
+		if err != nil {
+			return
+		}
+
+		// steps changing nothing, such as blocked moves, keep the previous frame up
+		if changed := c.draw(state, moved); !changed.Empty() || len(anim.Image) == 0 {
+			if err = frame(changed); err != nil {
+				return
+			}
+		}
+		err = hold(delay)
+	})
+	if err != nil {
+		return err
+	}
+
+	// the rovers end up where the last step left them, unless no trace was recorded
+	state.finish(result)
+	if changed := c.draw(state, roverIDs(result)); !changed.Empty() || len(anim.Image) == 0 {
+		if err := frame(changed); err != nil {
+			return err
+		}
+		if err := hold(delay); err != nil {
+			return err
+		}
+	}
+	if err := hold((finalFrameHold - 1) * delay); err != nil {
+		return err
+	}
+
+	return gif.EncodeAll(w, anim)
+}
+
+// RenderBatch fails, an animation only shows a single mission
+func (GIF) RenderBatch(io.Writer, []BatchMission) error {
+	return ErrImageBatch
+}
+
+// roverIDs returns the ids of every rover of the mission
+func roverIDs(result *rover.MissionResult) []int {
+	ids := make([]int, 0, len(result.Rovers))
+	for _, rr := range result.Rovers {
+		ids = append(ids, rr.ID)
+	}
+	return ids
+}
+
+// crop returns a copy of the part of the picture within r
+func crop(img *image.Paletted, r image.Rectangle) *image.Paletted {
+	part := image.NewPaletted(r, img.Palette)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		copy(part.Pix[part.PixOffset(r.Min.X, y):part.PixOffset(r.Max.X, y)], img.Pix[img.PixOffset(r.Min.X, y):img.PixOffset(r.Max.X, y)])
+	}
+	return part
+}
+
+// cellMark is what a cell shows: the tint of the last rover through it and the rover on it, 0 for none
+type cellMark struct {
+	visitor int
+	rover   int
+	heading rover.Direction
+}
+
+// canvas draws the states of a mission on a picture of its plateau, one pixel wide grid lines around cells of the given size.
+// Only the cells of the rovers that moved since the previous state are drawn again
+type canvas struct {
+	img      *image.Paletted
+	maxY     int
+	cellSize int
+	ranks    map[int]int                    // deployment rank of every rover by id, picking its colour
+	drawn    map[rover.Coordinates]cellMark // what the cells show, cells still blank are not in it
+	rovers   map[int]rover.Coordinates      // cell every rover was drawn on by id
+}
+
+func newCanvas(result *rover.MissionResult, cellSize int) (*canvas, error) {
+	maxX, maxY := result.Plateau.MaxX(), result.Plateau.MaxY()
+	// every side is checked before it is multiplied so a huge plateau cannot overflow the size of the picture
+	if maxX >= maxImagePixels/cellSize || maxY >= maxImagePixels/cellSize {
+		return nil, fmt.Errorf("%w: %d x %d cells of %d pixels", ErrImageTooLarge, maxX+1, maxY+1, cellSize)
+	}
+	width, height := (maxX+1)*cellSize+1, (maxY+1)*cellSize+1
+	if width > maxImagePixels/height {
+		return nil, fmt.Errorf("%w: %d x %d pixels", ErrImageTooLarge, width, height)
+	}
+
+	c := &canvas{
+		img:      image.NewPaletted(image.Rect(0, 0, width, height), imagePalette),
+		maxY:     maxY,
+		cellSize: cellSize,
+		ranks:    make(map[int]int, len(result.Rovers)),
+		drawn:    map[rover.Coordinates]cellMark{},
+		rovers:   map[int]rover.Coordinates{},
+	}
+
+	for i, rr := range result.Rovers {
+		c.ranks[rr.ID] = i
+	}
+
+	for x := 0; x < width; x += cellSize {
+		for y := range height {
+			c.img.SetColorIndex(x, y, colourGrid)
+		}
+	}
+	for y := 0; y < height; y += cellSize {
+		for x := range width {
+			c.img.SetColorIndex(x, y, colourGrid)
+		}
+	}
+
+	for _, o := range result.Plateau.Obstacles() {
+		from, to := o.Bounds()
+		for x := max(from.X(), 0); x <= min(to.X(), maxX); x++ {
+			for y := max(from.Y(), 0); y <= min(to.Y(), maxY); y++ {
+				c.fill(c.cellBounds(rover.NewCoordinates(x, y)), colourObstacle)
+			}
+		}
+	}
+
+	return c, nil
+}
+
+// draw brings the picture up to date with the state, the given rovers having moved since it was last drawn. It returns the bounds of the pixels drawn again, empty if nothing changed
+func (c *canvas) draw(s *gridState, moved []int) image.Rectangle {
+	var changed image.Rectangle
+
+	// a rover that moved can only have left the cell it was drawn on for the one it is on now
+	for _, id := range moved {
+		if at, ok := c.rovers[id]; ok {
+			changed = changed.Union(c.update(s, at))
+			delete(c.rovers, id)
+		}
+		if p, ok := s.rovers[id]; ok {
+			changed = changed.Union(c.update(s, cell(p)))
+		}
+	}
+	for _, id := range moved {
+		if p, ok := s.rovers[id]; ok {
+			c.rovers[id] = cell(p)
+		}
+	}
+
+	return changed
+}
+
+// drawAll draws every cell of the state, the rovers being on visited cells
+func (c *canvas) drawAll(s *gridState) {
+	for at := range s.visited {
+		c.update(s, at)
+	}
+
+	clear(c.rovers)
+	for id, p := range s.rovers {
+		c.rovers[id] = cell(p)
+	}
+}
+
+// update draws the cell again if the state changed it, returning the bounds of the pixels drawn
+func (c *canvas) update(s *gridState, at rover.Coordinates) image.Rectangle {
+	mark := cellMark{visitor: s.visited[at]}
+	if id, ok := s.cells[at]; ok {
+		p := s.rovers[id]
+		mark.rover, mark.heading = id, p.Direction()
+	}
+	if c.drawn[at] == mark {
+		return image.Rectangle{}
+	}
+
+	c.drawn[at] = mark
+	return c.paint(at, mark)
+}
+
+// paint draws the cell as marked, returning its bounds
+func (c *canvas) paint(at rover.Coordinates, mark cellMark) image.Rectangle {
+	r := c.cellBounds(at)
+
+	background := colourCell
+	if mark.visitor != 0 {
+		background = c.colour(mark.visitor) + 1
+	}
+	c.fill(r, background)
+
+	if mark.rover == 0 {
+		return r
+	}
+
+	// a triangle pointing north, turned to the heading, a sixth of the cell away from its sides
+	half := float64(r.Dx()) / 2
+	size := half - float64(r.Dx())/6
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			dx, dy := northward(float64(x-r.Min.X)+0.5-half, float64(y-r.Min.Y)+0.5-half, mark.heading)
+			if dy >= -size && dy <= size && 2*math.Abs(dx) <= 0.8*(dy+size) {
+				c.img.SetColorIndex(x, y, c.colour(mark.rover))
+			}
+		}
+	}
+	return r
+}
+
+// cellBounds returns the pixels of a cell within its grid lines, north up
+func (c *canvas) cellBounds(at rover.Coordinates) image.Rectangle {
+	x, y := at.X()*c.cellSize+1, (c.maxY-at.Y())*c.cellSize+1
+	return image.Rect(x, y, x+c.cellSize-1, y+c.cellSize-1)
+}
+
+// fill paints every pixel of r with the colour
+func (c *canvas) fill(r image.Rectangle, colour uint8) {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			c.img.SetColorIndex(x, y, colour)
+		}
+	}
+}
+
+// colour returns the palette index of the colour of the rover
+func (c *canvas) colour(id int) uint8 {
+	return colourRovers + uint8(2*(c.ranks[id]%len(svgPalette)))
+}
+
+// northward turns an offset from the centre of a cell, y pointing down, by the rotation taking the heading back to north
+func northward(dx, dy float64, heading rover.Direction) (float64, float64) {
+	switch heading {
+	case rover.E:
+		return dy, -dx
+	case rover.S:
+		return -dx, -dy
+	case rover.W:
+		return -dy, dx
+	default:
+		return dx, dy
+	}
+}
@@ -0,0 +1,189 @@
+package render_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"mars/internal/render"
+	"mars/internal/rover"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPNG_Render(t *testing.T) {
+	t.Parallel()
+
+	// the first two colours of the svg format, the cells rovers went through mixed with three parts of white
+	blue, blueTint := color.RGBA{0x1f, 0x77, 0xb4, 0xff}, color.RGBA{0xc7, 0xdd, 0xed, 0xff}
+	orange := color.RGBA{0xff, 0x7f, 0x0e, 0xff}
+	blank, obstacle := color.RGBA{0xfa, 0xfa, 0xfa, 0xff}, color.RGBA{0x55, 0x55, 0x55, 0xff}
+
+	testCases := map[string]struct {
+		input      string
+		opts       []rover.Option
+		cellSize   int
+		wantSize   image.Point
+		wantColour map[[2]int]color.Color // colour at the centre of cells
+	}{
+		"ok - rovers, visited cells and obstacles": {
+			input:    "5 5\nROCK 3 3\nZONE 4 0 5 1\n1 2 N\nMM\n5 5 S\nLM",
+			opts:     []rover.Option{rover.WithTrace()},
+			cellSize: 10,
+			wantSize: image.Pt(61, 61),
+			wantColour: map[[2]int]color.Color{
+				{1, 4}: blue,
+				{1, 3}: blueTint,
+				{1, 2}: blueTint,
+				{5, 5}: orange,
+				{3, 3}: obstacle,
+				{4, 0}: obstacle,
+				{5, 1}: obstacle,
+				{0, 0}: blank,
+			},
+		},
+		"ok - lost rover no longer drawn": {
+			input:    "3 3\n0 1 S\nMM",
+			opts:     []rover.Option{rover.WithTrace(), rover.WithBoundaryPolicy(rover.LoseOffEdge{})},
+			cellSize: 4,
+			wantSize: image.Pt(17, 17),
+			wantColour: map[[2]int]color.Color{
+				{0, 1}: blueTint,
+				{0, 0}: blueTint,
+			},
+		},
+		"ok - lockstep rovers": {
+			input:    "3 3\n0 0 E\nM\n3 3 W\nM",
+			opts:     []rover.Option{rover.WithLockstep()},
+			cellSize: 8,
+			wantSize: image.Pt(33, 33),
+			wantColour: map[[2]int]color.Color{
+				{0, 0}: blueTint,
+				{1, 0}: blue,
+				{2, 3}: orange,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, render.PNG{CellSize: tc.cellSize}.Render(&buf, simulate(t, tc.input, tc.opts...)))
+
+			img, err := png.Decode(&buf)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.wantSize, img.Bounds().Size())
+			maxY := (img.Bounds().Dy()-1)/tc.cellSize - 1
+			for at, want := range tc.wantColour {
+				got := img.At(at[0]*tc.cellSize+tc.cellSize/2, (maxY-at[1])*tc.cellSize+tc.cellSize/2)
+				assert.Equal(t, colourRGBA(want), colourRGBA(got), "cell %v", at)
+			}
+		})
+	}
+}
+
+func TestGIF_Render(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		input      string
+		opts       []rover.Option
+		frameDelay time.Duration
+		wantDelays []int
+	}{
+		"ok - one frame per step": {
+			input:      "3 3\n0 0 N\nMRM",
+			opts:       []rover.Option{rover.WithTrace()},
+			frameDelay: 100 * time.Millisecond,
+			// deployment, M, R, M, then the final state holding the last frame up
+			wantDelays: []int{10, 10, 10, 10 + 30},
+		},
+		"ok - blocked moves keep the frame up": {
+			input:      "3 3\n0 3 N\nMMR",
+			opts:       []rover.Option{rover.WithTrace()},
+			frameDelay: 50 * time.Millisecond,
+			wantDelays: []int{15, 5 + 15},
+		},
+		"ok - frame held longer than a gif frame can stay": {
+			input:      "1 1\n0 1 N\n" + strings.Repeat("M", 72),
+			opts:       []rover.Option{rover.WithTrace()},
+			frameDelay: 10 * time.Second,
+			// deployment, 72 blocked moves and the final hold add up to 76000 hundredths of a second
+			wantDelays: []int{65535, 76000 - 65535},
+		},
+		"ok - one frame per tick": {
+			input:      "3 3\n0 0 N\nMM\n3 3 S\nM",
+			opts:       []rover.Option{rover.WithLockstep()},
+			frameDelay: 5 * time.Millisecond,
+			wantDelays: []int{1, 1, 1 + 3},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			result := simulate(t, tc.input, tc.opts...)
+
+			var buf bytes.Buffer
+			require.NoError(t, render.GIF{CellSize: 8, FrameDelay: tc.frameDelay}.Render(&buf, result))
+
+			anim, err := gif.DecodeAll(&buf)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantDelays, anim.Delay)
+
+			// played to the end, the frames give the picture of the png format
+			played := image.NewPaletted(anim.Image[0].Bounds(), anim.Image[0].Palette)
+			for _, frame := range anim.Image {
+				draw.Draw(played, frame.Bounds(), frame, frame.Bounds().Min, draw.Src)
+			}
+
+			var pngBuf bytes.Buffer
+			require.NoError(t, render.PNG{CellSize: 8}.Render(&pngBuf, result))
+			final, err := png.Decode(&pngBuf)
+			require.NoError(t, err)
+
+			require.Equal(t, final.Bounds(), played.Bounds())
+			for y := range final.Bounds().Dy() {
+				for x := range final.Bounds().Dx() {
+					require.Equal(t, colourRGBA(final.At(x, y)), colourRGBA(played.At(x, y)), "pixel %d %d", x, y)
+				}
+			}
+		})
+	}
+}
+
+func TestImage_Errors(t *testing.T) {
+	t.Parallel()
+
+	result := simulate(t, "999 999\n0 0 N\nM")
+
+	var buf bytes.Buffer
+	require.ErrorIs(t, render.PNG{CellSize: 32}.Render(&buf, result), render.ErrImageTooLarge)
+	require.ErrorIs(t, render.GIF{CellSize: 32, FrameDelay: time.Second}.Render(&buf, result), render.ErrImageTooLarge)
+	assert.Zero(t, buf.Len())
+
+	// with the limits disabled this picture is 2^32+1 x 2^32-1 pixels, which overflows to -1 pixels when multiplied
+	plateau, err := rover.NewPlateau(1<<31-1, 1<<31-2, 0, 0)
+	require.NoError(t, err)
+	require.ErrorIs(t, render.PNG{CellSize: 2}.Render(&buf, &rover.MissionResult{Plateau: plateau}), render.ErrImageTooLarge)
+	assert.Zero(t, buf.Len())
+
+	// every step moves the rover, every frame holds two cells
+	long := simulate(t, "1 1\n0 0 N\n"+strings.Repeat("MRMR", 25_000), rover.WithTrace(), rover.WithBoundaryPolicy(rover.WrapAround{}))
+	require.ErrorIs(t, render.GIF{CellSize: 32, FrameDelay: time.Second}.Render(&buf, long), render.ErrAnimationTooLong)
+	assert.Zero(t, buf.Len())
+
+	require.ErrorIs(t, render.PNG{}.RenderBatch(&buf, nil), render.ErrImageBatch)
+	require.ErrorIs(t, render.GIF{}.RenderBatch(&buf, nil), render.ErrImageBatch)
+}
+
+// colourRGBA returns the colour as comparable 8 bit channels
+func colourRGBA(c color.Color) color.RGBA {
+	return color.RGBAModel.Convert(c).(color.RGBA)
+}
@@ -0,0 +1,211 @@
+package render
+
+import (
+	"encoding/json"
+	"io"
+	"mars/internal/rover"
+)
+
+// JSON renders the mission result as a single JSON document, the same one returned by the web API
+type JSON struct{}
+
+// Mission is the JSON representation of a MissionResult
+type Mission struct {
+	Rovers []Rover `json:"rovers"`
+	Ticks  []Tick  `json:"ticks,omitempty"`
+}
+
+// Rover is the JSON representation of a RoverResult
+type Rover struct {
+	ID           int           `json:"id"`
+	Name         string        `json:"name,omitempty"`
+	X            int           `json:"x"`
+	Y            int           `json:"y"`
+	Heading      string        `json:"heading"`
+	Status       string        `json:"status"`
+	Start        Position      `json:"start"`
+	Error        string        `json:"error,omitempty"`
+	IgnoredMoves []IgnoredMove `json:"ignoredMoves"`
+	Trace        []TraceStep   `json:"trace,omitempty"`
+}
+
+// Batch is the JSON representation of the combined report of a batch of missions
+type Batch struct {
+	Missions  []BatchEntry `json:"missions"`
+	Succeeded int          `json:"succeeded"`
+	Failed    int          `json:"failed"`
+}
+
+// BatchEntry is the JSON representation of a BatchMission, holding either its result or its error
+type BatchEntry struct {
+	ID     int      `json:"id"`
+	Name   string   `json:"name,omitempty"`
+	Line   int      `json:"line"`
+	OK     bool     `json:"ok"`
+	Result *Mission `json:"result,omitempty"`
+	Error  string   `json:"error,omitempty"`
+}
+
+type IgnoredMove struct {
+	Step    int    `json:"step"`
+	X       int    `json:"x"`
+	Y       int    `json:"y"`
+	Heading string `json:"heading"`
+	Reason  string `json:"reason"`
+}
+
+type Position struct {
+	X       int    `json:"x"`
+	Y       int    `json:"y"`
+	Heading string `json:"heading"`
+}
+
+type TraceStep struct {
+	Step    int      `json:"step"`
+	Command string   `json:"command"`
+	Before  Position `json:"before"`
+	After   Position `json:"after"`
+	Outcome string   `json:"outcome"`
+}
+
+type Tick struct {
+	Tick   int          `json:"tick"`
+	Rovers []RoverState `json:"rovers"`
+}
+
+type RoverState struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name,omitempty"`
+	X       int    `json:"x"`
+	Y       int    `json:"y"`
+	Heading string `json:"heading"`
+	Status  string `json:"status"`
+}
+
+func (JSON) ContentType() string {
+	return "application/json"
+}
+
+func (JSON) Render(w io.Writer, result *rover.MissionResult) error {
+	return json.NewEncoder(w).Encode(NewMission(result))
+}
+
+func (JSON) RenderBatch(w io.Writer, missions []BatchMission) error {
+	return json.NewEncoder(w).Encode(NewBatch(missions))
+}
+
+// NewBatch maps the missions of a batch to the JSON representation of their combined report
+func NewBatch(missions []BatchMission) Batch {
+	resp := Batch{
+		Missions: make([]BatchEntry, 0, len(missions)),
+	}
+
+	for _, m := range missions {
+		entry := BatchEntry{
+			ID:   m.ID,
+			Name: m.Name,
+			Line: m.Line,
+			OK:   m.OK(),
+		}
+
+		if m.OK() {
+			mission := NewMission(m.Result)
+			entry.Result = &mission
+			resp.Succeeded++
+		} else {
+			entry.Error = m.Err.Error()
+			resp.Failed++
+		}
+
+		resp.Missions = append(resp.Missions, entry)
+	}
+
+	return resp
+}
+
+// NewMission maps a MissionResult to its JSON representation
+func NewMission(result *rover.MissionResult) Mission {
+	resp := Mission{
+		Rovers: NewRovers(result.Rovers),
+	}
+
+	for _, tick := range result.Ticks {
+		tickResp := Tick{
+			Tick:   tick.Number,
+			Rovers: make([]RoverState, 0, len(tick.Rovers)),
+		}
+
+		for _, rs := range tick.Rovers {
+			tickResp.Rovers = append(tickResp.Rovers, RoverState{
+				ID:      rs.ID,
+				Name:    rs.Name,
+				X:       rs.Position.X(),
+				Y:       rs.Position.Y(),
+				Heading: rs.Position.Direction().String(),
+				Status:  rs.Status.String(),
+			})
+		}
+
+		resp.Ticks = append(resp.Ticks, tickResp)
+	}
+
+	return resp
+}
+
+// NewRover maps a RoverResult to its JSON representation
+func NewRover(rr rover.RoverResult) Rover {
+	ignored := make([]IgnoredMove, 0, len(rr.IgnoredMoves))
+	for _, im := range rr.IgnoredMoves {
+		ignored = append(ignored, IgnoredMove{
+			Step:    im.Step,
+			X:       im.Target.X(),
+			Y:       im.Target.Y(),
+			Heading: im.Target.Direction().String(),
+			Reason:  im.Reason.Error(),
+		})
+	}
+
+	resp := Rover{
+		ID:           rr.ID,
+		Name:         rr.Name,
+		X:            rr.Position.X(),
+		Y:            rr.Position.Y(),
+		Heading:      rr.Position.Direction().String(),
+		Status:       rr.Status.String(),
+		Start:        NewPosition(rr.Start),
+		IgnoredMoves: ignored,
+	}
+	if rr.Err != nil {
+		resp.Error = rr.Err.Error()
+	}
+
+	for _, ts := range rr.Trace {
+		resp.Trace = append(resp.Trace, TraceStep{
+			Step:    ts.Step,
+			Command: ts.Command.String(),
+			Before:  NewPosition(ts.Before),
+			After:   NewPosition(ts.After),
+			Outcome: ts.Outcome.String(),
+		})
+	}
+
+	return resp
+}
+
+// NewRovers maps the results of several rovers to their JSON representation
+func NewRovers(results []rover.RoverResult) []Rover {
+	resp := make([]Rover, 0, len(results))
+	for _, rr := range results {
+		resp = append(resp, NewRover(rr))
+	}
+	return resp
+}
+
+// NewPosition maps a Position to its JSON representation
+func NewPosition(p rover.Position) Position {
+	return Position{
+		X:       p.X(),
+		Y:       p.Y(),
+		Heading: p.Direction().String(),
+	}
+}
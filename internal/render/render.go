@@ -0,0 +1,110 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"mars/internal/rover"
+	"strconv"
+	"time"
+)
+
+// Names of the output formats, as accepted by New
+const (
+	FormatText      = "text"
+	FormatJSON      = "json"
+	FormatCSV       = "csv"
+	FormatTable     = "table"
+	FormatGrid      = "grid"
+	FormatGridSteps = "grid-steps"
+	FormatSVG       = "svg"
+	FormatPNG       = "png"
+	FormatGIF       = "gif"
+)
+
+// Renderer writes a mission result, or the combined report of a batch of missions, in a given output format
+type Renderer interface {
+	Render(w io.Writer, result *rover.MissionResult) error
+	RenderBatch(w io.Writer, missions []BatchMission) error
+	ContentType() string // media type of the rendered output, as sent by the web API
+}
+
+// BatchMission is the outcome of one mission of a batch: its result when it ran, the error that stopped it otherwise
+type BatchMission struct {
+	ID     int    // 1-based position of the mission in the batch
+	Name   string // name given on the separator line, empty if none
+	Line   int    // line of the batch input the mission starts on
+	Result *rover.MissionResult
+	Err    error
+}
+
+// OK reports whether the mission ran to completion
+func (m BatchMission) OK() bool {
+	return m.Err == nil
+}
+
+// Label returns the mission name, or its number when it has none
+func (m BatchMission) Label() string {
+	if m.Name != "" {
+		return m.Name
+	}
+	return strconv.Itoa(m.ID)
+}
+
+// Option tunes a renderer, renderers ignore the options they have no use for
+type Option func(*options)
+
+// options holds the settings of the renderers drawing images
+type options struct {
+	cellSize   int
+	frameDelay time.Duration
+}
+
+// WithCellSize sets the side of a plateau cell in the png and gif formats, in pixels
+func WithCellSize(pixels int) Option {
+	return func(o *options) {
+		o.cellSize = pixels
+	}
+}
+
+// WithFrameDelay sets the time every frame of the gif format is shown
+func WithFrameDelay(d time.Duration) Option {
+	return func(o *options) {
+		o.frameDelay = d
+	}
+}
+
+// New returns the renderer for the named output format
+func New(format string, opts ...Option) (Renderer, error) {
+	o := options{cellSize: defaultCellSize, frameDelay: defaultFrameDelay}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	switch format {
+	case FormatText:
+		return Text{}, nil
+	case FormatJSON:
+		return JSON{}, nil
+	case FormatCSV:
+		return CSV{}, nil
+	case FormatTable:
+		return Table{}, nil
+	case FormatGrid:
+		return Grid{}, nil
+	case FormatGridSteps:
+		return Grid{Steps: true}, nil
+	case FormatSVG:
+		return SVG{}, nil
+	case FormatPNG:
+		return PNG{CellSize: o.cellSize}, nil
+	case FormatGIF:
+		return GIF{CellSize: o.cellSize, FrameDelay: o.frameDelay}, nil
+	default:
+		return nil, fmt.Errorf("%w: (got %q)", ErrFormatUnknown, format)
+	}
+}
+
+// blockedMoves returns the number of moves the rover had to ignore
+func blockedMoves(rr rover.RoverResult) int {
+	return len(rr.IgnoredMoves)
+}
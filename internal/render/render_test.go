@@ -0,0 +1,121 @@
+package render
+
+import (
+	"context"
+	"mars/internal/rover"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderers(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		format          string
+		trace           bool
+		wantContentType string
+		wantOutput      string
+	}{
+		"ok - text": {
+			format:          FormatText,
+			wantContentType: "text/plain; charset=utf-8",
+			wantOutput:      "1 3 N\nalpha: 5 5 E\n",
+		},
+		"ok - text with trace": {
+			format:          FormatText,
+			trace:           true,
+			wantContentType: "text/plain; charset=utf-8",
+			wantOutput: "rover 1 step 1 M: 1 2 N -> 1 3 N moved\n" +
+				"rover alpha step 1 M: 5 5 E -> 5 5 E blocked-by-boundary\n" +
+				"1 3 N\nalpha: 5 5 E\n",
+		},
+		"ok - json": {
+			format:          FormatJSON,
+			wantContentType: "application/json",
+			wantOutput: `{"rovers":[{"id":1,"x":1,"y":3,"heading":"N","status":"operational","start":{"x":1,"y":2,"heading":"N"},"ignoredMoves":[]},` +
+				`{"id":2,"name":"alpha","x":5,"y":5,"heading":"E","status":"operational","start":{"x":5,"y":5,"heading":"E"},"ignoredMoves":[{"step":1,"x":6,"y":5,"heading":"E","reason":"position must be more than 0 and within boundaries"}]}]}` + "\n",
+		},
+		"ok - csv": {
+			format:          FormatCSV,
+			wantContentType: "text/csv; charset=utf-8",
+			wantOutput: "id,name,start_x,start_y,start_heading,x,y,heading,status,blocked_moves,error\n" +
+				"1,,1,2,N,1,3,N,operational,0,\n" +
+				"2,alpha,5,5,E,5,5,E,operational,1,\n",
+		},
+		"ok - table": {
+			format:          FormatTable,
+			wantContentType: "text/plain; charset=utf-8",
+			wantOutput: "ID  NAME   START  FINAL  STATUS       BLOCKED\n" +
+				"1   -      1 2 N  1 3 N  operational  0\n" +
+				"2   alpha  5 5 E  5 5 E  operational  1\n",
+		},
+		"ok - grid": {
+			format:          FormatGrid,
+			trace:           true,
+			wantContentType: "text/plain; charset=utf-8",
+			wantOutput: "5 .  .  .  .  .  >2\n" +
+				"4 .  .  .  .  .  .\n" +
+				"3 .  ^1 .  .  .  .\n" +
+				"2 .  o  .  .  .  .\n" +
+				"1 .  .  .  .  .  .\n" +
+				"0 .  .  .  .  .  .\n" +
+				"  0  1  2  3  4  5\n" +
+				"1: 1 3 N\n" +
+				"2 alpha: 5 5 E\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			renderer, err := New(tc.format)
+			require.NoError(t, err)
+
+			var sb strings.Builder
+			require.NoError(t, renderer.Render(&sb, missionResult(t, tc.trace)))
+
+			assert.Equal(t, tc.wantContentType, renderer.ContentType())
+			assert.Equal(t, tc.wantOutput, sb.String())
+		})
+	}
+}
+
+func TestNew_UnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	_, err := New("yaml")
+	require.ErrorIs(t, err, ErrFormatUnknown)
+}
+
+// missionResult runs two rovers on a 5 x 5 plateau, the second one named and blocked once by the edge
+func missionResult(t *testing.T, trace bool) *rover.MissionResult {
+	t.Helper()
+
+	plateau, err := rover.NewPlateau(5, 5, 2, 2)
+	require.NoError(t, err)
+
+	first, err := rover.NewPosition(plateau, rover.NewCoordinates(1, 2), rover.N)
+	require.NoError(t, err)
+	second, err := rover.NewPosition(plateau, rover.NewCoordinates(5, 5), rover.E)
+	require.NoError(t, err)
+
+	var opts []rover.Option
+	if trace {
+		opts = append(opts, rover.WithTrace())
+	}
+
+	mc, err := rover.NewMissionControl(plateau, opts...)
+	require.NoError(t, err)
+
+	result, err := mc.Simulate(context.Background(), &rover.MissionControlInput{
+		Instructions: []rover.RoverInstruction{
+			{InitialPosition: first, Commands: "M"},
+			{Name: "alpha", InitialPosition: second, Commands: "M"},
+		},
+	})
+	require.NoError(t, err)
+
+	return result
+}
@@ -0,0 +1,358 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"mars/internal/rover"
+	"strings"
+)
+
+// layout of the drawing, in pixels
+const (
+	svgCell      = 40 // side of a plateau cell
+	svgMargin    = 32 // room around the plateau, the coordinates are written in it
+	svgLine      = 20 // height of a legend line
+	svgTitle     = 28 // height of the title line of a mission of a batch
+	svgCharWidth = 8  // width of a character of the monospace font, wide enough to lay out the legend
+)
+
+// size of the drawing, in bytes
+const (
+	maxSVGBytes     = 32_000_000 // largest drawing, every mission of a batch together
+	svgElementBytes = 256        // most an element of the drawing takes, the label of a rover it holds aside
+	svgPointBytes   = 24         // most a point of a rover path takes
+)
+
+// svgPalette colours the rovers in deployment order, starting over once every colour is taken
+var svgPalette = []string{"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd", "#8c564b", "#e377c2", "#7f7f7f", "#bcbd22", "#17becf"}
+
+// SVG draws the mission as a scalable image: the plateau grid with its coordinates and obstacles, the path of every rover from a start marker to an end marker, the moves that were blocked and a legend.
+// Paths follow the trace of the mission, without one only where the rovers started and ended up is known. Every element has a class naming what it shows, for styling.
+// A drawing that would take over maxSVGBytes fails with ErrSVGTooLarge before anything is drawn
+type SVG struct{}
+
+func (SVG) ContentType() string {
+	return "image/svg+xml"
+}
+
+func (SVG) Render(w io.Writer, result *rover.MissionResult) error {
+	m, err := newSVGMission(result)
+	if err != nil {
+		return err
+	}
+	if size := m.size(); size > maxSVGBytes {
+		return fmt.Errorf("%w: about %d bytes", ErrSVGTooLarge, size)
+	}
+
+	var sb strings.Builder
+	svgOpen(&sb, m.width, m.height)
+	m.write(&sb)
+	sb.WriteString("</svg>\n")
+
+	_, err = io.WriteString(w, sb.String())
+	return err
+}
+
+// RenderBatch draws the missions one below the other in a single image, each one under a title line telling whether it ran. Failed missions only show their error
+func (SVG) RenderBatch(w io.Writer, missions []BatchMission) error {
+	titles := make([]string, 0, len(missions))
+	laidOut := make([]*svgMission, 0, len(missions))
+	var size int
+
+	for _, bm := range missions {
+		title := fmt.Sprintf("%s (line %d): ok", batchHeader(bm), bm.Line)
+		if !bm.OK() {
+			title = fmt.Sprintf("%s (line %d): failed: %v", batchHeader(bm), bm.Line, bm.Err)
+		}
+		titles = append(titles, title)
+		size += svgElementBytes + len(html.EscapeString(title))
+
+		var m *svgMission
+		if bm.OK() {
+			var err error
+			if m, err = newSVGMission(bm.Result); err != nil {
+				return fmt.Errorf("mission %d: %w", bm.ID, err)
+			}
+			size += m.size()
+		}
+		laidOut = append(laidOut, m)
+
+		if size > maxSVGBytes {
+			return fmt.Errorf("%w: about %d bytes by mission %d", ErrSVGTooLarge, size, bm.ID)
+		}
+	}
+
+	var body strings.Builder
+	var width, height int
+
+	for i, bm := range missions {
+		title := titles[i]
+		fmt.Fprintf(&body, "<text class=\"mission\" x=\"%d\" y=\"%d\" font-weight=\"bold\">%s</text>\n", svgMargin/2, height+svgTitle-8, html.EscapeString(title))
+		width = max(width, svgMargin+len(title)*svgCharWidth)
+		height += svgTitle
+
+		if !bm.OK() {
+			continue
+		}
+
+		m := laidOut[i]
+		fmt.Fprintf(&body, "<g transform=\"translate(0 %d)\">\n", height)
+		m.write(&body)
+		body.WriteString("</g>\n")
+		width = max(width, m.width)
+		height += m.height
+	}
+
+	var sb strings.Builder
+	svgOpen(&sb, width, height)
+	sb.WriteString(body.String())
+	sb.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// svgOpen writes the opening tag of an image of the given size, and its white background
+func svgOpen(sb *strings.Builder, width, height int) {
+	fmt.Fprintf(sb, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" font-family=\"monospace\" font-size=\"12\">\n", width, height, width, height)
+	fmt.Fprintf(sb, "<rect width=\"%d\" height=\"%d\" fill=\"#ffffff\"/>\n", width, height)
+}
+
+// svgMission lays out the drawing of a mission: the plateau on the left, the legend on its right
+type svgMission struct {
+	result *rover.MissionResult
+	paths  map[int][][]rover.Coordinates // cells each rover went through by id, split wherever it did not move to a neighbouring cell such as across the edge of a wrapping plateau
+	width  int
+	height int
+}
+
+// newSVGMission lays out the drawing of the mission, failing with ErrSVGTooLarge when its plateau alone would take over maxSVGBytes.
+// The plateau is checked before its size is computed so a huge one cannot overflow it
+func newSVGMission(result *rover.MissionResult) (*svgMission, error) {
+	// every column and row has a grid line and a coordinate
+	maxX, maxY := result.Plateau.MaxX(), result.Plateau.MaxY()
+	if maxX >= maxSVGBytes/(2*svgElementBytes) || maxY >= maxSVGBytes/(2*svgElementBytes) {
+		return nil, fmt.Errorf("%w: %d x %d cells", ErrSVGTooLarge, maxX+1, maxY+1)
+	}
+
+	m := &svgMission{
+		result: result,
+		paths:  map[int][][]rover.Coordinates{},
+	}
+
+	state := newGridState()
+	replay(result, state, func(_ string, moved []int) {
+		for _, id := range moved {
+			if p, ok := state.rovers[id]; ok {
+				m.extend(id, cell(p))
+			}
+		}
+	})
+	for _, rr := range result.Rovers {
+		if rr.Status != rover.StatusLost {
+			m.extend(rr.ID, cell(rr.Position))
+		}
+	}
+
+	var legendWidth int
+	for _, rr := range result.Rovers {
+		legendWidth = max(legendWidth, len(legend(rr)))
+	}
+	legendWidth = max(legendWidth, len("blocked move"))*svgCharWidth + 2*svgLine
+
+	plateauHeight := (result.Plateau.MaxY()+1)*svgCell + 2*svgMargin
+	legendHeight := (len(result.Rovers)+len(svgKeys)+1)*svgLine + 2*svgMargin
+
+	m.width = (result.Plateau.MaxX()+1)*svgCell + 2*svgMargin + legendWidth
+	m.height = max(plateauHeight, legendHeight)
+	return m, nil
+}
+
+// size returns the most bytes the drawing takes: the grid lines and coordinates, the obstacles, the paths, markers and legend lines of the rovers, and the legend keys
+func (m *svgMission) size() int {
+	plateau := m.result.Plateau
+	size := (plateau.MaxX()+plateau.MaxY()+2)*2*svgElementBytes + (3+len(svgKeys)+len(plateau.Obstacles()))*svgElementBytes
+
+	for _, rr := range m.result.Rovers {
+		// the label of the rover is written in every marker and in the legend
+		labelled := svgElementBytes + len(legend(rr))
+		size += 4 * labelled
+
+		for _, segment := range m.paths[rr.ID] {
+			size += svgElementBytes + len(segment)*svgPointBytes
+		}
+		for _, step := range rr.Trace {
+			switch step.Outcome {
+			case rover.OutcomeBlockedByBoundary, rover.OutcomeBlockedByRover, rover.OutcomeBlockedByObstacle:
+				size += labelled
+			}
+		}
+	}
+	return size
+}
+
+// extend adds the cell to the path of the rover, unless it is still in the last one
+func (m *svgMission) extend(id int, c rover.Coordinates) {
+	segments := m.paths[id]
+	if len(segments) > 0 {
+		last := segments[len(segments)-1]
+		prev := last[len(last)-1]
+		if prev == c {
+			return
+		}
+		if abs(prev.X()-c.X())+abs(prev.Y()-c.Y()) == 1 {
+			segments[len(segments)-1] = append(last, c)
+			return
+		}
+	}
+	m.paths[id] = append(segments, []rover.Coordinates{c})
+}
+
+// x returns the horizontal centre of the cells of the column
+func (m *svgMission) x(column int) int {
+	return svgMargin + column*svgCell + svgCell/2
+}
+
+// y returns the vertical centre of the cells of the row, north up
+func (m *svgMission) y(row int) int {
+	return svgMargin + (m.result.Plateau.MaxY()-row)*svgCell + svgCell/2
+}
+
+// write draws the plateau, the rovers on it and the legend
+func (m *svgMission) write(sb *strings.Builder) {
+	m.writePlateau(sb)
+
+	for i, rr := range m.result.Rovers {
+		m.writeRover(sb, rr, svgPalette[i%len(svgPalette)])
+	}
+
+	m.writeLegend(sb)
+}
+
+// writePlateau draws the cells, their coordinates and the obstacles
+func (m *svgMission) writePlateau(sb *strings.Builder) {
+	maxX, maxY := m.result.Plateau.MaxX(), m.result.Plateau.MaxY()
+	right, bottom := svgMargin+(maxX+1)*svgCell, svgMargin+(maxY+1)*svgCell
+
+	fmt.Fprintf(sb, "<rect class=\"plateau\" x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"#fafafa\" stroke=\"#999999\"/>\n", svgMargin, svgMargin, right-svgMargin, bottom-svgMargin)
+
+	sb.WriteString("<g class=\"grid\" stroke=\"#dddddd\">\n")
+	for x := 1; x <= maxX; x++ {
+		fmt.Fprintf(sb, "<line x1=\"%[1]d\" y1=\"%[2]d\" x2=\"%[1]d\" y2=\"%[3]d\"/>\n", svgMargin+x*svgCell, svgMargin, bottom)
+	}
+	for y := 1; y <= maxY; y++ {
+		fmt.Fprintf(sb, "<line x1=\"%[1]d\" y1=\"%[2]d\" x2=\"%[3]d\" y2=\"%[2]d\"/>\n", svgMargin, svgMargin+y*svgCell, right)
+	}
+	sb.WriteString("</g>\n")
+
+	sb.WriteString("<g class=\"coordinates\" fill=\"#666666\" text-anchor=\"middle\">\n")
+	for x := 0; x <= maxX; x++ {
+		fmt.Fprintf(sb, "<text x=\"%d\" y=\"%d\">%d</text>\n", m.x(x), bottom+svgMargin/2+4, x)
+	}
+	for y := 0; y <= maxY; y++ {
+		fmt.Fprintf(sb, "<text x=\"%d\" y=\"%d\">%d</text>\n", svgMargin/2, m.y(y)+4, y)
+	}
+	sb.WriteString("</g>\n")
+
+	for _, o := range m.result.Plateau.Obstacles() {
+		from, to := o.Bounds()
+		fromX, fromY := max(from.X(), 0), max(from.Y(), 0)
+		toX, toY := min(to.X(), maxX), min(to.Y(), maxY)
+
+		fmt.Fprintf(sb, "<rect class=\"obstacle\" x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"#555555\"><title>%s</title></rect>\n",
+			m.x(fromX)-svgCell/2, m.y(toY)-svgCell/2, (toX-fromX+1)*svgCell, (toY-fromY+1)*svgCell, html.EscapeString(o.String()))
+	}
+}
+
+// writeRover draws the path of the rover with its start and end markers, and a marker for every move that was blocked
+func (m *svgMission) writeRover(sb *strings.Builder, rr rover.RoverResult, colour string) {
+	label := html.EscapeString(rr.Label())
+
+	fmt.Fprintf(sb, "<g class=\"rover\" stroke=\"%s\" fill=\"%s\">\n", colour, colour)
+
+	for _, segment := range m.paths[rr.ID] {
+		points := make([]string, 0, len(segment))
+		for _, c := range segment {
+			points = append(points, fmt.Sprintf("%d,%d", m.x(c.X()), m.y(c.Y())))
+		}
+		fmt.Fprintf(sb, "<polyline class=\"path\" points=\"%s\" fill=\"none\" stroke-width=\"3\" stroke-linejoin=\"round\" stroke-opacity=\"0.8\"/>\n", strings.Join(points, " "))
+	}
+
+	for _, step := range rr.Trace {
+		switch step.Outcome {
+		case rover.OutcomeBlockedByBoundary, rover.OutcomeBlockedByRover, rover.OutcomeBlockedByObstacle:
+			fmt.Fprintf(sb, "<g transform=\"translate(%d %d) rotate(%d)\"><line class=\"blocked\" x1=\"-8\" y1=\"-16\" x2=\"8\" y2=\"-16\" stroke-width=\"4\"/><title>rover %s step %d: %s</title></g>\n",
+				m.x(step.Before.X()), m.y(step.Before.Y()), rotation(step.Before.Direction()), label, step.Step, step.Outcome)
+		}
+	}
+
+	fmt.Fprintf(sb, "<circle class=\"start\" cx=\"%d\" cy=\"%d\" r=\"6\" fill=\"#ffffff\" stroke-width=\"3\"><title>rover %s starts at %s</title></circle>\n",
+		m.x(rr.Start.X()), m.y(rr.Start.Y()), label, rr.Start.String())
+
+	if rr.Status == rover.StatusLost {
+		fmt.Fprintf(sb, "<path class=\"lost\" transform=\"translate(%d %d)\" d=\"M-7,-7 L7,7 M-7,7 L7,-7\" fill=\"none\" stroke-width=\"3\"><title>rover %s is lost from %s</title></path>\n",
+			m.x(rr.Position.X()), m.y(rr.Position.Y()), label, rr.Position.String())
+	} else {
+		fmt.Fprintf(sb, "<polygon class=\"end\" transform=\"translate(%d %d) rotate(%d)\" points=\"0,-10 8,7 -8,7\" stroke-width=\"1\"><title>rover %s ends at %s</title></polygon>\n",
+			m.x(rr.Position.X()), m.y(rr.Position.Y()), rotation(rr.Position.Direction()), label, html.EscapeString(rr.String()))
+	}
+
+	sb.WriteString("</g>\n")
+}
+
+// svgKeys explains the markers in the legend, after the rovers
+var svgKeys = []struct {
+	name   string
+	marker string // drawn centred on the origin
+}{
+	{"start", `<circle r="6" fill="#ffffff" stroke="#333333" stroke-width="3"/>`},
+	{"end", `<polygon points="0,-10 8,7 -8,7" fill="#333333"/>`},
+	{"blocked move", `<line x1="-8" y1="0" x2="8" y2="0" stroke="#333333" stroke-width="4"/>`},
+	{"lost", `<path d="M-7,-7 L7,7 M-7,7 L7,-7" stroke="#333333" stroke-width="3"/>`},
+	{"obstacle", `<rect x="-8" y="-8" width="16" height="16" fill="#555555"/>`},
+}
+
+// writeLegend lists the rovers in their colour with their final position, then what the markers mean
+func (m *svgMission) writeLegend(sb *strings.Builder) {
+	left := (m.result.Plateau.MaxX()+1)*svgCell + 2*svgMargin
+	y := svgMargin
+
+	sb.WriteString("<g class=\"legend\">\n")
+	for i, rr := range m.result.Rovers {
+		colour := svgPalette[i%len(svgPalette)]
+		fmt.Fprintf(sb, "<line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\" stroke=\"%s\" stroke-width=\"3\"/>\n", left, y+svgLine/2, left+svgLine, y+svgLine/2, colour)
+		fmt.Fprintf(sb, "<text x=\"%d\" y=\"%d\">%s</text>\n", left+svgLine+8, y+svgLine/2+4, html.EscapeString(legend(rr)))
+		y += svgLine
+	}
+
+	y += svgLine
+	for _, key := range svgKeys {
+		fmt.Fprintf(sb, "<g transform=\"translate(%d %d)\">%s</g>\n", left+svgLine/2, y+svgLine/2, key.marker)
+		fmt.Fprintf(sb, "<text x=\"%d\" y=\"%d\">%s</text>\n", left+svgLine+8, y+svgLine/2+4, key.name)
+		y += svgLine
+	}
+	sb.WriteString("</g>\n")
+}
+
+// rotation returns the clockwise angle, in degrees, turning a marker pointing north to face the heading
+func rotation(d rover.Direction) int {
+	switch d {
+	case rover.E:
+		return 90
+	case rover.S:
+		return 180
+	case rover.W:
+		return 270
+	default:
+		return 0
+	}
+}
+
+// abs returns the absolute value of n
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
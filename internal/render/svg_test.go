@@ -0,0 +1,152 @@
+package render_test
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"mars/internal/render"
+	"mars/internal/rover"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSVG_Render(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		input      string
+		opts       []rover.Option
+		wantCounts map[string]int
+		wantPaths  []string // points of every path, in drawing order
+	}{
+		"ok - paths, markers and blocked moves": {
+			input:      "5 5\n1 2 N\nMLMRM\nalpha: 5 5 E\nM",
+			opts:       []rover.Option{rover.WithTrace()},
+			wantCounts: map[string]int{"plateau": 1, "rover": 2, "path": 2, "start": 2, "end": 2, "blocked": 1, "lost": 0, "obstacle": 0, "legend": 1},
+			wantPaths:  []string{"92,172 92,132 52,132 52,92", "252,52"},
+		},
+		"ok - obstacles": {
+			input:      "5 5\nROCK 3 3\nZONE 0 0 1 0\n1 2 N\nM",
+			opts:       []rover.Option{rover.WithTrace()},
+			wantCounts: map[string]int{"obstacle": 2, "path": 1, "blocked": 0},
+			wantPaths:  []string{"92,172 92,132"},
+		},
+		"ok - lost rover": {
+			input:      "5 5\n0 1 S\nMM",
+			opts:       []rover.Option{rover.WithTrace(), rover.WithBoundaryPolicy(rover.LoseOffEdge{})},
+			wantCounts: map[string]int{"start": 1, "end": 0, "lost": 1},
+			wantPaths:  []string{"52,212 52,252"},
+		},
+		"ok - path split across a wrapping edge": {
+			input:      "5 5\n4 0 E\nMM",
+			opts:       []rover.Option{rover.WithTrace(), rover.WithBoundaryPolicy(rover.WrapAround{})},
+			wantCounts: map[string]int{"path": 2},
+			wantPaths:  []string{"212,252 252,252", "52,252"},
+		},
+		"ok - pushed rover path": {
+			input:      "5 5\n1 0 E\n\n0 0 E\nM",
+			opts:       []rover.Option{rover.WithTrace(), rover.WithCollisionPolicy(rover.PushOnCollision{})},
+			wantCounts: map[string]int{"path": 2},
+			wantPaths:  []string{"92,252 132,252", "52,252 92,252"},
+		},
+		"ok - lockstep ticks": {
+			input:      "5 5\n0 0 N\nMM\n1 1 W\nM",
+			opts:       []rover.Option{rover.WithTrace(), rover.WithLockstep()},
+			wantCounts: map[string]int{"path": 2, "blocked": 1},
+			wantPaths:  []string{"52,252 52,212 52,172", "92,212"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var sb strings.Builder
+			require.NoError(t, render.SVG{}.Render(&sb, simulate(t, tc.input, tc.opts...)))
+
+			elements := svgElements(t, sb.String())
+			for class, want := range tc.wantCounts {
+				assert.Len(t, elements[class], want, class)
+			}
+
+			var paths []string
+			for _, el := range elements["path"] {
+				paths = append(paths, svgAttr(el, "points"))
+			}
+			assert.Equal(t, tc.wantPaths, paths)
+		})
+	}
+}
+
+func TestSVG_RenderBatch(t *testing.T) {
+	t.Parallel()
+
+	missions := []render.BatchMission{
+		{ID: 1, Name: "day <1>", Line: 1, Result: simulate(t, "5 5\n1 2 N\nM", rover.WithTrace())},
+		{ID: 2, Line: 4, Err: errors.New("no rovers")},
+	}
+
+	var sb strings.Builder
+	require.NoError(t, render.SVG{}.RenderBatch(&sb, missions))
+
+	elements := svgElements(t, sb.String())
+	assert.Len(t, elements["mission"], 2)
+	assert.Len(t, elements["plateau"], 1)
+	assert.Contains(t, sb.String(), `--- mission 1 &#34;day &lt;1&gt;&#34; (line 1): ok`)
+	assert.Contains(t, sb.String(), `--- mission 2 (line 4): failed: no rovers`)
+}
+
+func TestSVG_RenderTooLarge(t *testing.T) {
+	t.Parallel()
+
+	// every move is blocked by the edge of the plateau and gets a marker
+	blocked := strings.Repeat("M", 100_000)
+	long := simulate(t, "1 1\n0 1 N\n"+blocked+"\n1 1 N\n"+blocked, rover.WithTrace())
+
+	var sb strings.Builder
+	require.ErrorIs(t, render.SVG{}.Render(&sb, long), render.ErrSVGTooLarge)
+	assert.Zero(t, sb.Len())
+
+	// the missions of a batch are drawn together
+	half := simulate(t, "1 1\n0 1 N\n"+blocked, rover.WithTrace())
+	require.ErrorIs(t, render.SVG{}.RenderBatch(&sb, []render.BatchMission{{ID: 1, Result: half}, {ID: 2, Result: half}}), render.ErrSVGTooLarge)
+	assert.Zero(t, sb.Len())
+
+	// with the limits disabled the plateau alone is checked before its size is computed
+	plateau, err := rover.NewPlateau(3, 1<<62-2, 0, 0)
+	require.NoError(t, err)
+	require.ErrorIs(t, render.SVG{}.Render(&sb, &rover.MissionResult{Plateau: plateau}), render.ErrSVGTooLarge)
+	assert.Zero(t, sb.Len())
+}
+
+// svgElements checks the image is well-formed XML and returns its elements by class
+func svgElements(t *testing.T, svg string) map[string][]xml.StartElement {
+	t.Helper()
+
+	elements := map[string][]xml.StartElement{}
+	dec := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return elements
+		}
+		require.NoError(t, err)
+
+		if el, ok := tok.(xml.StartElement); ok {
+			if class := svgAttr(el, "class"); class != "" {
+				elements[class] = append(elements[class], el.Copy())
+			}
+		}
+	}
+}
+
+// svgAttr returns the value of the named attribute of the element, empty if it has none
+func svgAttr(el xml.StartElement, name string) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
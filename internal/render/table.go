@@ -0,0 +1,70 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"mars/internal/rover"
+	"text/tabwriter"
+)
+
+// Table renders an aligned table for humans, one row per rover with its start and final positions, status and number of blocked moves
+type Table struct{}
+
+func (Table) ContentType() string {
+	return "text/plain; charset=utf-8"
+}
+
+func (Table) Render(w io.Writer, result *rover.MissionResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "ID\tNAME\tSTART\tFINAL\tSTATUS\tBLOCKED")
+
+	for _, rr := range result.Rovers {
+		fmt.Fprintln(tw, tableRow(rr))
+	}
+
+	return tw.Flush()
+}
+
+// RenderBatch writes a single table of the rovers of every mission, a failed mission has one row with its status only. The errors of failed missions follow the table
+func (Table) RenderBatch(w io.Writer, missions []BatchMission) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "MISSION\tID\tNAME\tSTART\tFINAL\tSTATUS\tBLOCKED")
+
+	for _, m := range missions {
+		if !m.OK() {
+			fmt.Fprintf(tw, "%s\t-\t-\t-\t-\tfailed\t-\n", m.Label())
+			continue
+		}
+
+		for _, rr := range m.Result.Rovers {
+			fmt.Fprintf(tw, "%s\t%s\n", m.Label(), tableRow(rr))
+		}
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, m := range missions {
+		if m.OK() {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "mission %s (line %d): %v\n", m.Label(), m.Line, m.Err); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// tableRow returns the tab separated cells of a rover
+func tableRow(rr rover.RoverResult) string {
+	name := rr.Name
+	if name == "" {
+		name = "-"
+	}
+
+	return fmt.Sprintf("%d\t%s\t%s\t%s\t%s\t%d", rr.ID, name, rr.Start.String(), rr.Position.String(), rr.Status, blockedMoves(rr))
+}
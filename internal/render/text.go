@@ -0,0 +1,62 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"mars/internal/rover"
+)
+
+// Text renders the original output format: the trace of every rover, when one was recorded, followed by the final position of each rover one per line
+type Text struct{}
+
+func (Text) ContentType() string {
+	return "text/plain; charset=utf-8"
+}
+
+func (Text) Render(w io.Writer, result *rover.MissionResult) error {
+	for _, roverResult := range result.Rovers {
+		for _, step := range roverResult.Trace {
+			if _, err := fmt.Fprintf(w, "rover %s %s\n", roverResult.Label(), step); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, roverResult := range result.Rovers {
+		if _, err := fmt.Fprintln(w, roverResult.String()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RenderBatch writes every mission after a "--- mission" header line telling whether it ran, failed missions only show their error
+func (t Text) RenderBatch(w io.Writer, missions []BatchMission) error {
+	for _, m := range missions {
+		if !m.OK() {
+			if _, err := fmt.Fprintf(w, "%s (line %d): failed: %v\n", batchHeader(m), m.Line, m.Err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if _, err := fmt.Fprintf(w, "%s (line %d): ok\n", batchHeader(m), m.Line); err != nil {
+			return err
+		}
+		if err := t.Render(w, m.Result); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// batchHeader returns the "--- mission" line introducing a mission of a batch, with its name if it has one
+func batchHeader(m BatchMission) string {
+	header := fmt.Sprintf("--- mission %d", m.ID)
+	if m.Name != "" {
+		header += fmt.Sprintf(" %q", m.Name)
+	}
+	return header
+}
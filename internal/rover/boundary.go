@@ -0,0 +1,90 @@
+package rover
+
+import "fmt"
+
+// BoundaryAction tells MissionControl how to handle a move that would take a rover off the plateau
+type BoundaryAction int
+
+const (
+	BoundaryActionUnknown BoundaryAction = iota
+	BoundaryActionStop                   // ignore the move and carry on with the remaining commands
+	BoundaryActionMove                   // move to the position returned by the policy instead
+	BoundaryActionLose                   // the rover drives off the edge and is lost, leaving a scent on its last cell
+	BoundaryActionAbort                  // stop processing the rover's commands and report an error
+)
+
+// Names of the built-in boundary policies, as accepted by NewBoundaryPolicy
+const (
+	BoundaryStop   = "stop"
+	BoundaryWrap   = "wrap"
+	BoundaryLost   = "lost"
+	BoundaryStrict = "strict"
+)
+
+// BoundaryPolicy decides what happens when a rover at from attempts to move to the out of bounds position to
+type BoundaryPolicy interface {
+	OffPlateau(plateau *Plateau, from, to Position) (BoundaryAction, Position)
+}
+
+// StopAtEdge keeps the rover on the edge of the plateau, ignoring the move (default)
+type StopAtEdge struct{}
+
+// WrapAround treats the plateau as a torus, a rover leaving one edge re-enters from the opposite one
+type WrapAround struct{}
+
+// LoseOffEdge removes a rover that drives off the plateau. The scent it leaves protects later rovers from falling off the same cell
+type LoseOffEdge struct{}
+
+// AbortAtEdge stops the rover at its current position and reports an error
+type AbortAtEdge struct{}
+
+func (StopAtEdge) OffPlateau(_ *Plateau, from, _ Position) (BoundaryAction, Position) {
+	return BoundaryActionStop, from
+}
+
+func (WrapAround) OffPlateau(plateau *Plateau, _, to Position) (BoundaryAction, Position) {
+	width, height := plateau.maxX+1, plateau.maxY+1
+
+	to.coordinates.x = ((to.coordinates.x % width) + width) % width
+	to.coordinates.y = ((to.coordinates.y % height) + height) % height
+
+	return BoundaryActionMove, to
+}
+
+func (LoseOffEdge) OffPlateau(_ *Plateau, from, _ Position) (BoundaryAction, Position) {
+	return BoundaryActionLose, from
+}
+
+func (AbortAtEdge) OffPlateau(_ *Plateau, from, _ Position) (BoundaryAction, Position) {
+	return BoundaryActionAbort, from
+}
+
+// NewBoundaryPolicy returns the built-in BoundaryPolicy registered under the given name or an error if there is none
+func NewBoundaryPolicy(name string) (BoundaryPolicy, error) {
+	switch name {
+	case BoundaryStop:
+		return StopAtEdge{}, nil
+	case BoundaryWrap:
+		return WrapAround{}, nil
+	case BoundaryLost:
+		return LoseOffEdge{}, nil
+	case BoundaryStrict:
+		return AbortAtEdge{}, nil
+	}
+	return nil, fmt.Errorf("%w: given %q", ErrBoundaryPolicyUnknown, name)
+}
+
+// WithBoundaryPolicy sets the policy MissionControl applies when a rover attempts to leave the plateau
+func WithBoundaryPolicy(bp BoundaryPolicy) Option {
+	return func(mc *MissionControl) {
+		mc.boundary = bp
+	}
+}
+
+// boundaryPolicy returns the configured BoundaryPolicy, defaulting to StopAtEdge
+func (mc *MissionControl) boundaryPolicy() BoundaryPolicy {
+	if mc.boundary == nil {
+		return StopAtEdge{}
+	}
+	return mc.boundary
+}
@@ -0,0 +1,169 @@
+package rover
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBoundaryPolicy(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		name       string
+		wantPolicy BoundaryPolicy
+		wantErr    error
+	}{
+		"ok - stop":             {name: BoundaryStop, wantPolicy: StopAtEdge{}},
+		"ok - wrap":             {name: BoundaryWrap, wantPolicy: WrapAround{}},
+		"ok - lost":             {name: BoundaryLost, wantPolicy: LoseOffEdge{}},
+		"ok - strict":           {name: BoundaryStrict, wantPolicy: AbortAtEdge{}},
+		"err - unknown policy":  {name: "bounce", wantErr: ErrBoundaryPolicyUnknown},
+		"err - empty policy":    {name: "", wantErr: ErrBoundaryPolicyUnknown},
+		"err - case sensitive":  {name: "STOP", wantErr: ErrBoundaryPolicyUnknown},
+		"err - trailing spaces": {name: "wrap ", wantErr: ErrBoundaryPolicyUnknown},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			policy, err := NewBoundaryPolicy(tc.name)
+
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantPolicy, policy)
+		})
+	}
+}
+
+func TestWrapAround(t *testing.T) {
+	t.Parallel()
+	plateau := &Plateau{maxX: 5, maxY: 3}
+
+	testCases := map[string]struct {
+		to         Position
+		wantTarget Position
+	}{
+		"north edge": {
+			to:         Position{coordinates: Coordinates{x: 2, y: 4}, direction: N},
+			wantTarget: Position{coordinates: Coordinates{x: 2, y: 0}, direction: N},
+		},
+		"east edge": {
+			to:         Position{coordinates: Coordinates{x: 6, y: 1}, direction: E},
+			wantTarget: Position{coordinates: Coordinates{x: 0, y: 1}, direction: E},
+		},
+		"south edge": {
+			to:         Position{coordinates: Coordinates{x: 2, y: -1}, direction: S},
+			wantTarget: Position{coordinates: Coordinates{x: 2, y: 3}, direction: S},
+		},
+		"west edge": {
+			to:         Position{coordinates: Coordinates{x: -1, y: 1}, direction: W},
+			wantTarget: Position{coordinates: Coordinates{x: 5, y: 1}, direction: W},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			action, target := WrapAround{}.OffPlateau(plateau, Position{}, tc.to)
+
+			assert.Equal(t, BoundaryActionMove, action)
+			assert.Equal(t, tc.wantTarget, target)
+		})
+	}
+}
+
+func TestMissionControlSimulate_BoundaryPolicies(t *testing.T) {
+	t.Parallel()
+	testPlateau := createTestPlateau(t, 5, 5)
+
+	testCases := map[string]struct {
+		policy      BoundaryPolicy
+		input       []RoverInstruction
+		wantOutput  []string
+		wantStatus  []RoverStatus
+		wantIgnored []int
+	}{
+		"stop - rover stays on the edge": {
+			policy: StopAtEdge{},
+			input: []RoverInstruction{
+				*createTestSingleRoverInstruction(t, testPlateau, 5, 4, N, "MMRM"),
+			},
+			wantOutput:  []string{"5 5 E"},
+			wantStatus:  []RoverStatus{StatusOperational},
+			wantIgnored: []int{2},
+		},
+		"wrap - rover re-enters from the opposite edge": {
+			policy: WrapAround{},
+			input: []RoverInstruction{
+				*createTestSingleRoverInstruction(t, testPlateau, 5, 4, N, "MMRM"),
+			},
+			wantOutput:  []string{"0 0 E"},
+			wantStatus:  []RoverStatus{StatusOperational},
+			wantIgnored: []int{0},
+		},
+		"wrap - wrapped cell occupied is a collision": {
+			policy: WrapAround{},
+			input: []RoverInstruction{
+				*createTestSingleRoverInstruction(t, testPlateau, 2, 0, N, ""),
+				*createTestSingleRoverInstruction(t, testPlateau, 2, 5, N, "M"),
+			},
+			wantOutput:  []string{"2 0 N", "2 5 N"},
+			wantStatus:  []RoverStatus{StatusOperational, StatusOperational},
+			wantIgnored: []int{0, 1},
+		},
+		"lost - rover falls off and its scent protects the next one": {
+			policy: LoseOffEdge{},
+			input: []RoverInstruction{
+				*createTestSingleRoverInstruction(t, testPlateau, 3, 4, N, "MMRM"),
+				*createTestSingleRoverInstruction(t, testPlateau, 3, 3, N, "MMMLM"),
+			},
+			wantOutput:  []string{"3 5 N LOST", "2 5 W"},
+			wantStatus:  []RoverStatus{StatusLost, StatusOperational},
+			wantIgnored: []int{0, 1},
+		},
+		"lost - a lost rover frees its cell": {
+			policy: LoseOffEdge{},
+			input: []RoverInstruction{
+				*createTestSingleRoverInstruction(t, testPlateau, 0, 0, S, "M"),
+				*createTestSingleRoverInstruction(t, testPlateau, 0, 0, E, "M"),
+			},
+			wantOutput:  []string{"0 0 S LOST", "1 0 E"},
+			wantStatus:  []RoverStatus{StatusLost, StatusOperational},
+			wantIgnored: []int{0, 0},
+		},
+		"strict - rover aborts on the edge": {
+			policy: AbortAtEdge{},
+			input: []RoverInstruction{
+				*createTestSingleRoverInstruction(t, testPlateau, 0, 1, S, "MMLM"),
+				*createTestSingleRoverInstruction(t, testPlateau, 3, 3, N, "M"),
+			},
+			wantOutput:  []string{"0 0 S ABORTED", "3 4 N"},
+			wantStatus:  []RoverStatus{StatusAborted, StatusOperational},
+			wantIgnored: []int{0, 0},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mc, err := NewMissionControl(testPlateau, WithBoundaryPolicy(tc.policy))
+			require.NoError(t, err)
+
+			result, err := mc.Simulate(context.Background(), &MissionControlInput{Instructions: tc.input})
+			require.NoError(t, err)
+
+			for i, rr := range result.Rovers {
+				assert.Equal(t, tc.wantOutput[i], rr.String())
+				assert.Equal(t, tc.wantStatus[i], rr.Status)
+				assert.Len(t, rr.IgnoredMoves, tc.wantIgnored[i])
+
+				if rr.Status == StatusAborted {
+					assert.ErrorIs(t, rr.Err, ErrPositionOutOfBounds)
+				}
+			}
+		})
+	}
+}
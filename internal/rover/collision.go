@@ -0,0 +1,144 @@
+package rover
+
+import (
+	"errors"
+	"fmt"
+)
+
+// CollisionAction tells MissionControl how to handle a move blocked by another rover or an obstacle
+type CollisionAction int
+
+const (
+	CollisionActionUnknown CollisionAction = iota
+	CollisionActionSkip                    // ignore the move and carry on with the remaining commands
+	CollisionActionHalt                    // ignore the move and stop processing the rover's remaining commands
+	CollisionActionAbort                   // fail the whole mission
+	CollisionActionPush                    // push the blocking rover one cell ahead, skipping the move if that cell is not free
+	CollisionActionSwap                    // swap cells with the blocking rover
+)
+
+// Names of the built-in collision policies, as accepted by NewCollisionPolicy
+const (
+	CollisionSkip  = "skip"
+	CollisionHalt  = "halt"
+	CollisionAbort = "abort"
+	CollisionPush  = "push"
+	CollisionSwap  = "swap"
+)
+
+// Collision describes a move that was blocked by another rover or an obstacle
+type Collision struct {
+	RoverID   int
+	Step      int // 1-based index of the command within the rover's command string
+	From      Position
+	To        Position
+	BlockerID int   // id of the blocking rover, only meaningful when Reason is ErrRoverCollision
+	Reason    error // ErrRoverCollision or ErrObstacleCollision
+}
+
+// CollisionPolicy decides what happens when a rover's move is blocked
+type CollisionPolicy interface {
+	OnCollision(c Collision) CollisionAction
+}
+
+// SkipOnCollision ignores the blocked move and carries on (default)
+type SkipOnCollision struct{}
+
+// HaltOnCollision stops the rover where it is, its remaining commands are not processed
+type HaltOnCollision struct{}
+
+// AbortOnCollision fails the mission on the first blocked move
+type AbortOnCollision struct{}
+
+// PushOnCollision shoves a blocking rover one cell further along the direction of travel. Obstacles cannot be pushed
+type PushOnCollision struct{}
+
+// SwapOnCollision exchanges cells with a blocking rover. Obstacles cannot be swapped with
+type SwapOnCollision struct{}
+
+func (SkipOnCollision) OnCollision(Collision) CollisionAction  { return CollisionActionSkip }
+func (HaltOnCollision) OnCollision(Collision) CollisionAction  { return CollisionActionHalt }
+func (AbortOnCollision) OnCollision(Collision) CollisionAction { return CollisionActionAbort }
+func (PushOnCollision) OnCollision(Collision) CollisionAction  { return CollisionActionPush }
+func (SwapOnCollision) OnCollision(Collision) CollisionAction  { return CollisionActionSwap }
+
+// NewCollisionPolicy returns the built-in CollisionPolicy registered under the given name or an error if there is none
+func NewCollisionPolicy(name string) (CollisionPolicy, error) {
+	switch name {
+	case CollisionSkip:
+		return SkipOnCollision{}, nil
+	case CollisionHalt:
+		return HaltOnCollision{}, nil
+	case CollisionAbort:
+		return AbortOnCollision{}, nil
+	case CollisionPush:
+		return PushOnCollision{}, nil
+	case CollisionSwap:
+		return SwapOnCollision{}, nil
+	}
+	return nil, fmt.Errorf("%w: given %q", ErrCollisionPolicyUnknown, name)
+}
+
+// WithCollisionPolicy sets the policy MissionControl applies when a rover's move is blocked
+func WithCollisionPolicy(cp CollisionPolicy) Option {
+	return func(mc *MissionControl) {
+		mc.collision = cp
+	}
+}
+
+// collisionPolicy returns the configured CollisionPolicy, defaulting to SkipOnCollision
+func (mc *MissionControl) collisionPolicy() CollisionPolicy {
+	if mc.collision == nil {
+		return SkipOnCollision{}
+	}
+	return mc.collision
+}
+
+// push moves the rover blocking c one cell further along the direction of travel, returning false if it cannot be pushed.
+// A push off the plateau goes through the boundary policy as a move would, it only succeeds when the policy moves the rover back onto the plateau
+func (mc *MissionControl) push(c Collision) bool {
+	blocker, ok := mc.rovers[c.BlockerID]
+	if !ok || !errors.Is(c.Reason, ErrRoverCollision) {
+		return false
+	}
+
+	pushTo := *blocker.position
+	pushTo.coordinates = pushTo.coordinates.ahead(c.From.direction)
+
+	if validateBoundaries(&pushTo, mc.plateau) != nil {
+		action, target := mc.boundaryPolicy().OffPlateau(mc.plateau, *blocker.position, pushTo)
+		if action != BoundaryActionMove {
+			return false
+		}
+		pushTo = target
+	}
+
+	if err := mc.validate(&pushTo); err != nil {
+		return false
+	}
+
+	from := *blocker.position
+	mc.relocate(blocker, pushTo)
+	mc.emit(RoverMoved{RoverID: blocker.id, RoverName: blocker.name, Step: c.Step, From: from, To: pushTo, Outcome: OutcomePushed, MovedBy: c.RoverID})
+	return true
+}
+
+// swap exchanges the cells of the rover that collided and the rover blocking it, returning false if there is no rover to swap with
+func (mc *MissionControl) swap(r *Rover, c Collision) bool {
+	blocker, ok := mc.rovers[c.BlockerID]
+	if !ok || !errors.Is(c.Reason, ErrRoverCollision) {
+		return false
+	}
+
+	blockerFrom := *blocker.position
+	blockerTo := blockerFrom
+	blockerTo.coordinates = r.position.coordinates
+
+	r.position.set(c.To)
+	blocker.position.set(blockerTo)
+
+	mc.occupiedSquares[r.position.coordinates] = r.id
+	mc.occupiedSquares[blocker.position.coordinates] = blocker.id
+	mc.emit(RoverMoved{RoverID: blocker.id, RoverName: blocker.name, Step: c.Step, From: blockerFrom, To: blockerTo, Outcome: OutcomeSwapped, MovedBy: c.RoverID})
+	return true
+}
@@ -0,0 +1,193 @@
+package rover
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCollisionPolicy(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		name       string
+		wantPolicy CollisionPolicy
+		wantErr    error
+	}{
+		"ok - skip":            {name: CollisionSkip, wantPolicy: SkipOnCollision{}},
+		"ok - halt":            {name: CollisionHalt, wantPolicy: HaltOnCollision{}},
+		"ok - abort":           {name: CollisionAbort, wantPolicy: AbortOnCollision{}},
+		"ok - push":            {name: CollisionPush, wantPolicy: PushOnCollision{}},
+		"ok - swap":            {name: CollisionSwap, wantPolicy: SwapOnCollision{}},
+		"err - unknown policy": {name: "bounce", wantErr: ErrCollisionPolicyUnknown},
+		"err - empty policy":   {name: "", wantErr: ErrCollisionPolicyUnknown},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			policy, err := NewCollisionPolicy(tc.name)
+
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantPolicy, policy)
+		})
+	}
+}
+
+// recordingPolicy returns a fixed action and keeps every collision it is asked about
+type recordingPolicy struct {
+	action     CollisionAction
+	collisions []Collision
+}
+
+func (p *recordingPolicy) OnCollision(c Collision) CollisionAction {
+	p.collisions = append(p.collisions, c)
+	return p.action
+}
+
+func TestMissionControlSimulate_CollisionPolicies(t *testing.T) {
+	t.Parallel()
+	testPlateau := createTestPlateau(t, 5, 5)
+
+	// rover 1 sits at (2,2), rover 2 drives east into it from (0,2) and then turns north
+	blockedEast := func(commands string) []RoverInstruction {
+		return []RoverInstruction{
+			*createTestSingleRoverInstruction(t, testPlateau, 2, 2, N, ""),
+			*createTestSingleRoverInstruction(t, testPlateau, 0, 2, E, commands),
+		}
+	}
+
+	testCases := map[string]struct {
+		policy     CollisionPolicy
+		boundary   BoundaryPolicy
+		obstacles  []Obstacle
+		input      []RoverInstruction
+		wantOutput []string
+		wantErr    error
+	}{
+		"skip - rover carries on after the blocked move": {
+			policy:     SkipOnCollision{},
+			input:      blockedEast("MMLM"),
+			wantOutput: []string{"2 2 N", "1 3 N"},
+		},
+		"halt - rover stops at the blocked move": {
+			policy:     HaltOnCollision{},
+			input:      blockedEast("MMLM"),
+			wantOutput: []string{"2 2 N", "1 2 E HALTED"},
+		},
+		"abort - mission fails": {
+			policy:  AbortOnCollision{},
+			input:   blockedEast("MMLM"),
+			wantErr: ErrRoverCollision,
+		},
+		"abort - obstacle fails the mission too": {
+			policy:    AbortOnCollision{},
+			obstacles: []Obstacle{NewObstacle(Rock, NewCoordinates(1, 2))},
+			input:     blockedEast("M"),
+			wantErr:   ErrObstacleCollision,
+		},
+		"push - blocking rover is shoved ahead": {
+			policy:     PushOnCollision{},
+			input:      blockedEast("MMLM"),
+			wantOutput: []string{"3 2 N", "2 3 N"},
+		},
+		"push - falls back to skip when the blocking rover cannot move": {
+			policy:     PushOnCollision{},
+			obstacles:  []Obstacle{NewObstacle(Crater, NewCoordinates(3, 2))},
+			input:      blockedEast("MMLM"),
+			wantOutput: []string{"2 2 N", "1 3 N"},
+		},
+		"push - obstacles cannot be pushed": {
+			policy:     PushOnCollision{},
+			obstacles:  []Obstacle{NewObstacle(Rock, NewCoordinates(1, 2))},
+			input:      blockedEast("MLM"),
+			wantOutput: []string{"2 2 N", "0 3 N"},
+		},
+		"push - pusher crossing the wrap edge shoves the blocking rover one cell along": {
+			policy:   PushOnCollision{},
+			boundary: WrapAround{},
+			input: []RoverInstruction{
+				*createTestSingleRoverInstruction(t, testPlateau, 0, 2, N, ""),
+				*createTestSingleRoverInstruction(t, testPlateau, 5, 2, E, "M"),
+			},
+			wantOutput: []string{"1 2 N", "0 2 E"},
+		},
+		"push - blocking rover is shoved across the wrap edge": {
+			policy:   PushOnCollision{},
+			boundary: WrapAround{},
+			input: []RoverInstruction{
+				*createTestSingleRoverInstruction(t, testPlateau, 5, 2, N, ""),
+				*createTestSingleRoverInstruction(t, testPlateau, 4, 2, E, "M"),
+			},
+			wantOutput: []string{"0 2 N", "5 2 E"},
+		},
+		"push - blocking rover cannot be shoved off the edge": {
+			policy: PushOnCollision{},
+			input: []RoverInstruction{
+				*createTestSingleRoverInstruction(t, testPlateau, 5, 2, N, ""),
+				*createTestSingleRoverInstruction(t, testPlateau, 4, 2, E, "M"),
+			},
+			wantOutput: []string{"5 2 N", "4 2 E"},
+		},
+		"swap - rovers exchange cells": {
+			policy:     SwapOnCollision{},
+			input:      blockedEast("MMLM"),
+			wantOutput: []string{"1 2 N", "2 3 N"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			plateau := createTestPlateau(t, 5, 5)
+			for _, o := range tc.obstacles {
+				require.NoError(t, plateau.AddObstacle(o))
+			}
+
+			mc, err := NewMissionControl(plateau, WithCollisionPolicy(tc.policy), WithBoundaryPolicy(tc.boundary))
+			require.NoError(t, err)
+
+			output, err := mc.Execute(context.Background(), &MissionControlInput{Instructions: tc.input})
+
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				assert.ErrorContains(t, err, "rover 2 blocked at step")
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantOutput, output)
+		})
+	}
+}
+
+func TestMissionControl_CustomCollisionPolicy(t *testing.T) {
+	t.Parallel()
+	plateau := createTestPlateau(t, 5, 5)
+	policy := &recordingPolicy{action: CollisionActionSkip}
+
+	mc, err := NewMissionControl(plateau, WithCollisionPolicy(policy))
+	require.NoError(t, err)
+
+	_, err = mc.Execute(context.Background(), &MissionControlInput{
+		Instructions: []RoverInstruction{
+			*createTestSingleRoverInstruction(t, plateau, 2, 2, N, ""),
+			*createTestSingleRoverInstruction(t, plateau, 0, 2, E, "MM"),
+		},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, policy.collisions, 1)
+	assert.Equal(t, Collision{
+		RoverID:   2,
+		Step:      2,
+		From:      Position{coordinates: Coordinates{x: 1, y: 2}, direction: E},
+		To:        Position{coordinates: Coordinates{x: 2, y: 2}, direction: E},
+		BlockerID: 1,
+		Reason:    ErrRoverCollision,
+	}, policy.collisions[0])
+}
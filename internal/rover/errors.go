@@ -3,12 +3,23 @@ package rover
 import "errors"
 
 var (
-	ErrPositionOutOfBounds = errors.New("position must be more than 0 and within boundaries")
-	ErrDirectionUnknown    = errors.New("direction must be one of N, E, S, W")
-	ErrRoverPositionIsNil  = errors.New("rover must not be nil")
-	ErrRoverCollision      = errors.New("path is blocked by another rover")
-	ErrRoverInstructions   = errors.New("rover error executing instruction")
-	ErrRoverCreating       = errors.New("rover could not be created")
-	ErrPlateauTooSmall     = errors.New("plateau must be at least 2 * 2")
-	ErrPlateauIsNil        = errors.New("plateau must not be nil")
+	ErrPositionOutOfBounds    = errors.New("position must be more than 0 and within boundaries")
+	ErrDirectionUnknown       = errors.New("direction must be one of N, E, S, W")
+	ErrRoverPositionIsNil     = errors.New("rover must not be nil")
+	ErrRoverCollision         = errors.New("path is blocked by another rover")
+	ErrObstacleCollision      = errors.New("path is blocked by an obstacle")
+	ErrObstacleOutOfBounds    = errors.New("obstacle must be within plateau boundaries")
+	ErrObstacleKindUnknown    = errors.New("obstacle kind must be one of rock, crater, nogo, zone")
+	ErrRoverInstructions      = errors.New("rover error executing instruction")
+	ErrRoverCreating          = errors.New("rover could not be created")
+	ErrPlateauTooSmall        = errors.New("plateau must be at least 2 * 2")
+	ErrPlateauIsNil           = errors.New("plateau must not be nil")
+	ErrScentProtected         = errors.New("move off the plateau prevented by the scent of a lost rover")
+	ErrBoundaryPolicyUnknown  = errors.New("boundary policy must be one of stop, wrap, lost, strict")
+	ErrCollisionPolicyUnknown = errors.New("collision policy must be one of skip, halt, abort, push, swap")
+	ErrMissionCancelled       = errors.New("mission cancelled")
+	ErrRoverNotFound          = errors.New("no rover deployed with that name or number")
+	ErrRoverNameTaken         = errors.New("a rover with that name or number is already deployed")
+	ErrRoverNotOperational    = errors.New("rover can no longer take commands")
+	ErrRoverLimit             = errors.New("too many rovers deployed")
 )
@@ -0,0 +1,131 @@
+package rover
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Event is implemented by every event MissionControl emits. Use a type switch to tell them apart
+type Event interface {
+	isEvent()
+}
+
+// RoverPlaced is emitted when a rover is deployed on the plateau
+type RoverPlaced struct {
+	RoverID   int
+	RoverName string // empty for rovers without a name
+	Position  Position
+}
+
+// RoverMoved is emitted when a rover changes cell. Rovers pushed or swapped by another rover also emit it, with the step of the command that caused it
+type RoverMoved struct {
+	RoverID   int
+	RoverName string
+	Step      int
+	From      Position
+	To        Position
+	Outcome   Outcome // OutcomeMoved, OutcomePushed or OutcomeSwapped
+	MovedBy   int     // id of the rover that pushed or swapped this one, 0 when the rover moved on its own command
+}
+
+// RoverTurned is emitted when a rover rotates on itself
+type RoverTurned struct {
+	RoverID   int
+	RoverName string
+	Step      int
+	Command   Command
+	From      Position
+	To        Position
+}
+
+// MoveRejected is emitted when a move command is ignored
+type MoveRejected struct {
+	RoverID   int
+	RoverName string
+	Step      int
+	From      Position
+	Target    Position
+	Reason    error
+	Outcome   Outcome // one of the OutcomeBlockedBy outcomes
+}
+
+// RoverFinished is emitted once a rover stops processing commands, whatever the reason
+type RoverFinished struct {
+	RoverID   int
+	RoverName string
+	Position  Position
+	Status    RoverStatus
+	Err       error
+}
+
+func (RoverPlaced) isEvent()   {}
+func (RoverMoved) isEvent()    {}
+func (RoverTurned) isEvent()   {}
+func (MoveRejected) isEvent()  {}
+func (RoverFinished) isEvent() {}
+
+// EventSink receives the events emitted by MissionControl as a mission runs. Calls are made synchronously from the goroutine running the mission
+type EventSink interface {
+	HandleEvent(e Event)
+}
+
+// NopSink discards every event (default)
+type NopSink struct{}
+
+func (NopSink) HandleEvent(Event) {}
+
+// SlogSink logs events to a slog.Logger. Rejected moves and rovers that did not finish operational are logged as warnings, everything else at debug level
+type SlogSink struct {
+	logger *slog.Logger
+}
+
+// NewSlogSink returns a SlogSink writing to the given logger
+func NewSlogSink(logger *slog.Logger) *SlogSink {
+	return &SlogSink{
+		logger: logger,
+	}
+}
+
+func (s *SlogSink) HandleEvent(e Event) {
+	ctx := context.Background()
+
+	switch ev := e.(type) {
+	case RoverPlaced:
+		s.logger.DebugContext(ctx, "rover placed", "rover", roverLabel(ev.RoverID, ev.RoverName), "position", ev.Position.String())
+
+	case RoverMoved:
+		s.logger.DebugContext(ctx, "rover moved", "rover", roverLabel(ev.RoverID, ev.RoverName), "step", ev.Step, "from", ev.From.String(), "to", ev.To.String(), "outcome", ev.Outcome.String())
+
+	case RoverTurned:
+		s.logger.DebugContext(ctx, "rover turned", "rover", roverLabel(ev.RoverID, ev.RoverName), "step", ev.Step, "command", ev.Command.String(), "heading", ev.To.Direction().String())
+
+	case MoveRejected:
+		s.logger.WarnContext(ctx, "rover ignored move", "rover", roverLabel(ev.RoverID, ev.RoverName), "step", ev.Step, "target", ev.Target.String(), "reason", ev.Reason.Error())
+
+	case RoverFinished:
+		level := slog.LevelDebug
+		if ev.Status != StatusOperational {
+			level = slog.LevelWarn
+		}
+
+		attrs := []any{"rover", roverLabel(ev.RoverID, ev.RoverName), "position", ev.Position.String(), "status", ev.Status.String()}
+		if ev.Err != nil {
+			attrs = append(attrs, "error", ev.Err.Error())
+		}
+		s.logger.Log(ctx, level, "rover finished", attrs...)
+	}
+}
+
+// WithEventSink adds a sink receiving the events emitted as the mission runs. When given several times, every sink receives every event in the order the sinks were added
+func WithEventSink(sink EventSink) Option {
+	return func(mc *MissionControl) {
+		mc.sinks = append(mc.sinks, sink)
+	}
+}
+
+// emit sends an event to the configured sinks, if any
+func (mc *MissionControl) emit(e Event) {
+	for _, sink := range mc.sinks {
+		sink.HandleEvent(e)
+	}
+}
@@ -0,0 +1,163 @@
+package rover
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSink keeps every event it receives
+type recordingSink struct {
+	events []Event
+}
+
+func (s *recordingSink) HandleEvent(e Event) {
+	s.events = append(s.events, e)
+}
+
+func TestMissionControl_Events(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		opts       []Option
+		input      func(p *Plateau) []RoverInstruction
+		wantEvents []Event
+	}{
+		"ok - placed, turned, moved, rejected and finished": {
+			input: func(p *Plateau) []RoverInstruction {
+				return []RoverInstruction{*createTestSingleRoverInstruction(t, p, 5, 4, N, "RMLM")}
+			},
+			wantEvents: []Event{
+				RoverPlaced{RoverID: 1, Position: pos(5, 4, N)},
+				RoverTurned{RoverID: 1, Step: 1, Command: CmdRight, From: pos(5, 4, N), To: pos(5, 4, E)},
+				MoveRejected{RoverID: 1, Step: 2, From: pos(5, 4, E), Target: pos(6, 4, E), Reason: ErrPositionOutOfBounds, Outcome: OutcomeBlockedByBoundary},
+				RoverTurned{RoverID: 1, Step: 3, Command: CmdLeft, From: pos(5, 4, E), To: pos(5, 4, N)},
+				RoverMoved{RoverID: 1, Step: 4, From: pos(5, 4, N), To: pos(5, 5, N), Outcome: OutcomeMoved},
+				RoverFinished{RoverID: 1, Position: pos(5, 5, N), Status: StatusOperational},
+			},
+		},
+		"ok - pushed rover emits its own move": {
+			opts: []Option{WithCollisionPolicy(PushOnCollision{})},
+			input: func(p *Plateau) []RoverInstruction {
+				return []RoverInstruction{
+					*createTestSingleRoverInstruction(t, p, 1, 0, N, ""),
+					*createTestSingleRoverInstruction(t, p, 0, 0, E, "M"),
+				}
+			},
+			wantEvents: []Event{
+				RoverPlaced{RoverID: 1, Position: pos(1, 0, N)},
+				RoverFinished{RoverID: 1, Position: pos(1, 0, N), Status: StatusOperational},
+				RoverPlaced{RoverID: 2, Position: pos(0, 0, E)},
+				RoverMoved{RoverID: 1, Step: 1, From: pos(1, 0, N), To: pos(2, 0, N), Outcome: OutcomePushed, MovedBy: 2},
+				RoverMoved{RoverID: 2, Step: 1, From: pos(0, 0, E), To: pos(1, 0, E), Outcome: OutcomePushed},
+				RoverFinished{RoverID: 2, Position: pos(1, 0, E), Status: StatusOperational},
+			},
+		},
+		"ok - lost rover finishes with its status": {
+			opts: []Option{WithBoundaryPolicy(LoseOffEdge{})},
+			input: func(p *Plateau) []RoverInstruction {
+				return []RoverInstruction{*createTestSingleRoverInstruction(t, p, 0, 0, S, "MM")}
+			},
+			wantEvents: []Event{
+				RoverPlaced{RoverID: 1, Position: pos(0, 0, S)},
+				RoverFinished{RoverID: 1, Position: pos(0, 0, S), Status: StatusLost},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			plateau := createTestPlateau(t, 5, 5)
+			sink := &recordingSink{}
+
+			mc, err := NewMissionControl(plateau, append(tc.opts, WithEventSink(sink))...)
+			require.NoError(t, err)
+
+			_, err = mc.Simulate(context.Background(), &MissionControlInput{Instructions: tc.input(plateau)})
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.wantEvents, sink.events)
+		})
+	}
+}
+
+func TestMissionControl_MultipleSinks(t *testing.T) {
+	t.Parallel()
+
+	plateau := createTestPlateau(t, 5, 5)
+	first, second := &recordingSink{}, &recordingSink{}
+
+	mcf := NewMissionControlFactory(WithEventSink(first))
+	mc, err := mcf.Create(plateau, WithEventSink(second))
+	require.NoError(t, err)
+
+	_, err = mc.Simulate(context.Background(), &MissionControlInput{Instructions: []RoverInstruction{*createTestSingleRoverInstruction(t, plateau, 1, 2, N, "M")}})
+	require.NoError(t, err)
+
+	assert.Len(t, first.events, 3)
+	assert.Equal(t, first.events, second.events)
+}
+
+func TestSlogSink(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		level      slog.Level
+		event      Event
+		wantLogged string
+	}{
+		"ok - rejected move logged as a warning": {
+			level:      slog.LevelInfo,
+			event:      MoveRejected{RoverID: 1, Step: 2, Target: pos(0, -1, S), Reason: ErrPositionOutOfBounds},
+			wantLogged: `level=WARN msg="rover ignored move" rover=1 step=2 target="0 -1 S" reason="position must be more than 0 and within boundaries"`,
+		},
+		"ok - lost rover logged as a warning": {
+			level:      slog.LevelInfo,
+			event:      RoverFinished{RoverID: 3, Position: pos(5, 5, N), Status: StatusLost},
+			wantLogged: `level=WARN msg="rover finished" rover=3 position="5 5 N" status=lost`,
+		},
+		"ok - named rover logged by name": {
+			level:      slog.LevelInfo,
+			event:      MoveRejected{RoverID: 1, RoverName: "alpha", Step: 2, Target: pos(0, -1, S), Reason: ErrPositionOutOfBounds},
+			wantLogged: `level=WARN msg="rover ignored move" rover=alpha step=2 target="0 -1 S" reason="position must be more than 0 and within boundaries"`,
+		},
+		"ok - moves hidden above debug level": {
+			level:      slog.LevelInfo,
+			event:      RoverMoved{RoverID: 1, Step: 1, From: pos(0, 0, N), To: pos(0, 1, N), Outcome: OutcomeMoved},
+			wantLogged: "",
+		},
+		"ok - moves logged at debug level": {
+			level:      slog.LevelDebug,
+			event:      RoverMoved{RoverID: 1, Step: 1, From: pos(0, 0, N), To: pos(0, 1, N), Outcome: OutcomeMoved},
+			wantLogged: `level=DEBUG msg="rover moved" rover=1 step=1 from="0 0 N" to="0 1 N" outcome=moved`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			handler := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+				Level: tc.level,
+				// drop the timestamp so the output is deterministic
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if a.Key == slog.TimeKey {
+						return slog.Attr{}
+					}
+					return a
+				},
+			})
+
+			NewSlogSink(slog.New(handler)).HandleEvent(tc.event)
+
+			if tc.wantLogged == "" {
+				assert.Empty(t, buf.String())
+				return
+			}
+			assert.Equal(t, tc.wantLogged+"\n", buf.String())
+		})
+	}
+}
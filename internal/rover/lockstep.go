@@ -0,0 +1,344 @@
+package rover
+
+import (
+	"context"
+	"fmt"
+)
+
+// Tick holds the state of every rover after one lockstep tick. Tick 0 is the deployment of the rovers
+type Tick struct {
+	Number int
+	Rovers []RoverState
+}
+
+// RoverState is a snapshot of a rover at a given tick
+type RoverState struct {
+	ID       int
+	Name     string
+	Position Position
+	Status   RoverStatus
+}
+
+// lockstepRover tracks the progress of a single rover through its commands
+type lockstepRover struct {
+	rover    *Rover
+	commands []rune
+	next     int // index of the next command to process
+	done     bool
+	result   RoverResult
+}
+
+// moveIntent is a move command waiting to be resolved against every other move of the same tick
+type moveIntent struct {
+	lr      *lockstepRover
+	step    int
+	from    Position
+	target  Position
+	blocked bool
+	blocker int // id of the rover that blocked the move
+}
+
+// WithLockstep makes MissionControl run every rover one command per tick instead of one rover after another. Rovers moving in the same tick are resolved together:
+//   - two or more rovers moving into the same cell: the first deployed rover wins, the others are blocked
+//   - two rovers moving into each other's cell: both are blocked as they cannot pass through each other
+//   - a rover may move into a cell vacated by another rover in the same tick
+//
+// Blocked moves go through the collision policy, push and swap are not supported in lockstep mode and skip the move instead
+func WithLockstep() Option {
+	return func(mc *MissionControl) {
+		mc.lockstep = true
+	}
+}
+
+// simulateLockstep deploys every rover and then runs them in lockstep, one command per rover per tick, recording the state of the fleet after each tick
+func (mc *MissionControl) simulateLockstep(ctx context.Context, input *MissionControlInput) (*MissionResult, error) {
+	fleet := make([]*lockstepRover, 0, len(input.Instructions))
+
+	// every rover is deployed before the first tick
+	for i, instruction := range input.Instructions {
+		roverID := i + 1
+
+		currentRover, err := NewRover(roverID, instruction.InitialPosition)
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: %v", ErrRoverCreating, roverLabel(roverID, instruction.Name), err)
+		}
+		currentRover.name = instruction.Name
+
+		if err := mc.validate(currentRover.position); err != nil {
+			err = fmt.Errorf("new rover with id %s cannot be placed at (%s): %w", currentRover.label(), currentRover.position.String(), err)
+			return nil, fmt.Errorf("%w %s: %w", ErrRoverInstructions, currentRover.label(), err)
+		}
+
+		mc.occupiedSquares[currentRover.position.coordinates] = currentRover.id
+		mc.track(currentRover)
+		mc.emit(RoverPlaced{RoverID: currentRover.id, RoverName: currentRover.name, Position: *currentRover.position})
+
+		fleet = append(fleet, &lockstepRover{
+			rover:    currentRover,
+			commands: []rune(instruction.Commands),
+			result:   RoverResult{ID: currentRover.id, Name: currentRover.name, Start: *currentRover.position},
+		})
+	}
+
+	result := &MissionResult{
+		Plateau: mc.plateau,
+		Rovers:  make([]RoverResult, 0, len(fleet)),
+		Ticks:   []Tick{snapshot(0, fleet)},
+	}
+
+	for tick := 1; ; tick++ {
+		if err := checkCancelled(ctx, tick); err != nil {
+			return nil, err
+		}
+
+		active := false
+		var intents []*moveIntent
+
+		// turns are applied straight away, moves are collected and resolved together
+		for _, lr := range fleet {
+			if lr.done || lr.next >= len(lr.commands) {
+				continue
+			}
+			active = true
+
+			intent, err := mc.lockstepCommand(lr)
+			if err != nil {
+				return nil, fmt.Errorf("%w %s: %w", ErrRoverInstructions, lr.rover.label(), err)
+			}
+
+			if intent != nil {
+				intents = append(intents, intent)
+			}
+		}
+
+		if !active {
+			break
+		}
+
+		if err := mc.resolveMoves(intents); err != nil {
+			return nil, err
+		}
+
+		for _, lr := range fleet {
+			if !lr.done && lr.next >= len(lr.commands) {
+				mc.stopLockstep(lr)
+			}
+		}
+
+		result.Ticks = append(result.Ticks, snapshot(tick, fleet))
+	}
+
+	// rovers without any command never entered a tick
+	for _, lr := range fleet {
+		if !lr.done {
+			mc.stopLockstep(lr)
+		}
+		result.Rovers = append(result.Rovers, lr.result)
+	}
+
+	return result, nil
+}
+
+// lockstepCommand processes the next command of the rover. Turns and moves that cannot succeed whatever the other rovers do are settled here, other moves are returned to be resolved with the rest of the tick
+func (mc *MissionControl) lockstepCommand(lr *lockstepRover) (*moveIntent, error) {
+	r := lr.rover
+	c := Command(lr.commands[lr.next])
+	lr.next++
+	step := lr.next
+	before := *r.position
+
+	switch c {
+	case CmdLeft:
+		r.turnLeft()
+	case CmdRight:
+		r.turnRight()
+	case CmdMove:
+		return mc.lockstepMove(lr, step)
+	default:
+		return nil, nil
+	}
+
+	mc.record(&lr.result, step, c, before, *r.position, OutcomeTurned)
+	mc.emit(RoverTurned{RoverID: r.id, RoverName: r.name, Step: step, Command: c, From: before, To: *r.position})
+	return nil, nil
+}
+
+// lockstepMove applies the boundary policy and static obstacles to a move, returning the move intent if the target cell may be reachable
+func (mc *MissionControl) lockstepMove(lr *lockstepRover, step int) (*moveIntent, error) {
+	r := lr.rover
+	before := *r.position
+	currentPosKey := r.position.coordinates
+	nextPos := r.move()
+
+	if validateBoundaries(&nextPos, mc.plateau) != nil {
+		action, target := mc.boundaryPolicy().OffPlateau(mc.plateau, *r.position, nextPos)
+
+		switch action {
+		case BoundaryActionMove:
+			nextPos = target
+
+		case BoundaryActionLose:
+			if _, scented := mc.scents[currentPosKey]; scented {
+				mc.ignore(r, step, nextPos, ErrScentProtected, &lr.result)
+				mc.record(&lr.result, step, CmdMove, before, before, OutcomeBlockedByBoundary)
+				return nil, nil
+			}
+
+			// lost rovers leave the plateau straight away, freeing their cell for this tick
+			mc.leaveScent(currentPosKey)
+			delete(mc.occupiedSquares, currentPosKey)
+			delete(mc.rovers, r.id)
+
+			lr.result.Status = StatusLost
+			mc.record(&lr.result, step, CmdMove, before, before, OutcomeLost)
+			mc.stopLockstep(lr)
+			return nil, nil
+
+		case BoundaryActionAbort:
+			lr.result.Status = StatusAborted
+			lr.result.Err = fmt.Errorf("rover %s aborted at step %d moving to (%s): %w", r.label(), step, nextPos.String(), ErrPositionOutOfBounds)
+			mc.record(&lr.result, step, CmdMove, before, before, OutcomeBlockedByBoundary)
+			mc.stopLockstep(lr)
+			return nil, nil
+
+		default:
+			mc.ignore(r, step, nextPos, ErrPositionOutOfBounds, &lr.result)
+			mc.record(&lr.result, step, CmdMove, before, before, OutcomeBlockedByBoundary)
+			return nil, nil
+		}
+	}
+
+	if mc.plateau.isObstructed(nextPos.coordinates) {
+		return nil, mc.lockstepBlocked(&moveIntent{lr: lr, step: step, from: before, target: nextPos}, ErrObstacleCollision)
+	}
+
+	return &moveIntent{lr: lr, step: step, from: before, target: nextPos}, nil
+}
+
+// resolveMoves settles every move of a tick at once and applies the successful ones
+func (mc *MissionControl) resolveMoves(intents []*moveIntent) error {
+	movers := make(map[int]*moveIntent, len(intents))
+	winners := make(map[Coordinates]*moveIntent, len(intents))
+
+	for _, intent := range intents {
+		movers[intent.lr.rover.id] = intent
+
+		// contested cells go to the first deployed rover
+		if w, ok := winners[intent.target.coordinates]; !ok || intent.lr.rover.id < w.lr.rover.id {
+			winners[intent.target.coordinates] = intent
+		}
+	}
+
+	for _, intent := range intents {
+		if w := winners[intent.target.coordinates]; w != intent {
+			intent.blocked = true
+			intent.blocker = w.lr.rover.id
+		}
+	}
+
+	// a move into an occupied cell only succeeds if the occupant leaves it in the same tick, repeat until no more moves get blocked
+	for changed := true; changed; {
+		changed = false
+
+		for _, intent := range intents {
+			if intent.blocked {
+				continue
+			}
+
+			occupant, occupied := mc.occupiedSquares[intent.target.coordinates]
+			if !occupied {
+				continue
+			}
+
+			other, moving := movers[occupant]
+			headOn := moving && other.target.coordinates == intent.from.coordinates
+
+			if !moving || other.blocked || headOn {
+				intent.blocked = true
+				intent.blocker = occupant
+				changed = true
+			}
+		}
+	}
+
+	// vacate every cell first so rovers can follow each other
+	for _, intent := range intents {
+		if !intent.blocked {
+			delete(mc.occupiedSquares, intent.from.coordinates)
+		}
+	}
+
+	for _, intent := range intents {
+		r := intent.lr.rover
+
+		if intent.blocked {
+			if err := mc.lockstepBlocked(intent, ErrRoverCollision); err != nil {
+				return fmt.Errorf("%w %s: %w", ErrRoverInstructions, r.label(), err)
+			}
+			continue
+		}
+
+		r.position.set(intent.target)
+		mc.occupiedSquares[intent.target.coordinates] = r.id
+
+		mc.record(&intent.lr.result, intent.step, CmdMove, intent.from, *r.position, OutcomeMoved)
+		mc.emit(RoverMoved{RoverID: r.id, RoverName: r.name, Step: intent.step, From: intent.from, To: *r.position, Outcome: OutcomeMoved})
+	}
+
+	return nil
+}
+
+// lockstepBlocked applies the collision policy to a blocked move. Only skip, halt and abort are supported in lockstep mode
+func (mc *MissionControl) lockstepBlocked(intent *moveIntent, reason error) error {
+	r := intent.lr.rover
+	c := Collision{
+		RoverID:   r.id,
+		Step:      intent.step,
+		From:      intent.from,
+		To:        intent.target,
+		BlockerID: intent.blocker,
+		Reason:    reason,
+	}
+
+	action := mc.collisionPolicy().OnCollision(c)
+	if action == CollisionActionAbort {
+		mc.emit(RoverFinished{RoverID: r.id, RoverName: r.name, Position: *r.position, Status: StatusAborted, Err: reason})
+		return fmt.Errorf("rover %s blocked at step %d moving to (%s): %w", r.label(), intent.step, intent.target.String(), reason)
+	}
+
+	mc.ignore(r, intent.step, intent.target, reason, &intent.lr.result)
+	mc.record(&intent.lr.result, intent.step, CmdMove, intent.from, intent.from, blockedOutcome(reason))
+
+	if action == CollisionActionHalt {
+		intent.lr.result.Status = StatusHalted
+		mc.stopLockstep(intent.lr)
+	}
+
+	return nil
+}
+
+// stopLockstep finalises a rover that will not process any more commands
+func (mc *MissionControl) stopLockstep(lr *lockstepRover) {
+	lr.done = true
+	lr.result.Position = *lr.rover.position
+	lr.result = mc.finish(lr.result)
+}
+
+// snapshot returns the state of the fleet at the given tick
+func snapshot(number int, fleet []*lockstepRover) Tick {
+	tick := Tick{
+		Number: number,
+		Rovers: make([]RoverState, 0, len(fleet)),
+	}
+
+	for _, lr := range fleet {
+		tick.Rovers = append(tick.Rovers, RoverState{
+			ID:       lr.rover.id,
+			Name:     lr.rover.name,
+			Position: *lr.rover.position,
+			Status:   lr.result.Status,
+		})
+	}
+
+	return tick
+}
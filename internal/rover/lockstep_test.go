@@ -0,0 +1,191 @@
+package rover
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMissionControlSimulate_Lockstep(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		opts    []Option
+		input   func(p *Plateau) []RoverInstruction
+		want    []string
+		wantErr error
+	}{
+		"ok - rover follows another into the cell it vacates": {
+			input: func(p *Plateau) []RoverInstruction {
+				return []RoverInstruction{
+					*createTestSingleRoverInstruction(t, p, 0, 0, E, "M"),
+					*createTestSingleRoverInstruction(t, p, 1, 0, E, "M"),
+				}
+			},
+			want: []string{"1 0 E", "2 0 E"},
+		},
+		"ok - contested cell goes to the first deployed rover": {
+			input: func(p *Plateau) []RoverInstruction {
+				return []RoverInstruction{
+					*createTestSingleRoverInstruction(t, p, 0, 1, E, "M"),
+					*createTestSingleRoverInstruction(t, p, 2, 1, W, "M"),
+				}
+			},
+			want: []string{"1 1 E", "2 1 W"},
+		},
+		"ok - head-on swap blocks both rovers": {
+			input: func(p *Plateau) []RoverInstruction {
+				return []RoverInstruction{
+					*createTestSingleRoverInstruction(t, p, 0, 0, E, "M"),
+					*createTestSingleRoverInstruction(t, p, 1, 0, W, "M"),
+				}
+			},
+			want: []string{"0 0 E", "1 0 W"},
+		},
+		"ok - rovers rotate around a cycle": {
+			input: func(p *Plateau) []RoverInstruction {
+				return []RoverInstruction{
+					*createTestSingleRoverInstruction(t, p, 0, 0, N, "M"),
+					*createTestSingleRoverInstruction(t, p, 0, 1, E, "M"),
+					*createTestSingleRoverInstruction(t, p, 1, 1, S, "M"),
+					*createTestSingleRoverInstruction(t, p, 1, 0, W, "M"),
+				}
+			},
+			want: []string{"0 1 N", "1 1 E", "1 0 S", "0 0 W"},
+		},
+		"ok - blocked rover blocks the rover behind it": {
+			input: func(p *Plateau) []RoverInstruction {
+				return []RoverInstruction{
+					*createTestSingleRoverInstruction(t, p, 0, 0, E, "M"),
+					*createTestSingleRoverInstruction(t, p, 1, 0, E, "M"),
+					*createTestSingleRoverInstruction(t, p, 2, 0, N, ""),
+				}
+			},
+			want: []string{"0 0 E", "1 0 E", "2 0 N"},
+		},
+		"ok - turns apply before moves are resolved": {
+			input: func(p *Plateau) []RoverInstruction {
+				return []RoverInstruction{
+					*createTestSingleRoverInstruction(t, p, 0, 0, N, "RM"),
+					*createTestSingleRoverInstruction(t, p, 1, 0, N, "MM"),
+				}
+			},
+			want: []string{"1 0 E", "1 2 N"},
+		},
+		"ok - lost rover frees its cell in the same tick": {
+			opts: []Option{WithBoundaryPolicy(LoseOffEdge{})},
+			input: func(p *Plateau) []RoverInstruction {
+				return []RoverInstruction{
+					*createTestSingleRoverInstruction(t, p, 0, 5, N, "M"),
+					*createTestSingleRoverInstruction(t, p, 0, 4, N, "M"),
+				}
+			},
+			want: []string{"0 5 N LOST", "0 5 N"},
+		},
+		"ok - halted rover stops processing commands": {
+			opts: []Option{WithCollisionPolicy(HaltOnCollision{})},
+			input: func(p *Plateau) []RoverInstruction {
+				return []RoverInstruction{
+					*createTestSingleRoverInstruction(t, p, 0, 1, E, "M"),
+					*createTestSingleRoverInstruction(t, p, 2, 1, W, "MLM"),
+				}
+			},
+			want: []string{"1 1 E", "2 1 W HALTED"},
+		},
+		"ok - push falls back to skip": {
+			opts: []Option{WithCollisionPolicy(PushOnCollision{})},
+			input: func(p *Plateau) []RoverInstruction {
+				return []RoverInstruction{
+					*createTestSingleRoverInstruction(t, p, 0, 0, E, "M"),
+					*createTestSingleRoverInstruction(t, p, 1, 0, N, ""),
+				}
+			},
+			want: []string{"0 0 E", "1 0 N"},
+		},
+		"err - abort on contested cell": {
+			opts: []Option{WithCollisionPolicy(AbortOnCollision{})},
+			input: func(p *Plateau) []RoverInstruction {
+				return []RoverInstruction{
+					*createTestSingleRoverInstruction(t, p, 0, 1, E, "M"),
+					*createTestSingleRoverInstruction(t, p, 2, 1, W, "M"),
+				}
+			},
+			wantErr: ErrRoverCollision,
+		},
+		"err - rovers deployed on the same cell": {
+			input: func(p *Plateau) []RoverInstruction {
+				return []RoverInstruction{
+					*createTestSingleRoverInstruction(t, p, 1, 1, N, ""),
+					*createTestSingleRoverInstruction(t, p, 1, 1, N, ""),
+				}
+			},
+			wantErr: ErrRoverInstructions,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			plateau := createTestPlateau(t, 5, 5)
+
+			mc, err := NewMissionControl(plateau, append([]Option{WithLockstep()}, tc.opts...)...)
+			require.NoError(t, err)
+
+			got, err := mc.Execute(context.Background(), &MissionControlInput{Instructions: tc.input(plateau)})
+
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestMissionControlSimulate_LockstepTicks(t *testing.T) {
+	t.Parallel()
+
+	plateau := createTestPlateau(t, 5, 5)
+
+	mc, err := NewMissionControl(plateau, WithLockstep(), WithTrace())
+	require.NoError(t, err)
+
+	result, err := mc.Simulate(context.Background(), &MissionControlInput{
+		Instructions: []RoverInstruction{
+			*createTestSingleRoverInstruction(t, plateau, 0, 0, N, "MM"),
+			*createTestSingleRoverInstruction(t, plateau, 3, 3, E, "L"),
+		},
+	})
+	require.NoError(t, err)
+
+	want := []Tick{
+		{Number: 0, Rovers: []RoverState{{ID: 1, Position: pos(0, 0, N)}, {ID: 2, Position: pos(3, 3, E)}}},
+		{Number: 1, Rovers: []RoverState{{ID: 1, Position: pos(0, 1, N)}, {ID: 2, Position: pos(3, 3, N)}}},
+		{Number: 2, Rovers: []RoverState{{ID: 1, Position: pos(0, 2, N)}, {ID: 2, Position: pos(3, 3, N)}}},
+	}
+	assert.Equal(t, want, result.Ticks)
+
+	// steps line up with ticks
+	assert.Equal(t, []TraceStep{
+		{Step: 1, Command: CmdMove, Before: pos(0, 0, N), After: pos(0, 1, N), Outcome: OutcomeMoved},
+		{Step: 2, Command: CmdMove, Before: pos(0, 1, N), After: pos(0, 2, N), Outcome: OutcomeMoved},
+	}, result.Rovers[0].Trace)
+}
+
+func TestMissionControlSimulate_SequentialHasNoTicks(t *testing.T) {
+	t.Parallel()
+
+	plateau := createTestPlateau(t, 5, 5)
+
+	mc, err := NewMissionControl(plateau)
+	require.NoError(t, err)
+
+	result, err := mc.Simulate(context.Background(), &MissionControlInput{
+		Instructions: []RoverInstruction{*createTestSingleRoverInstruction(t, plateau, 0, 0, N, "M")},
+	})
+	require.NoError(t, err)
+	assert.Nil(t, result.Ticks)
+}
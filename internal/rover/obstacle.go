@@ -0,0 +1,131 @@
+package rover
+
+import "fmt"
+
+type ObstacleKind int
+
+const (
+	UnknownObstacle ObstacleKind = iota
+	Rock                         // single cell
+	Crater                       // single cell
+	NoGo                         // single cell declared off-limits
+	Zone                         // rectangular exclusion zone
+)
+
+// Obstacle is a static, rectangular area of the plateau that rovers cannot be placed on or move into. Single cell obstacles have matching corners
+type Obstacle struct {
+	kind ObstacleKind
+	from Coordinates // bottom left corner
+	to   Coordinates // top right corner
+}
+
+// NewObstacle returns a single cell obstacle of the given kind at the given coordinates. It performs no validation
+func NewObstacle(kind ObstacleKind, c Coordinates) Obstacle {
+	return Obstacle{
+		kind: kind,
+		from: c,
+		to:   c,
+	}
+}
+
+// NewZone returns a rectangular exclusion zone spanning both corners (inclusive), in any order. It performs no validation
+func NewZone(a, b Coordinates) Obstacle {
+	return Obstacle{
+		kind: Zone,
+		from: Coordinates{x: min(a.x, b.x), y: min(a.y, b.y)},
+		to:   Coordinates{x: max(a.x, b.x), y: max(a.y, b.y)},
+	}
+}
+
+// Kind returns the kind of obstacle
+func (o Obstacle) Kind() ObstacleKind {
+	return o.kind
+}
+
+// Bounds returns the bottom left and top right corners covered by the obstacle
+func (o Obstacle) Bounds() (from, to Coordinates) {
+	return o.from, o.to
+}
+
+// contains reports whether the given coordinates fall within the obstacle
+func (o Obstacle) contains(c Coordinates) bool {
+	return c.x >= o.from.x && c.x <= o.to.x && c.y >= o.from.y && c.y <= o.to.y
+}
+
+// String implements the Stringer interface
+func (o Obstacle) String() string {
+	if o.kind == Zone {
+		return fmt.Sprintf("%s %d %d %d %d", o.kind, o.from.x, o.from.y, o.to.x, o.to.y)
+	}
+	return fmt.Sprintf("%s %d %d", o.kind, o.from.x, o.from.y)
+}
+
+// implement stringer interface so obstacle kinds print with the keyword used by the input formats
+func (k ObstacleKind) String() string {
+	switch k {
+	case Rock:
+		return "rock"
+	case Crater:
+		return "crater"
+	case NoGo:
+		return "nogo"
+	case Zone:
+		return "zone"
+	default:
+		return "?"
+	}
+}
+
+// X returns the x coordinate
+func (c Coordinates) X() int {
+	return c.x
+}
+
+// Y returns the y coordinate
+func (c Coordinates) Y() int {
+	return c.y
+}
+
+// AddObstacle places a static obstacle on the Plateau returning an error should the obstacle kind be unknown or any of its cells fall outside the plateau
+func (p *Plateau) AddObstacle(o Obstacle) error {
+	if o.kind <= UnknownObstacle || o.kind > Zone {
+		return ErrObstacleKindUnknown
+	}
+
+	for _, c := range []Coordinates{o.from, o.to} {
+		if c.x < 0 || c.x > p.maxX || c.y < 0 || c.y > p.maxY {
+			return fmt.Errorf("%w: %s", ErrObstacleOutOfBounds, o)
+		}
+	}
+
+	p.obstacles = append(p.obstacles, o)
+	if o.kind == Zone {
+		p.zones = append(p.zones, o)
+		return nil
+	}
+
+	if p.cells == nil {
+		p.cells = make(map[Coordinates]struct{})
+	}
+	p.cells[o.from] = struct{}{}
+	return nil
+}
+
+// Obstacles returns the obstacles placed on the Plateau
+func (p *Plateau) Obstacles() []Obstacle {
+	return p.obstacles
+}
+
+// isObstructed reports whether any obstacle covers the given coordinates
+func (p *Plateau) isObstructed(c Coordinates) bool {
+	if _, ok := p.cells[c]; ok {
+		return true
+	}
+
+	for _, o := range p.zones {
+		if o.contains(c) {
+			return true
+		}
+	}
+	return false
+}
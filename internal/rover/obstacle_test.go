@@ -0,0 +1,151 @@
+package rover
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewZone(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		a, b         Coordinates
+		wantObstacle Obstacle
+	}{
+		"ok - corners in order": {
+			a: NewCoordinates(1, 1), b: NewCoordinates(3, 2),
+			wantObstacle: Obstacle{kind: Zone, from: Coordinates{x: 1, y: 1}, to: Coordinates{x: 3, y: 2}},
+		},
+		"ok - corners reversed": {
+			a: NewCoordinates(3, 1), b: NewCoordinates(1, 2),
+			wantObstacle: Obstacle{kind: Zone, from: Coordinates{x: 1, y: 1}, to: Coordinates{x: 3, y: 2}},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.wantObstacle, NewZone(tc.a, tc.b))
+		})
+	}
+}
+
+func TestPlateauAddObstacle(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		obstacle Obstacle
+		wantErr  error
+	}{
+		"ok - rock":              {obstacle: NewObstacle(Rock, NewCoordinates(2, 2)), wantErr: nil},
+		"ok - zone":              {obstacle: NewZone(NewCoordinates(0, 0), NewCoordinates(5, 5)), wantErr: nil},
+		"err - unknown kind":     {obstacle: NewObstacle(UnknownObstacle, NewCoordinates(2, 2)), wantErr: ErrObstacleKindUnknown},
+		"err - out of bounds":    {obstacle: NewObstacle(Crater, NewCoordinates(6, 2)), wantErr: ErrObstacleOutOfBounds},
+		"err - zone exceeds map": {obstacle: NewZone(NewCoordinates(1, 1), NewCoordinates(1, 6)), wantErr: ErrObstacleOutOfBounds},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			plateau := &Plateau{maxX: 5, maxY: 5}
+
+			err := plateau.AddObstacle(tc.obstacle)
+
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				assert.Empty(t, plateau.Obstacles())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, []Obstacle{tc.obstacle}, plateau.Obstacles())
+		})
+	}
+}
+
+func TestPlateau_isObstructed(t *testing.T) {
+	t.Parallel()
+
+	plateau := &Plateau{maxX: 10, maxY: 10}
+	require.NoError(t, plateau.AddObstacle(NewObstacle(Rock, NewCoordinates(1, 1))))
+	require.NoError(t, plateau.AddObstacle(NewObstacle(Crater, NewCoordinates(9, 2))))
+	require.NoError(t, plateau.AddObstacle(NewZone(NewCoordinates(4, 4), NewCoordinates(6, 5))))
+
+	testCases := map[string]struct {
+		coordinates Coordinates
+		want        bool
+	}{
+		"ok - rock":         {coordinates: NewCoordinates(1, 1), want: true},
+		"ok - crater":       {coordinates: NewCoordinates(9, 2), want: true},
+		"ok - zone corner":  {coordinates: NewCoordinates(6, 5), want: true},
+		"ok - zone inside":  {coordinates: NewCoordinates(5, 4), want: true},
+		"ok - free cell":    {coordinates: NewCoordinates(1, 2), want: false},
+		"ok - next to zone": {coordinates: NewCoordinates(7, 5), want: false},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, plateau.isObstructed(tc.coordinates))
+		})
+	}
+}
+
+func TestRunRover_Obstacles(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		obstacles  []Obstacle
+		rover      *Rover
+		commands   string
+		wantString string
+		wantErr    error
+	}{
+		"ok - rock blocks move": {
+			obstacles: []Obstacle{NewObstacle(Rock, NewCoordinates(5, 7))},
+			rover: &Rover{
+				id:       1,
+				position: &Position{coordinates: Coordinates{x: 5, y: 5}, direction: N},
+			},
+			commands:   "MMM",
+			wantString: "5 6 N",
+		},
+		"ok - zone blocks move, rover drives around it": {
+			obstacles: []Obstacle{NewZone(NewCoordinates(4, 7), NewCoordinates(6, 8))},
+			rover: &Rover{
+				id:       1,
+				position: &Position{coordinates: Coordinates{x: 5, y: 6}, direction: N},
+			},
+			commands:   "MRMMLMMM",
+			wantString: "7 9 N",
+		},
+		"err - ErrObstacleCollision - placing new rover": {
+			obstacles: []Obstacle{NewObstacle(NoGo, NewCoordinates(5, 5))},
+			rover: &Rover{
+				id:       1,
+				position: &Position{coordinates: Coordinates{x: 5, y: 5}, direction: N},
+			},
+			commands: "M",
+			wantErr:  ErrObstacleCollision,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			plateau := &Plateau{maxX: 10, maxY: 10}
+			for _, o := range tc.obstacles {
+				require.NoError(t, plateau.AddObstacle(o))
+			}
+
+			mc, err := NewMissionControl(plateau)
+			require.NoError(t, err)
+
+			result, err := mc.RunRover(context.Background(), tc.rover, tc.commands)
+
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantString, result)
+		})
+	}
+}
@@ -1,8 +1,12 @@
 package rover
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"strconv"
+	"strings"
+	"sync"
 )
 
 type Direction int
@@ -33,15 +37,20 @@ type Position struct {
 
 type Rover struct {
 	id       int
+	name     string // optional identifier given in the mission input
 	position *Position
 }
 
 type Plateau struct {
-	maxX int
-	maxY int
+	maxX      int
+	maxY      int
+	obstacles []Obstacle               // every obstacle in the order it was added
+	cells     map[Coordinates]struct{} // cells covered by single cell obstacles, so they are found without a scan
+	zones     []Obstacle               // exclusion zones, the only obstacles scanned
 }
 
 type RoverInstruction struct {
+	Name            string // optional identifier, rovers without one are known by their deployment number
 	InitialPosition *Position
 	Commands        string
 }
@@ -50,23 +59,79 @@ type MissionControlInput struct {
 	Instructions []RoverInstruction
 }
 
+// IgnoredMove records a move command that was not applied and why it was rejected
+type IgnoredMove struct {
+	Step   int      // 1-based index of the command within the rover's command string
+	Target Position // position the rover attempted to move to
+	Reason error
+}
+
+type RoverStatus int
+
+const (
+	StatusOperational RoverStatus = iota // rover processed its commands and remains on the plateau
+	StatusLost                           // rover drove off the plateau
+	StatusAborted                        // rover stopped processing its commands because of an error
+	StatusHalted                         // rover stopped processing its commands after a collision
+)
+
+// RoverResult holds the final state of a single rover once its commands have been processed
+type RoverResult struct {
+	ID           int      // deployment number, starting at 1
+	Name         string   // identifier given in the mission input, if any
+	Start        Position // position the rover was in before processing its commands
+	Position     Position // last known position, for a lost rover this is the cell it fell from
+	Status       RoverStatus
+	Err          error // reason the rover was aborted, nil otherwise
+	IgnoredMoves []IgnoredMove
+	Trace        []TraceStep // every command applied to the rover, only recorded when tracing is enabled
+}
+
+// MissionResult holds the result of every rover in a mission, in the order they were deployed
+type MissionResult struct {
+	Plateau *Plateau // plateau the mission ran on, obstacles included
+	Rovers  []RoverResult
+	Ticks   []Tick // state of every rover after each tick, only recorded in lockstep mode
+}
+
 type MissionControl struct {
+	mu              sync.Mutex // serialises missions and session calls, MissionControl state is not safe for concurrent use otherwise
 	plateau         *Plateau
-	occupiedSquares map[Coordinates]int // contains the position of an existing (not moving) rover as the key. Value is the rover ID
+	occupiedSquares map[Coordinates]int      // contains the position of an existing (not moving) rover as the key. Value is the rover ID
+	scents          map[Coordinates]struct{} // cells a rover was lost from, later rovers will not follow it off the edge
+	rovers          map[int]*Rover           // rovers still on the plateau by id, so later rovers can push or swap with them
+	boundary        BoundaryPolicy
+	collision       CollisionPolicy
+	sinks           []EventSink
+	trace           bool
+	lockstep        bool
+	deployed        []*deployedRover // rovers deployed with Deploy, in deployment order
+	numberAttempts  bool             // number the rovers by Deploy call, failed ones included, see WithAttemptNumbering
+	attempts        int              // Deploy calls so far
+	maxRovers       int              // rovers Deploy may deploy, zero for no limit
 }
 
+// Option configures optional MissionControl behaviour
+type Option func(*MissionControl)
+
 type MissionControlFactory interface {
-	Create(plateau *Plateau) (*MissionControl, error)
+	Create(plateau *Plateau, opts ...Option) (*MissionControl, error)
 }
 
-type defaultMissionControlFactory struct{}
+type defaultMissionControlFactory struct {
+	opts []Option
+}
 
-func NewMissionControlFactory() *defaultMissionControlFactory {
-	return &defaultMissionControlFactory{}
+// NewMissionControlFactory returns a factory applying the given options to every MissionControl it creates
+func NewMissionControlFactory(opts ...Option) *defaultMissionControlFactory {
+	return &defaultMissionControlFactory{
+		opts: opts,
+	}
 }
 
-func (f *defaultMissionControlFactory) Create(plateau *Plateau) (*MissionControl, error) {
-	return NewMissionControl(plateau)
+// Create returns a new MissionControl for the plateau. Options given here are applied after the factory's own
+func (f *defaultMissionControlFactory) Create(plateau *Plateau, opts ...Option) (*MissionControl, error) {
+	return NewMissionControl(plateau, append(append([]Option{}, f.opts...), opts...)...)
 }
 
 // NewCoordinates takes a pair of int x, y coordinates and returns a coordinates struct and performs no validation
@@ -104,6 +169,21 @@ func NewPosition(p *Plateau, c Coordinates, d Direction) (*Position, error) {
 	return pos, nil
 }
 
+// X returns the x coordinate of the position
+func (p *Position) X() int {
+	return p.coordinates.x
+}
+
+// Y returns the y coordinate of the position
+func (p *Position) Y() int {
+	return p.coordinates.y
+}
+
+// Direction returns the heading of the position
+func (p *Position) Direction() Direction {
+	return p.direction
+}
+
 // String implements the Stringer interface
 func (p *Position) String() string {
 	return fmt.Sprintf("%d %d %s", p.coordinates.x, p.coordinates.y, p.direction)
@@ -125,17 +205,23 @@ func NewRover(id int, p *Position) (*Rover, error) {
 func (r *Rover) move() Position {
 	// we do not mutate the original
 	nextPosition := *r.position
-	switch r.position.direction {
+	nextPosition.coordinates = r.position.coordinates.ahead(r.position.direction)
+	return nextPosition
+}
+
+// ahead returns the coordinates one cell away in the given direction, which may be off the plateau
+func (c Coordinates) ahead(d Direction) Coordinates {
+	switch d {
 	case N:
-		nextPosition.coordinates.y++
+		c.y++
 	case E:
-		nextPosition.coordinates.x++
+		c.x++
 	case S:
-		nextPosition.coordinates.y--
+		c.y--
 	case W:
-		nextPosition.coordinates.x--
+		c.x--
 	}
-	return nextPosition
+	return c
 }
 
 // turnLeft causes the Rover to rotate 90 degrees on itself to the left
@@ -189,24 +275,44 @@ func NewPlateau(maxX, maxY, minPlateauSizeX, minPlateauSizeY int) (*Plateau, err
 	}, nil
 }
 
-// NewMissionControl takes a pointer to a Plateau struct and returns a pointer to a new MissionControl struct returning an error should the given Plateau be nil
-func NewMissionControl(p *Plateau) (*MissionControl, error) {
+// MaxX returns the x coordinate of the upper-right corner of the Plateau
+func (p *Plateau) MaxX() int {
+	return p.maxX
+}
+
+// MaxY returns the y coordinate of the upper-right corner of the Plateau
+func (p *Plateau) MaxY() int {
+	return p.maxY
+}
+
+// NewMissionControl takes a pointer to a Plateau struct and optional settings and returns a pointer to a new MissionControl struct returning an error should the given Plateau be nil
+func NewMissionControl(p *Plateau, opts ...Option) (*MissionControl, error) {
 	if p == nil {
 		return nil, ErrPlateauIsNil
 	}
 
-	return &MissionControl{
+	mc := &MissionControl{
 		plateau:         p,
 		occupiedSquares: make(map[Coordinates]int),
-	}, nil
+	}
+
+	for _, opt := range opts {
+		opt(mc)
+	}
+
+	return mc, nil
 }
 
-// validate takes a Position pointer and returns an error should the desired Position fail validation or if another rover is already at that Position
+// validate takes a Position pointer and returns an error should the desired Position fail validation, be covered by an obstacle or if another rover is already at that Position
 func (mc *MissionControl) validate(pos *Position) error {
 	if err := validateBoundaries(pos, mc.plateau); err != nil {
 		return err
 	}
 
+	if mc.plateau.isObstructed(pos.coordinates) {
+		return ErrObstacleCollision
+	}
+
 	if _, ok := mc.occupiedSquares[pos.coordinates]; ok {
 		return ErrRoverCollision
 	}
@@ -214,48 +320,265 @@ func (mc *MissionControl) validate(pos *Position) error {
 	return nil
 }
 
-// RunRover takes a Rover pointer and a command string, returning a feedback string and an error should the commands fail. It keeps track of previous placed Rover in the Plateau and processes the commands giving feedback  to the user
-func (mc *MissionControl) RunRover(r *Rover, commands string) (string, error) {
+// RunRover takes a Rover pointer and a command string, returning a feedback string and an error should the commands fail. It keeps track of previous placed Rover in the Plateau and processes the commands giving feedback  to the user.
+// Processing stops with ErrMissionCancelled once ctx is done
+func (mc *MissionControl) RunRover(ctx context.Context, r *Rover, commands string) (string, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	result, err := mc.runRover(ctx, r, commands)
+	if err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+// runRover places the Rover and processes its commands, returning the full RoverResult including any ignored moves
+func (mc *MissionControl) runRover(ctx context.Context, r *Rover, commands string) (RoverResult, error) {
+	if err := mc.place(r); err != nil {
+		return RoverResult{}, err
+	}
+	return mc.drive(ctx, r, commands)
+}
+
+// place deploys the Rover on the plateau, failing if its cell is out of bounds, obstructed or occupied
+func (mc *MissionControl) place(r *Rover) error {
 	// check to see if mission control is attempting to place a rover on a location that's occupied
 	if err := mc.validate(r.position); err != nil {
 		// original error remains wrapped
-		return "", fmt.Errorf("new rover with id %d cannot be placed at (%s): %w", r.id, r.position.String(), err)
+		return fmt.Errorf("new rover with id %s cannot be placed at (%s): %w", r.label(), r.position.String(), err)
 	}
 
 	// place an entry in the occupied map using x, y coordinates as key and rover id as the value
 	mc.occupiedSquares[r.position.coordinates] = r.id
+	mc.track(r)
+	mc.emit(RoverPlaced{RoverID: r.id, RoverName: r.name, Position: *r.position})
+	return nil
+}
+
+// drive processes the commands of a placed Rover, returning the full RoverResult including any ignored moves. The rover stays where it got to when ctx is done.
+// Should processing fail the result still holds the ignored moves and trace steps of the commands processed before it did
+func (mc *MissionControl) drive(ctx context.Context, r *Rover, commands string) (RoverResult, error) {
+	result := RoverResult{ID: r.id, Name: r.name, Start: *r.position}
 
 	// process commands
+	step := 0
 	for _, c := range commands {
+		step++
+		before := *r.position
+
+		if err := checkCancelled(ctx, step); err != nil {
+			return result, err
+		}
+
 		switch Command(c) {
 		case CmdLeft:
 			r.turnLeft()
+			mc.record(&result, step, CmdLeft, before, *r.position, OutcomeTurned)
+			mc.emit(RoverTurned{RoverID: r.id, RoverName: r.name, Step: step, Command: CmdLeft, From: before, To: *r.position})
 		case CmdRight:
 			r.turnRight()
+			mc.record(&result, step, CmdRight, before, *r.position, OutcomeTurned)
+			mc.emit(RoverTurned{RoverID: r.id, RoverName: r.name, Step: step, Command: CmdRight, From: before, To: *r.position})
 		case CmdMove:
-			// store current position before moving
-			currentPosKey := r.position.coordinates
+			outcome, done, err := mc.moveRover(r, step, &result)
+			if err != nil {
+				mc.emit(RoverFinished{RoverID: r.id, RoverName: r.name, Position: *r.position, Status: StatusAborted, Err: err})
+				return result, err
+			}
 
-			nextPos := r.move()
+			mc.record(&result, step, CmdMove, before, *r.position, outcome)
 
-			// handle invalid moves
-			if err := mc.validate(&nextPos); err != nil {
-				// this is an invalid move so it will be ignored and we carry on attempting remaining commands
-				log.Printf("WARN: Rover %d ignored move to (%v): %s", r.id, nextPos.String(), err.Error())
-				continue
+			switch outcome {
+			case OutcomeMoved, OutcomePushed, OutcomeSwapped:
+				mc.emit(RoverMoved{RoverID: r.id, RoverName: r.name, Step: step, From: before, To: *r.position, Outcome: outcome})
 			}
 
-			r.position.set(nextPos)
+			// the rover will not process any more commands
+			if done {
+				return mc.finish(result), nil
+			}
+		}
+	}
+
+	result.Position = *r.position
+	return mc.finish(result), nil
+}
+
+// finish emits the RoverFinished event for a finalised result and returns it
+func (mc *MissionControl) finish(result RoverResult) RoverResult {
+	mc.emit(RoverFinished{RoverID: result.ID, RoverName: result.Name, Position: result.Position, Status: result.Status, Err: result.Err})
+	return result
+}
+
+// moveRover attempts to move the Rover one cell forward applying the boundary and collision policies. It returns the outcome of the move and true when the rover must stop processing commands, in which case the result has been finalised
+func (mc *MissionControl) moveRover(r *Rover, step int, result *RoverResult) (Outcome, bool, error) {
+	// store current position before moving
+	currentPosKey := r.position.coordinates
+
+	nextPos := r.move()
 
-			// delete existing state from the map after rover moves
+	// let the boundary policy decide what happens to moves off the plateau
+	if validateBoundaries(&nextPos, mc.plateau) != nil {
+		action, target := mc.boundaryPolicy().OffPlateau(mc.plateau, *r.position, nextPos)
+
+		switch action {
+		case BoundaryActionMove:
+			nextPos = target
+
+		case BoundaryActionLose:
+			if _, scented := mc.scents[currentPosKey]; scented {
+				mc.ignore(r, step, nextPos, ErrScentProtected, result)
+				return OutcomeBlockedByBoundary, false, nil
+			}
+
+			mc.leaveScent(currentPosKey)
 			delete(mc.occupiedSquares, currentPosKey)
+			delete(mc.rovers, r.id)
 
-			// and update with new position here
-			mc.occupiedSquares[nextPos.coordinates] = r.id
+			result.Status = StatusLost
+			result.Position = *r.position
+			return OutcomeLost, true, nil
+
+		case BoundaryActionAbort:
+			result.Status = StatusAborted
+			result.Err = fmt.Errorf("rover %s aborted at step %d moving to (%s): %w", r.label(), step, nextPos.String(), ErrPositionOutOfBounds)
+			result.Position = *r.position
+			return OutcomeBlockedByBoundary, true, nil
 		}
 	}
 
-	return r.position.String(), nil
+	// handle invalid moves
+	if err := mc.validate(&nextPos); err != nil {
+		if errors.Is(err, ErrPositionOutOfBounds) {
+			// this is an invalid move so it will be ignored and we carry on attempting remaining commands
+			mc.ignore(r, step, nextPos, err, result)
+			return OutcomeBlockedByBoundary, false, nil
+		}
+
+		return mc.collide(r, step, nextPos, err, result)
+	}
+
+	mc.relocate(r, nextPos)
+	return OutcomeMoved, false, nil
+}
+
+// collide applies the collision policy to a move blocked by another rover or an obstacle
+func (mc *MissionControl) collide(r *Rover, step int, nextPos Position, reason error, result *RoverResult) (Outcome, bool, error) {
+	c := Collision{
+		RoverID:   r.id,
+		Step:      step,
+		From:      *r.position,
+		To:        nextPos,
+		BlockerID: mc.occupiedSquares[nextPos.coordinates],
+		Reason:    reason,
+	}
+
+	switch mc.collisionPolicy().OnCollision(c) {
+	case CollisionActionPush:
+		if mc.push(c) {
+			mc.relocate(r, nextPos)
+			return OutcomePushed, false, nil
+		}
+
+	case CollisionActionSwap:
+		if mc.swap(r, c) {
+			return OutcomeSwapped, false, nil
+		}
+
+	case CollisionActionHalt:
+		mc.ignore(r, step, nextPos, reason, result)
+		result.Status = StatusHalted
+		result.Position = *r.position
+		return blockedOutcome(reason), true, nil
+
+	case CollisionActionAbort:
+		return blockedOutcome(reason), true, fmt.Errorf("rover %s blocked at step %d moving to (%s): %w", r.label(), step, nextPos.String(), reason)
+	}
+
+	// skip the move, also the fallback when a push or swap is not possible
+	mc.ignore(r, step, nextPos, reason, result)
+	return blockedOutcome(reason), false, nil
+}
+
+// ignore records a move that was not applied
+func (mc *MissionControl) ignore(r *Rover, step int, target Position, reason error, result *RoverResult) {
+	mc.emit(MoveRejected{RoverID: r.id, RoverName: r.name, Step: step, From: *r.position, Target: target, Reason: reason, Outcome: blockedOutcome(reason)})
+	result.IgnoredMoves = append(result.IgnoredMoves, IgnoredMove{Step: step, Target: target, Reason: reason})
+}
+
+// relocate moves the Rover to the given position, keeping the occupied squares up to date
+func (mc *MissionControl) relocate(r *Rover, pos Position) {
+	// delete existing state from the map after rover moves
+	delete(mc.occupiedSquares, r.position.coordinates)
+
+	r.position.set(pos)
+
+	// and update with new position here
+	mc.occupiedSquares[pos.coordinates] = r.id
+}
+
+// track keeps a reference to a placed Rover so later rovers can push or swap with it
+func (mc *MissionControl) track(r *Rover) {
+	if mc.rovers == nil {
+		mc.rovers = make(map[int]*Rover)
+	}
+	mc.rovers[r.id] = r
+}
+
+// String returns the final position of the rover in the "x y direction" output format, followed by the status for rovers that are no longer operational. Named rovers are prefixed with their name as in the input, e.g. "alpha: 1 3 N"
+func (r RoverResult) String() string {
+	out := r.Position.String()
+	if r.Status != StatusOperational {
+		out = fmt.Sprintf("%s %s", out, strings.ToUpper(r.Status.String()))
+	}
+
+	if r.Name != "" {
+		return fmt.Sprintf("%s: %s", r.Name, out)
+	}
+	return out
+}
+
+// Label returns the name of the rover or, when it has none, its deployment number
+func (r RoverResult) Label() string {
+	return roverLabel(r.ID, r.Name)
+}
+
+// label returns the name of the rover or, when it has none, its id
+func (r *Rover) label() string {
+	return roverLabel(r.id, r.name)
+}
+
+// roverLabel identifies a rover in messages by its name, falling back to its numeric id
+func roverLabel(id int, name string) string {
+	if name != "" {
+		return name
+	}
+	return strconv.Itoa(id)
+}
+
+// leaveScent marks the cell a rover was lost from
+func (mc *MissionControl) leaveScent(c Coordinates) {
+	if mc.scents == nil {
+		mc.scents = make(map[Coordinates]struct{})
+	}
+	mc.scents[c] = struct{}{}
+}
+
+// implement stringer interface so statuses print in results
+func (s RoverStatus) String() string {
+	switch s {
+	case StatusOperational:
+		return "operational"
+	case StatusLost:
+		return "lost"
+	case StatusAborted:
+		return "aborted"
+	case StatusHalted:
+		return "halted"
+	default:
+		return "?"
+	}
 }
 
 // implement stringer interface so we can print a friendly direction when using a Print function
@@ -274,24 +597,78 @@ func (d Direction) String() string {
 	}
 }
 
-func (mc *MissionControl) Execute(input *MissionControlInput) ([]string, error) {
-	var output []string
+// Execute runs every rover instruction in order and returns the final position of each rover as a string
+func (mc *MissionControl) Execute(ctx context.Context, input *MissionControlInput) ([]string, error) {
+	result, err := mc.Simulate(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]string, 0, len(result.Rovers))
+	for _, roverResult := range result.Rovers {
+		output = append(output, roverResult.String())
+	}
+
+	return output, nil
+}
+
+// Simulate runs every rover instruction in order, or all of them in lockstep when enabled, and returns the detailed MissionResult.
+// The mission stops with ErrMissionCancelled once ctx is done, the context being checked before every rover and periodically while its commands are processed
+func (mc *MissionControl) Simulate(ctx context.Context, input *MissionControlInput) (*MissionResult, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if mc.lockstep {
+		return mc.simulateLockstep(ctx, input)
+	}
+
+	result := &MissionResult{
+		Plateau: mc.plateau,
+		Rovers:  make([]RoverResult, 0, len(input.Instructions)),
+	}
 
 	for i, instruction := range input.Instructions {
 		roverID := i + 1
 
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrMissionCancelled, err)
+		}
+
 		currentRover, err := NewRover(roverID, instruction.InitialPosition)
 		if err != nil {
-			return nil, fmt.Errorf("%w %d: %v", ErrRoverCreating, roverID, err)
+			return nil, fmt.Errorf("%w %s: %v", ErrRoverCreating, roverLabel(roverID, instruction.Name), err)
 		}
+		currentRover.name = instruction.Name
 
-		singleRoverOutput, err := mc.RunRover(currentRover, instruction.Commands)
+		roverResult, err := mc.runRover(ctx, currentRover, instruction.Commands)
 		if err != nil {
-			return nil, fmt.Errorf("%w %d: %v", ErrRoverInstructions, roverID, err)
+			return nil, fmt.Errorf("%w %s: %w", ErrRoverInstructions, currentRover.label(), err)
 		}
 
-		output = append(output, singleRoverOutput)
+		result.Rovers = append(result.Rovers, roverResult)
 	}
 
-	return output, nil
+	// rovers pushed or swapped by later rovers have moved since their own result was recorded
+	for i := range result.Rovers {
+		if r, ok := mc.rovers[result.Rovers[i].ID]; ok {
+			result.Rovers[i].Position = *r.position
+		}
+	}
+
+	return result, nil
+}
+
+// cancelCheckInterval is the number of commands processed between two checks of the context, a check costs more than a command
+const cancelCheckInterval = 1024
+
+// checkCancelled returns ErrMissionCancelled once ctx is done. Only every cancelCheckInterval-th step looks at the context
+func checkCancelled(ctx context.Context, step int) error {
+	if step%cancelCheckInterval != 0 {
+		return nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrMissionCancelled, err)
+	}
+	return nil
 }
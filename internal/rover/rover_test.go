@@ -1,7 +1,8 @@
 package rover
 
 import (
-	"mars/internal/config"
+	"context"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -64,12 +65,10 @@ func TestNewPlateau(t *testing.T) {
 		},
 	}
 
-	cfg := config.Default()
-
 	for name, testCase := range testCases {
 		t.Run(name, func(t *testing.T) {
 
-			newPlateau, err := NewPlateau(testCase.maxX, testCase.maxY, cfg.MinPlateauX, cfg.MinPlateauY)
+			newPlateau, err := NewPlateau(testCase.maxX, testCase.maxY, testMinSize, testMinSize)
 			assert.Equal(t, newPlateau, testCase.wantPlateau)
 
 			if testCase.wantErr != nil {
@@ -331,7 +330,7 @@ func TestRunRover(t *testing.T) {
 	for name, tc := range testCases {
 		t.Run(name, func(t *testing.T) {
 
-			result, err := tc.mc.RunRover(tc.rover, tc.commands)
+			result, err := tc.mc.RunRover(context.Background(), tc.rover, tc.commands)
 
 			if tc.wantErr != nil {
 				require.ErrorIs(t, err, tc.wantErr)
@@ -389,12 +388,13 @@ func TestMove(t *testing.T) {
 
 // turnLeft and turnRight are implicitly tested
 
+// testMinSize is the default minimum plateau size of the config package, which cannot be imported here
+const testMinSize = 2
+
 func createTestPlateau(t *testing.T, x, y int) *Plateau {
 	t.Helper()
 
-	cfg := config.Default()
-
-	testPlateau, _ := NewPlateau(x, y, cfg.MinPlateauX, cfg.MinPlateauY)
+	testPlateau, _ := NewPlateau(x, y, testMinSize, testMinSize)
 	return testPlateau
 }
 
@@ -474,7 +474,7 @@ func TestMissionControlExecute(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			var output []string
 
-			output, err := tc.mc.Execute(tc.mcInput)
+			output, err := tc.mc.Execute(context.Background(), tc.mcInput)
 
 			if tc.wantErr != nil {
 				require.ErrorIs(t, err, tc.wantErr)
@@ -487,3 +487,204 @@ func TestMissionControlExecute(t *testing.T) {
 		})
 	}
 }
+
+func TestMissionControlSimulate(t *testing.T) {
+	t.Parallel()
+	testPlateau := createTestPlateau(t, 5, 5)
+
+	testCases := map[string]struct {
+		mcInput     *MissionControlInput
+		wantResults []RoverResult
+		wantErr     error
+	}{
+		"ok - no ignored moves": {
+			mcInput: &MissionControlInput{
+				Instructions: []RoverInstruction{
+					*createTestSingleRoverInstruction(t, testPlateau, 1, 2, N, "LMLMLMLMM"),
+				},
+			},
+			wantResults: []RoverResult{
+				{ID: 1, Start: Position{coordinates: Coordinates{x: 1, y: 2}, direction: N}, Position: Position{coordinates: Coordinates{x: 1, y: 3}, direction: N}},
+			},
+		},
+		"ok - ignored moves record step, target and reason": {
+			mcInput: &MissionControlInput{
+				Instructions: []RoverInstruction{
+					*createTestSingleRoverInstruction(t, testPlateau, 3, 3, N, ""),
+					*createTestSingleRoverInstruction(t, testPlateau, 3, 1, N, "MMRMMM"),
+				},
+			},
+			wantResults: []RoverResult{
+				{ID: 1, Start: Position{coordinates: Coordinates{x: 3, y: 3}, direction: N}, Position: Position{coordinates: Coordinates{x: 3, y: 3}, direction: N}},
+				{
+					ID:       2,
+					Start:    Position{coordinates: Coordinates{x: 3, y: 1}, direction: N},
+					Position: Position{coordinates: Coordinates{x: 5, y: 2}, direction: E},
+					IgnoredMoves: []IgnoredMove{
+						{Step: 2, Target: Position{coordinates: Coordinates{x: 3, y: 3}, direction: N}, Reason: ErrRoverCollision},
+						{Step: 6, Target: Position{coordinates: Coordinates{x: 6, y: 2}, direction: E}, Reason: ErrPositionOutOfBounds},
+					},
+				},
+			},
+		},
+		"err - ErrRoverInstructions": {
+			mcInput: &MissionControlInput{
+				Instructions: []RoverInstruction{
+					*createTestSingleRoverInstruction(t, testPlateau, 1, 2, N, ""),
+					*createTestSingleRoverInstruction(t, testPlateau, 1, 2, N, ""),
+				},
+			},
+			wantErr: ErrRoverInstructions,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mc, err := NewMissionControl(testPlateau)
+			require.NoError(t, err)
+
+			result, err := mc.Simulate(context.Background(), tc.mcInput)
+
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				assert.Nil(t, result)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantResults, result.Rovers)
+		})
+	}
+}
+
+// cancelSink cancels its context on the first event it receives and counts the turns that follow
+type cancelSink struct {
+	cancel context.CancelFunc
+	turns  int
+}
+
+func (s *cancelSink) HandleEvent(e Event) {
+	s.cancel()
+	if _, ok := e.(RoverTurned); ok {
+		s.turns++
+	}
+}
+
+func TestMissionControlSimulate_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		opts        []Option
+		cancelFirst bool // cancel before the mission starts rather than on its first event
+		wantTurns   int
+	}{
+		"err - cancelled before the mission": {
+			cancelFirst: true,
+		},
+		"err - cancelled while processing commands": {
+			wantTurns: cancelCheckInterval - 1,
+		},
+		"err - cancelled in lockstep": {
+			opts:      []Option{WithLockstep()},
+			wantTurns: cancelCheckInterval - 1,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			plateau := createTestPlateau(t, 5, 5)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.cancelFirst {
+				cancel()
+			}
+
+			sink := &cancelSink{cancel: cancel}
+			mc, err := NewMissionControl(plateau, append(tc.opts, WithEventSink(sink))...)
+			require.NoError(t, err)
+
+			result, err := mc.Simulate(ctx, &MissionControlInput{
+				Instructions: []RoverInstruction{
+					*createTestSingleRoverInstruction(t, plateau, 1, 2, N, strings.Repeat("L", 10*cancelCheckInterval)),
+				},
+			})
+
+			require.ErrorIs(t, err, ErrMissionCancelled)
+			assert.ErrorIs(t, err, context.Canceled)
+			assert.Nil(t, result)
+			assert.Equal(t, tc.wantTurns, sink.turns)
+		})
+	}
+}
+
+func TestMissionControlSimulate_Names(t *testing.T) {
+	t.Parallel()
+
+	named := func(name string, ri *RoverInstruction) RoverInstruction {
+		ri.Name = name
+		return *ri
+	}
+
+	testCases := map[string]struct {
+		opts       []Option
+		input      func(p *Plateau) []RoverInstruction
+		wantOutput []string
+		wantErrMsg string
+	}{
+		"ok - named rovers are reported by name": {
+			input: func(p *Plateau) []RoverInstruction {
+				return []RoverInstruction{
+					named("alpha", createTestSingleRoverInstruction(t, p, 1, 2, N, "M")),
+					*createTestSingleRoverInstruction(t, p, 3, 3, E, "M"),
+				}
+			},
+			wantOutput: []string{"alpha: 1 3 N", "4 3 E"},
+		},
+		"ok - named rovers are reported by name in lockstep": {
+			opts: []Option{WithLockstep(), WithBoundaryPolicy(LoseOffEdge{})},
+			input: func(p *Plateau) []RoverInstruction {
+				return []RoverInstruction{named("alpha", createTestSingleRoverInstruction(t, p, 5, 5, N, "M"))}
+			},
+			wantOutput: []string{"alpha: 5 5 N LOST"},
+		},
+		"err - placement error names the rover": {
+			input: func(p *Plateau) []RoverInstruction {
+				return []RoverInstruction{
+					named("alpha", createTestSingleRoverInstruction(t, p, 1, 2, N, "")),
+					named("beta", createTestSingleRoverInstruction(t, p, 1, 2, N, "")),
+				}
+			},
+			wantErrMsg: "rover error executing instruction beta: new rover with id beta cannot be placed at (1 2 N): path is blocked by another rover",
+		},
+		"err - aborted mission names the rover": {
+			opts: []Option{WithCollisionPolicy(AbortOnCollision{})},
+			input: func(p *Plateau) []RoverInstruction {
+				return []RoverInstruction{
+					named("alpha", createTestSingleRoverInstruction(t, p, 1, 3, N, "")),
+					named("beta", createTestSingleRoverInstruction(t, p, 1, 2, N, "M")),
+				}
+			},
+			wantErrMsg: "rover error executing instruction beta: rover beta blocked at step 1 moving to (1 3 N): path is blocked by another rover",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			plateau := createTestPlateau(t, 5, 5)
+
+			mc, err := NewMissionControl(plateau, tc.opts...)
+			require.NoError(t, err)
+
+			got, err := mc.Execute(context.Background(), &MissionControlInput{Instructions: tc.input(plateau)})
+
+			if tc.wantErrMsg != "" {
+				require.EqualError(t, err, tc.wantErrMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantOutput, got)
+		})
+	}
+}
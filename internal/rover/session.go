@@ -0,0 +1,152 @@
+package rover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// deployedRover keeps the state of a rover deployed with Deploy between command batches
+type deployedRover struct {
+	rover  *Rover
+	start  Position // position the rover was deployed at
+	status RoverStatus
+	err    error
+}
+
+// Deploy places a new rover on the plateau so it can be driven later on with Drive, processing its commands if it has any. Rovers are numbered in deployment order and are known by their name, if they have one, or by their number.
+// Deploy, Drive and Rovers are safe for concurrent use. They always run rovers one after another, whatever the execution mode. A batch of commands cancelled through ctx leaves the rover where it got to, still operational.
+// Deploy and Drive return a zero RoverResult when they fail before the rover ran any of its commands
+func (mc *MissionControl) Deploy(ctx context.Context, instruction RoverInstruction) (RoverResult, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	// every attempt takes a number, the ones turned down for the rover limit as well
+	roverID := len(mc.deployed) + 1
+	if mc.numberAttempts {
+		mc.attempts++
+		roverID = mc.attempts
+	}
+	label := roverLabel(roverID, instruction.Name)
+
+	if mc.maxRovers > 0 && len(mc.deployed) >= mc.maxRovers {
+		return RoverResult{}, fmt.Errorf("%w: at most %d", ErrRoverLimit, mc.maxRovers)
+	}
+
+	if instruction.InitialPosition == nil {
+		return RoverResult{}, fmt.Errorf("%w %s: %v", ErrRoverCreating, label, ErrRoverPositionIsNil)
+	}
+
+	if _, taken := mc.lookup(label); taken {
+		return RoverResult{}, fmt.Errorf("%w: %q", ErrRoverNameTaken, label)
+	}
+
+	// the rover gets its own copy of the position so the caller cannot move it behind mission control's back
+	position := *instruction.InitialPosition
+	currentRover, err := NewRover(roverID, &position)
+	if err != nil {
+		return RoverResult{}, fmt.Errorf("%w %s: %v", ErrRoverCreating, label, err)
+	}
+	currentRover.name = instruction.Name
+
+	if err := mc.place(currentRover); err != nil {
+		return RoverResult{}, fmt.Errorf("%w %s: %w", ErrRoverInstructions, label, err)
+	}
+
+	deployed := &deployedRover{rover: currentRover, start: position}
+	mc.deployed = append(mc.deployed, deployed)
+
+	return mc.driveDeployed(ctx, deployed, instruction.Commands)
+}
+
+// WithAttemptNumbering makes Deploy number every rover it is given, including the ones it fails to deploy, so rovers keep the number of their instruction as they do with Simulate
+func WithAttemptNumbering() Option {
+	return func(mc *MissionControl) {
+		mc.numberAttempts = true
+	}
+}
+
+// WithRoverLimit makes Deploy fail with ErrRoverLimit once limit rovers have been deployed, zero for no limit
+func WithRoverLimit(limit int) Option {
+	return func(mc *MissionControl) {
+		mc.maxRovers = limit
+	}
+}
+
+// Drive sends a batch of commands to the rover known by the given name or number and returns its state once they have been processed. The start position, ignored moves and trace steps only cover this batch, with steps counted from 1 within it
+func (mc *MissionControl) Drive(ctx context.Context, label string, commands string) (RoverResult, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	deployed, ok := mc.lookup(label)
+	if !ok {
+		return RoverResult{}, fmt.Errorf("%w: %q", ErrRoverNotFound, label)
+	}
+
+	if deployed.status != StatusOperational {
+		return RoverResult{}, fmt.Errorf("%w: rover %s is %s", ErrRoverNotOperational, label, deployed.status)
+	}
+
+	return mc.driveDeployed(ctx, deployed, commands)
+}
+
+// Rovers returns the current state of every rover deployed with Deploy, in deployment order
+func (mc *MissionControl) Rovers() []RoverResult {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	results := make([]RoverResult, 0, len(mc.deployed))
+	for _, deployed := range mc.deployed {
+		results = append(results, deployed.result())
+	}
+	return results
+}
+
+// driveDeployed processes a batch of commands for a deployed rover, keeping its status for the following batches. A mission failure aborts the rover, a cancelled batch does not.
+// The result of a failed batch still holds the ignored moves and trace steps of the commands processed before it failed
+func (mc *MissionControl) driveDeployed(ctx context.Context, deployed *deployedRover, commands string) (RoverResult, error) {
+	result, err := mc.drive(ctx, deployed.rover, commands)
+	if err != nil {
+		if !errors.Is(err, ErrMissionCancelled) {
+			deployed.status = StatusAborted
+			deployed.err = err
+		}
+
+		partial := deployed.result()
+		partial.Start = result.Start
+		partial.IgnoredMoves = result.IgnoredMoves
+		partial.Trace = result.Trace
+		return partial, fmt.Errorf("%w %s: %w", ErrRoverInstructions, deployed.rover.label(), err)
+	}
+
+	deployed.status = result.Status
+	deployed.err = result.Err
+
+	// the rover may since have been pushed or swapped, or be lost in which case the result already holds its last position
+	if result.Status != StatusLost {
+		result.Position = *deployed.rover.position
+	}
+	return result, nil
+}
+
+// lookup returns the deployed rover known by the given name or number
+func (mc *MissionControl) lookup(label string) (*deployedRover, bool) {
+	for _, deployed := range mc.deployed {
+		if deployed.rover.label() == label {
+			return deployed, true
+		}
+	}
+	return nil, false
+}
+
+// result returns the current state of the deployed rover, starting from where it was deployed
+func (d *deployedRover) result() RoverResult {
+	return RoverResult{
+		ID:       d.rover.id,
+		Name:     d.rover.name,
+		Start:    d.start,
+		Position: *d.rover.position,
+		Status:   d.status,
+		Err:      d.err,
+	}
+}
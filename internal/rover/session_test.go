@@ -0,0 +1,230 @@
+package rover
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMissionControlDeployAndDrive(t *testing.T) {
+	t.Parallel()
+
+	plateau := createTestPlateau(t, 5, 5)
+	mc, err := NewMissionControl(plateau, WithBoundaryPolicy(LoseOffEdge{}))
+	require.NoError(t, err)
+
+	got, err := mc.Deploy(context.Background(), *createTestSingleRoverInstruction(t, plateau, 1, 2, N, ""))
+	require.NoError(t, err)
+	assert.Equal(t, RoverResult{ID: 1, Start: pos(1, 2, N), Position: pos(1, 2, N)}, got)
+
+	alpha := *createTestSingleRoverInstruction(t, plateau, 3, 3, E, "M")
+	alpha.Name = "alpha"
+	got, err = mc.Deploy(context.Background(), alpha)
+	require.NoError(t, err)
+	assert.Equal(t, RoverResult{ID: 2, Name: "alpha", Start: pos(3, 3, E), Position: pos(4, 3, E)}, got)
+
+	// commands come in batches, steps restart at 1
+	got, err = mc.Drive(context.Background(), "1", "MM")
+	require.NoError(t, err)
+	assert.Equal(t, RoverResult{ID: 1, Start: pos(1, 2, N), Position: pos(1, 4, N)}, got)
+
+	got, err = mc.Drive(context.Background(), "alpha", "RMLM")
+	require.NoError(t, err)
+	assert.Equal(t, "alpha: 5 2 E", got.String())
+
+	got, err = mc.Drive(context.Background(), "alpha", "M")
+	require.NoError(t, err)
+	assert.Equal(t, StatusLost, got.Status)
+
+	assert.Equal(t, []string{"1 4 N", "alpha: 5 2 E LOST"}, resultStrings(mc.Rovers()))
+}
+
+func TestMissionControlDeploy_AttemptNumbering(t *testing.T) {
+	t.Parallel()
+
+	plateau := createTestPlateau(t, 5, 5)
+	mc, err := NewMissionControl(plateau, WithAttemptNumbering())
+	require.NoError(t, err)
+
+	_, err = mc.Deploy(context.Background(), *createTestSingleRoverInstruction(t, plateau, 1, 1, N, ""))
+	require.NoError(t, err)
+	_, err = mc.Deploy(context.Background(), *createTestSingleRoverInstruction(t, plateau, 1, 1, N, ""))
+	require.ErrorIs(t, err, ErrRoverCollision)
+
+	// the rover that could not be deployed keeps its number
+	got, err := mc.Deploy(context.Background(), *createTestSingleRoverInstruction(t, plateau, 2, 2, N, ""))
+	require.NoError(t, err)
+	assert.Equal(t, 3, got.ID)
+
+	_, err = mc.Drive(context.Background(), "3", "M")
+	require.NoError(t, err)
+	_, err = mc.Drive(context.Background(), "2", "M")
+	require.ErrorIs(t, err, ErrRoverNotFound)
+}
+
+func TestMissionControlDeploy_AttemptNumberingRoverLimit(t *testing.T) {
+	t.Parallel()
+
+	plateau := createTestPlateau(t, 5, 5)
+	mc, err := NewMissionControl(plateau, WithAttemptNumbering(), WithRoverLimit(1))
+	require.NoError(t, err)
+
+	_, err = mc.Deploy(context.Background(), *createTestSingleRoverInstruction(t, plateau, 1, 1, N, ""))
+	require.NoError(t, err)
+
+	// deploys turned down for the limit are numbered like any other failed deploy
+	for range 2 {
+		_, err = mc.Deploy(context.Background(), *createTestSingleRoverInstruction(t, plateau, 2, 2, N, ""))
+		require.ErrorIs(t, err, ErrRoverLimit)
+	}
+	assert.Equal(t, 3, mc.attempts)
+}
+
+func TestMissionControlDeployAndDrive_Errors(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		opts    []Option
+		run     func(mc *MissionControl, p *Plateau) error
+		wantErr error
+	}{
+		"err - ErrRoverNotFound": {
+			run: func(mc *MissionControl, p *Plateau) error {
+				_, err := mc.Drive(context.Background(), "alpha", "M")
+				return err
+			},
+			wantErr: ErrRoverNotFound,
+		},
+		"err - ErrRoverNameTaken": {
+			run: func(mc *MissionControl, p *Plateau) error {
+				_, _ = mc.Deploy(context.Background(), *createTestSingleRoverInstruction(t, p, 1, 1, N, ""))
+				_, err := mc.Deploy(context.Background(), RoverInstruction{Name: "1", InitialPosition: createTestRoverPosition(t, p, 2, 2, N)})
+				return err
+			},
+			wantErr: ErrRoverNameTaken,
+		},
+		"err - ErrRoverCollision on deploy": {
+			run: func(mc *MissionControl, p *Plateau) error {
+				_, _ = mc.Deploy(context.Background(), *createTestSingleRoverInstruction(t, p, 1, 1, N, ""))
+				_, err := mc.Deploy(context.Background(), *createTestSingleRoverInstruction(t, p, 1, 1, N, ""))
+				return err
+			},
+			wantErr: ErrRoverCollision,
+		},
+		"err - ErrRoverLimit": {
+			opts: []Option{WithRoverLimit(1)},
+			run: func(mc *MissionControl, p *Plateau) error {
+				_, _ = mc.Deploy(context.Background(), *createTestSingleRoverInstruction(t, p, 1, 1, N, ""))
+				_, err := mc.Deploy(context.Background(), *createTestSingleRoverInstruction(t, p, 2, 2, N, ""))
+				return err
+			},
+			wantErr: ErrRoverLimit,
+		},
+		"err - ErrRoverPositionIsNil": {
+			run: func(mc *MissionControl, p *Plateau) error {
+				_, err := mc.Deploy(context.Background(), RoverInstruction{})
+				return err
+			},
+			wantErr: ErrRoverCreating,
+		},
+		"err - ErrRoverNotOperational after halt": {
+			opts: []Option{WithCollisionPolicy(HaltOnCollision{})},
+			run: func(mc *MissionControl, p *Plateau) error {
+				_, _ = mc.Deploy(context.Background(), *createTestSingleRoverInstruction(t, p, 1, 2, N, ""))
+				_, _ = mc.Deploy(context.Background(), *createTestSingleRoverInstruction(t, p, 1, 1, N, "M"))
+				_, err := mc.Drive(context.Background(), "2", "RM")
+				return err
+			},
+			wantErr: ErrRoverNotOperational,
+		},
+		"err - ErrRoverCollision aborts the rover": {
+			opts: []Option{WithCollisionPolicy(AbortOnCollision{})},
+			run: func(mc *MissionControl, p *Plateau) error {
+				_, _ = mc.Deploy(context.Background(), *createTestSingleRoverInstruction(t, p, 1, 2, N, ""))
+				_, _ = mc.Deploy(context.Background(), *createTestSingleRoverInstruction(t, p, 1, 1, N, ""))
+				if _, err := mc.Drive(context.Background(), "2", "M"); err == nil {
+					return nil
+				}
+				_, err := mc.Drive(context.Background(), "2", "RM")
+				return err
+			},
+			wantErr: ErrRoverNotOperational,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			plateau := createTestPlateau(t, 5, 5)
+			mc, err := NewMissionControl(plateau, tc.opts...)
+			require.NoError(t, err)
+
+			require.ErrorIs(t, tc.run(mc, plateau), tc.wantErr)
+		})
+	}
+}
+
+func TestMissionControlDrive_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	plateau := createTestPlateau(t, 5, 5)
+	mc, err := NewMissionControl(plateau, WithBoundaryPolicy(WrapAround{}))
+	require.NoError(t, err)
+
+	_, err = mc.Deploy(context.Background(), *createTestSingleRoverInstruction(t, plateau, 0, 0, N, ""))
+	require.NoError(t, err)
+	_, err = mc.Deploy(context.Background(), *createTestSingleRoverInstruction(t, plateau, 3, 0, N, ""))
+	require.NoError(t, err)
+
+	// every rover goes round the plateau a few times, ending where it started
+	var wg sync.WaitGroup
+	for _, label := range []string{"1", "2"} {
+		for range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := mc.Drive(context.Background(), label, "MMMMMM")
+				assert.NoError(t, err)
+				_ = mc.Rovers()
+			}()
+		}
+	}
+	wg.Wait()
+
+	assert.Equal(t, []string{"0 0 N", "3 0 N"}, resultStrings(mc.Rovers()))
+}
+
+func TestMissionControlDrive_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	plateau := createTestPlateau(t, 5, 5)
+	mc, err := NewMissionControl(plateau)
+	require.NoError(t, err)
+
+	_, err = mc.Deploy(context.Background(), *createTestSingleRoverInstruction(t, plateau, 1, 2, N, ""))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// the cancelled batch leaves the rover where it got to, able to take the next batch
+	got, err := mc.Drive(ctx, "1", strings.Repeat("R", 2*cancelCheckInterval))
+	require.ErrorIs(t, err, ErrMissionCancelled)
+	assert.Equal(t, StatusOperational, got.Status)
+
+	// the batch stopped after cancelCheckInterval-1 turns right, leaving the rover facing W
+	got, err = mc.Drive(context.Background(), "1", "M")
+	require.NoError(t, err)
+	assert.Equal(t, "0 2 W", got.String())
+}
+
+func resultStrings(results []RoverResult) []string {
+	out := make([]string, 0, len(results))
+	for _, r := range results {
+		out = append(out, r.String())
+	}
+	return out
+}
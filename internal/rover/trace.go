@@ -0,0 +1,95 @@
+package rover
+
+import "fmt"
+
+// Outcome describes what happened when a rover applied a single command
+type Outcome int
+
+const (
+	OutcomeUnknown           Outcome = iota
+	OutcomeMoved                     // rover moved one cell forward, possibly across the edge of a wrapping plateau
+	OutcomeTurned                    // rover rotated 90 degrees
+	OutcomeBlockedByBoundary         // move ignored as it would take the rover off the plateau
+	OutcomeBlockedByRover            // move ignored as another rover occupies the target cell
+	OutcomeBlockedByObstacle         // move ignored as an obstacle covers the target cell
+	OutcomeLost                      // rover drove off the plateau
+	OutcomePushed                    // rover moved, pushing the rover in its way one cell ahead
+	OutcomeSwapped                   // rover moved, swapping cells with the rover in its way
+)
+
+// TraceStep records a single command applied to a rover
+type TraceStep struct {
+	Step    int // 1-based index of the command within the rover's command string
+	Command Command
+	Before  Position
+	After   Position
+	Outcome Outcome
+}
+
+// WithTrace makes MissionControl record every command applied to each rover in RoverResult.Trace
+func WithTrace() Option {
+	return func(mc *MissionControl) {
+		mc.trace = true
+	}
+}
+
+// record appends a step to the rover's trace when tracing is enabled
+func (mc *MissionControl) record(result *RoverResult, step int, cmd Command, before, after Position, outcome Outcome) {
+	if !mc.trace {
+		return
+	}
+
+	result.Trace = append(result.Trace, TraceStep{
+		Step:    step,
+		Command: cmd,
+		Before:  before,
+		After:   after,
+		Outcome: outcome,
+	})
+}
+
+// blockedOutcome maps the reason a move was rejected to its Outcome
+func blockedOutcome(reason error) Outcome {
+	switch reason {
+	case ErrRoverCollision:
+		return OutcomeBlockedByRover
+	case ErrObstacleCollision:
+		return OutcomeBlockedByObstacle
+	default:
+		return OutcomeBlockedByBoundary
+	}
+}
+
+// String implements the Stringer interface, e.g. "step 3 M: 1 2 N -> 1 3 N moved"
+func (ts TraceStep) String() string {
+	return fmt.Sprintf("step %d %s: %s -> %s %s", ts.Step, ts.Command, ts.Before.String(), ts.After.String(), ts.Outcome)
+}
+
+// implement stringer interface so commands print as their letter
+func (c Command) String() string {
+	return string(c)
+}
+
+// implement stringer interface so outcomes print in traces
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeMoved:
+		return "moved"
+	case OutcomeTurned:
+		return "turned"
+	case OutcomeBlockedByBoundary:
+		return "blocked-by-boundary"
+	case OutcomeBlockedByRover:
+		return "blocked-by-rover"
+	case OutcomeBlockedByObstacle:
+		return "blocked-by-obstacle"
+	case OutcomeLost:
+		return "lost"
+	case OutcomePushed:
+		return "pushed"
+	case OutcomeSwapped:
+		return "swapped"
+	default:
+		return "?"
+	}
+}
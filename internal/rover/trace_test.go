@@ -0,0 +1,115 @@
+package rover
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pos(x, y int, d Direction) Position {
+	return Position{coordinates: Coordinates{x: x, y: y}, direction: d}
+}
+
+func TestMissionControlSimulate_Trace(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		opts      []Option
+		obstacles []Obstacle
+		input     func(p *Plateau) []RoverInstruction
+		wantTrace []TraceStep // trace of the last rover
+	}{
+		"ok - tracing disabled by default": {
+			input: func(p *Plateau) []RoverInstruction {
+				return []RoverInstruction{*createTestSingleRoverInstruction(t, p, 1, 1, N, "LMR")}
+			},
+			wantTrace: nil,
+		},
+		"ok - moved, turned, blocked by boundary and rover": {
+			opts: []Option{WithTrace()},
+			input: func(p *Plateau) []RoverInstruction {
+				return []RoverInstruction{
+					*createTestSingleRoverInstruction(t, p, 0, 1, N, ""),
+					*createTestSingleRoverInstruction(t, p, 0, 0, W, "MRMX"),
+				}
+			},
+			wantTrace: []TraceStep{
+				{Step: 1, Command: CmdMove, Before: pos(0, 0, W), After: pos(0, 0, W), Outcome: OutcomeBlockedByBoundary},
+				{Step: 2, Command: CmdRight, Before: pos(0, 0, W), After: pos(0, 0, N), Outcome: OutcomeTurned},
+				{Step: 3, Command: CmdMove, Before: pos(0, 0, N), After: pos(0, 0, N), Outcome: OutcomeBlockedByRover},
+			},
+		},
+		"ok - blocked by obstacle": {
+			opts:      []Option{WithTrace()},
+			obstacles: []Obstacle{NewObstacle(Rock, NewCoordinates(1, 2))},
+			input: func(p *Plateau) []RoverInstruction {
+				return []RoverInstruction{*createTestSingleRoverInstruction(t, p, 1, 1, N, "MLM")}
+			},
+			wantTrace: []TraceStep{
+				{Step: 1, Command: CmdMove, Before: pos(1, 1, N), After: pos(1, 1, N), Outcome: OutcomeBlockedByObstacle},
+				{Step: 2, Command: CmdLeft, Before: pos(1, 1, N), After: pos(1, 1, W), Outcome: OutcomeTurned},
+				{Step: 3, Command: CmdMove, Before: pos(1, 1, W), After: pos(0, 1, W), Outcome: OutcomeMoved},
+			},
+		},
+		"ok - lost rover stops the trace": {
+			opts: []Option{WithTrace(), WithBoundaryPolicy(LoseOffEdge{})},
+			input: func(p *Plateau) []RoverInstruction {
+				return []RoverInstruction{*createTestSingleRoverInstruction(t, p, 5, 5, E, "MMR")}
+			},
+			wantTrace: []TraceStep{
+				{Step: 1, Command: CmdMove, Before: pos(5, 5, E), After: pos(5, 5, E), Outcome: OutcomeLost},
+			},
+		},
+		"ok - pushed and swapped": {
+			opts: []Option{WithTrace(), WithCollisionPolicy(PushOnCollision{})},
+			input: func(p *Plateau) []RoverInstruction {
+				return []RoverInstruction{
+					*createTestSingleRoverInstruction(t, p, 1, 0, N, ""),
+					*createTestSingleRoverInstruction(t, p, 0, 0, E, "M"),
+				}
+			},
+			wantTrace: []TraceStep{
+				{Step: 1, Command: CmdMove, Before: pos(0, 0, E), After: pos(1, 0, E), Outcome: OutcomePushed},
+			},
+		},
+		"ok - swapped": {
+			opts: []Option{WithTrace(), WithCollisionPolicy(SwapOnCollision{})},
+			input: func(p *Plateau) []RoverInstruction {
+				return []RoverInstruction{
+					*createTestSingleRoverInstruction(t, p, 1, 0, N, ""),
+					*createTestSingleRoverInstruction(t, p, 0, 0, E, "M"),
+				}
+			},
+			wantTrace: []TraceStep{
+				{Step: 1, Command: CmdMove, Before: pos(0, 0, E), After: pos(1, 0, E), Outcome: OutcomeSwapped},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			plateau := createTestPlateau(t, 5, 5)
+			for _, o := range tc.obstacles {
+				require.NoError(t, plateau.AddObstacle(o))
+			}
+
+			mc, err := NewMissionControl(plateau, tc.opts...)
+			require.NoError(t, err)
+
+			result, err := mc.Simulate(context.Background(), &MissionControlInput{Instructions: tc.input(plateau)})
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.wantTrace, result.Rovers[len(result.Rovers)-1].Trace)
+		})
+	}
+}
+
+func TestTraceStepString(t *testing.T) {
+	t.Parallel()
+
+	step := TraceStep{Step: 3, Command: CmdMove, Before: pos(1, 2, N), After: pos(1, 3, N), Outcome: OutcomeMoved}
+
+	assert.Equal(t, "step 3 M: 1 2 N -> 1 3 N moved", step.String())
+}
@@ -0,0 +1,9 @@
+package runner
+
+import "errors"
+
+var (
+	ErrRunnerCancelled = errors.New("mission not run, the run was cancelled")
+	ErrRunnerTimeout   = errors.New("mission ran out of time")
+	ErrRunnerPanic     = errors.New("mission panicked")
+)
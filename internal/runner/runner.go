@@ -0,0 +1,115 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mars/internal/rover"
+	"sync"
+	"time"
+)
+
+// Job runs a single mission. It should give up and return the context error once ctx is done
+type Job func(ctx context.Context) (*rover.MissionResult, error)
+
+// Result is the outcome of a Job: the mission result when it ran, the error that stopped it otherwise
+type Result struct {
+	Mission *rover.MissionResult
+	Err     error
+}
+
+// deadlineSlack is the time a job may take to return once its deadline passed before it is taken to have ignored its context
+const deadlineSlack = 10 * time.Millisecond
+
+// Runner fans independent missions out across a bounded pool of goroutines
+type Runner struct {
+	workers int
+	timeout time.Duration // time limit of every mission, zero means none
+}
+
+// Option configures a Runner
+type Option func(*Runner)
+
+// WithTimeout limits the time every mission may take. A mission that gives up or keeps running once its time is up is reported as timed out, one done right as it is up keeps its result
+func WithTimeout(d time.Duration) Option {
+	return func(r *Runner) {
+		r.timeout = d
+	}
+}
+
+// New returns a Runner running up to workers missions at the same time, at least one
+func New(workers int, opts ...Option) *Runner {
+	r := &Runner{
+		workers: max(workers, 1),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Run runs every job and returns their results in the order of the jobs, whatever order they finished in.
+// Once ctx is done the jobs not yet started are not run and report ErrRunnerCancelled, a failing or panicking job does not stop the others
+func (r *Runner) Run(ctx context.Context, jobs []Job) []Result {
+	results := make([]Result, len(jobs))
+
+	next := make(chan int)
+	go func() {
+		defer close(next)
+		for i := range jobs {
+			next <- i
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for range min(r.workers, len(jobs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i] = r.run(ctx, jobs[i])
+			}
+		}()
+	}
+
+	wg.Wait()
+	return results
+}
+
+// run runs a single job within its time limit
+func (r *Runner) run(ctx context.Context, job Job) (res Result) {
+	if err := ctx.Err(); err != nil {
+		return Result{Err: fmt.Errorf("%w: %w", ErrRunnerCancelled, err)}
+	}
+
+	jobCtx := ctx
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		jobCtx, cancel = context.WithTimeoutCause(ctx, r.timeout, ErrRunnerTimeout)
+		defer cancel()
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			res = Result{Err: fmt.Errorf("%w: %v", ErrRunnerPanic, p)}
+		}
+	}()
+
+	mission, err := job(jobCtx)
+
+	switch {
+	// only the time limit of the job is a timeout, the deadline of the whole run cancels it
+	case errors.Is(context.Cause(jobCtx), ErrRunnerTimeout) && (errors.Is(err, context.DeadlineExceeded) || ignoredDeadline(jobCtx)):
+		return Result{Err: fmt.Errorf("%w: %w", ErrRunnerTimeout, context.DeadlineExceeded)}
+	case err != nil && ctx.Err() != nil:
+		return Result{Err: fmt.Errorf("%w: %w", ErrRunnerCancelled, err)}
+	}
+	return Result{Mission: mission, Err: err}
+}
+
+// ignoredDeadline reports whether a job returned so long after the deadline of its context that it cannot have been looking at it. A job done right as its time is up keeps its result
+func ignoredDeadline(ctx context.Context) bool {
+	deadline, ok := ctx.Deadline()
+	return ok && time.Since(deadline) > deadlineSlack
+}
@@ -0,0 +1,161 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"mars/internal/rover"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errMission = errors.New("mission failed")
+
+// numbered returns a job whose result holds a single rover with the given id, after sleeping for d
+func numbered(id int, d time.Duration) Job {
+	return func(ctx context.Context) (*rover.MissionResult, error) {
+		select {
+		case <-time.After(d):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		return &rover.MissionResult{Rovers: []rover.RoverResult{{ID: id}}}, nil
+	}
+}
+
+func TestRunner_Run(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		workers int
+		opts    []Option
+		jobs    []Job
+		wantIDs []int   // rover id of every result, 0 for a failed job
+		wantErr []error // error of every result, nil for a job that ran
+	}{
+		"ok - results in job order whatever order they finish in": {
+			workers: 3,
+			jobs:    []Job{numbered(1, 30*time.Millisecond), numbered(2, 10*time.Millisecond), numbered(3, 0)},
+			wantIDs: []int{1, 2, 3},
+			wantErr: []error{nil, nil, nil},
+		},
+		"ok - more jobs than workers": {
+			workers: 2,
+			jobs:    []Job{numbered(1, 0), numbered(2, 0), numbered(3, 0), numbered(4, 0), numbered(5, 0)},
+			wantIDs: []int{1, 2, 3, 4, 5},
+			wantErr: []error{nil, nil, nil, nil, nil},
+		},
+		"ok - no worker still runs the jobs": {
+			workers: 0,
+			jobs:    []Job{numbered(1, 0)},
+			wantIDs: []int{1},
+			wantErr: []error{nil},
+		},
+		"err - failing and panicking jobs do not stop the others": {
+			workers: 1,
+			jobs: []Job{
+				func(ctx context.Context) (*rover.MissionResult, error) { return nil, errMission },
+				func(ctx context.Context) (*rover.MissionResult, error) { panic("boom") },
+				numbered(3, 0),
+			},
+			wantIDs: []int{0, 0, 3},
+			wantErr: []error{errMission, ErrRunnerPanic, nil},
+		},
+		"err - mission out of time": {
+			workers: 2,
+			opts:    []Option{WithTimeout(20 * time.Millisecond)},
+			jobs: []Job{
+				numbered(1, time.Second),
+				numbered(2, 0),
+				func(ctx context.Context) (*rover.MissionResult, error) {
+					// ignores its context
+					time.Sleep(40 * time.Millisecond)
+					return &rover.MissionResult{}, nil
+				},
+			},
+			wantIDs: []int{0, 2, 0},
+			wantErr: []error{ErrRunnerTimeout, nil, ErrRunnerTimeout},
+		},
+		"ok - mission done right as its time is up": {
+			workers: 1,
+			opts:    []Option{WithTimeout(20 * time.Millisecond)},
+			jobs: []Job{
+				func(ctx context.Context) (*rover.MissionResult, error) {
+					<-ctx.Done()
+					return &rover.MissionResult{Rovers: []rover.RoverResult{{ID: 1}}}, nil
+				},
+			},
+			wantIDs: []int{1},
+			wantErr: []error{nil},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			results := New(tc.workers, tc.opts...).Run(context.Background(), tc.jobs)
+			require.Len(t, results, len(tc.jobs))
+
+			for i, res := range results {
+				if tc.wantErr[i] != nil {
+					assert.ErrorIs(t, res.Err, tc.wantErr[i], "job %d", i)
+					assert.Nil(t, res.Mission, "job %d", i)
+					continue
+				}
+
+				require.NoError(t, res.Err, "job %d", i)
+				assert.Equal(t, tc.wantIDs[i], res.Mission.Rovers[0].ID, "job %d", i)
+			}
+		})
+	}
+}
+
+func TestRunner_RunCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var started atomic.Int32
+	jobs := make([]Job, 10)
+	for i := range jobs {
+		jobs[i] = func(ctx context.Context) (*rover.MissionResult, error) {
+			// the first job cancels the run, the others must not start
+			if started.Add(1) == 1 {
+				cancel()
+			}
+			return &rover.MissionResult{}, nil
+		}
+	}
+
+	results := New(1).Run(ctx, jobs)
+
+	require.Len(t, results, len(jobs))
+	assert.Equal(t, int32(1), started.Load())
+	assert.NoError(t, results[0].Err)
+	for _, res := range results[1:] {
+		assert.ErrorIs(t, res.Err, ErrRunnerCancelled)
+		assert.ErrorIs(t, res.Err, context.Canceled)
+	}
+}
+
+func TestRunner_RunDeadline(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// the deadline of the run is not the time limit of its missions
+	results := New(1, WithTimeout(time.Second)).Run(ctx, []Job{numbered(1, time.Second), numbered(2, 0)})
+
+	require.Len(t, results, 2)
+	for _, res := range results {
+		assert.ErrorIs(t, res.Err, ErrRunnerCancelled)
+		assert.ErrorIs(t, res.Err, context.DeadlineExceeded)
+		assert.NotErrorIs(t, res.Err, ErrRunnerTimeout)
+	}
+}
@@ -1,20 +1,42 @@
 package webapi
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"mars/internal/app"
 	"mars/internal/config"
+	"mars/internal/metrics"
+	"mars/internal/parser"
+	"mars/internal/render"
 	"mars/internal/rover"
+	"net"
 	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
 )
 
 // Server is a struct that holds the dependencies for the web api
 type Server struct {
-	cfg     *config.Config
-	parser  app.Parser
-	factory rover.MissionControlFactory
+	cfg        *config.Config
+	parser     app.Parser
+	jsonParser JSONParser
+	factory    rover.MissionControlFactory
+	dryRun     rover.MissionControlFactory // creates the mission controls of validations, their rover events are not counted in the metrics
+	sessions   *sessionStore
+	metrics    *metrics.Metrics
+	ready      atomic.Bool // true while the server is accepting requests, false before it starts and once it is shutting down
+}
+
+// JSONParser parses JSON mission documents as well as the plateau, rover and commands documents used by mission sessions
+type JSONParser interface {
+	app.Parser
+	ParsePlateau(input string, cfg *config.Config) (*rover.Plateau, error)
+	ParseRover(input string, plateau *rover.Plateau, cfg *config.Config) (rover.RoverInstruction, error)
+	ParseCommands(input string, cfg *config.Config) (string, error)
 }
 
 const maxRequestSize = 1024 * 1024 // 1MB
@@ -22,60 +44,338 @@ const maxRequestSize = 1024 * 1024 // 1MB
 var (
 	ErrServerStart       = errors.New("error starting http server")
 	ErrMissionProcessing = errors.New("mission processing failed")
+	ErrInvalidQuery      = errors.New("invalid query parameter")
+	ErrServerShutdown    = errors.New("error shutting down http server")
 )
 
-// NewServer is the constructor for a new web api server
-func NewServer(cfg *config.Config, p app.Parser, mcf rover.MissionControlFactory) *Server {
+// NewServer is the constructor for a new web api server. The text parser handles plain-text bodies and the JSON parser handles application/json bodies
+func NewServer(cfg *config.Config, p app.Parser, jp JSONParser, mcf rover.MissionControlFactory) *Server {
+	m := metrics.New()
+
 	return &Server{
-		cfg:     cfg,
-		parser:  p,
-		factory: mcf,
+		cfg:        cfg,
+		parser:     p,
+		jsonParser: jp,
+		// every mission control reports its rover events to the metrics
+		factory:  sinkFactory{MissionControlFactory: mcf, sink: m},
+		dryRun:   mcf,
+		sessions: newSessionStore(cfg.Timeouts.Session),
+		metrics:  m,
 	}
 }
 
-// Handler creates and returns a router
+// Handler creates and returns a router, recording the status code and latency of every request in the metrics
 func (s *Server) Handler() http.Handler {
 	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", s.handleHealth)
+	mux.HandleFunc("GET /readyz", s.handleReady)
+	mux.Handle("GET /metrics", s.metrics.Handler())
+
 	mux.HandleFunc("POST /mcontrol", s.handleMission) // register POST endpoint only
+	mux.HandleFunc("POST /mcontrol/stream", s.handleMissionStream)
+	mux.HandleFunc("POST /validate", s.handleValidate)
 
-	return mux
+	// mission sessions keep their rovers between requests
+	mux.HandleFunc("POST /missions", s.handleCreateSession)
+	mux.HandleFunc("GET /missions/{id}", s.handleGetSession)
+	mux.HandleFunc("DELETE /missions/{id}", s.handleDeleteSession)
+	mux.HandleFunc("GET /missions/{id}/rovers", s.handleListRovers)
+	mux.HandleFunc("POST /missions/{id}/rovers", s.handleDeployRover)
+	mux.HandleFunc("POST /missions/{id}/rovers/{rover}/commands", s.handleDriveRover)
+
+	return s.metrics.Middleware(mux)
 }
 
-// Starts begins listening for requests
-func (s *Server) Start() error {
+// Start listens on the configured address and serves requests until ctx is done, then stops accepting new requests and waits for in-flight ones to complete, up to the shutdown timeout
+func (s *Server) Start(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.cfg.SrvAddr)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrServerStart, err)
+	}
 
-	log.Printf("starting Mars rover HTTP server on %s\n", s.cfg.SrvAddr)
+	return s.Serve(ctx, ln)
+}
+
+// Serve serves requests on the listener until ctx is done, then shuts down gracefully. The listener is closed on return
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	srv := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: s.cfg.Timeouts.ReadHeader,
+		ReadTimeout:       s.cfg.Timeouts.Read,
+		WriteTimeout:      s.cfg.Timeouts.Write,
+		IdleTimeout:       s.cfg.Timeouts.Idle,
+	}
 
-	if err := http.ListenAndServe(s.cfg.SrvAddr, s.Handler()); err != nil {
+	log.Printf("starting Mars rover HTTP server on %s\n", ln.Addr())
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Serve(ln)
+	}()
+	s.ready.Store(true)
+
+	select {
+	case err := <-errCh:
+		s.ready.Store(false)
 		return fmt.Errorf("%w: %v", ErrServerStart, err)
+
+	case <-ctx.Done():
+	}
+
+	// report not ready straight away so load balancers stop sending requests while in-flight missions drain
+	s.ready.Store(false)
+	log.Printf("shutting down Mars rover HTTP server, waiting for in-flight requests")
+
+	shutdownCtx := context.Background()
+	if s.cfg.Timeouts.Shutdown > 0 {
+		var cancel context.CancelFunc
+		shutdownCtx, cancel = context.WithTimeout(shutdownCtx, s.cfg.Timeouts.Shutdown)
+		defer cancel()
+	}
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("%w: %v", ErrServerShutdown, err)
 	}
 
 	return nil
 }
 
+// handleHealth reports the process is alive
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, "ok")
+}
+
+// handleReady reports whether the server is accepting requests, it is not once shutting down
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	if !s.ready.Load() {
+		http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, "ready")
+}
+
 func (s *Server) handleMission(w http.ResponseWriter, r *http.Request) {
 
 	// limit the size of what we accept
 	r.Body = http.MaxBytesReader(w, r.Body, maxRequestSize)
 	defer r.Body.Close()
 
-	application := app.NewApp(s.parser, s.factory, r.Body, w, s.cfg)
+	// pick the parser matching the body format
+	p := s.parser
+	if isJSON(r.Header.Get("Content-Type")) {
+		p = s.jsonParser
+	}
+
+	jsonResponse := wantsJSON(r)
 
-	if err := application.Run(); err != nil {
+	cfg, err := s.missionConfig(r)
+	if err != nil {
+		message := fmt.Sprintf("Bad request: %v", err)
+		if jsonResponse {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: message})
+			return
+		}
+		http.Error(w, message, http.StatusBadRequest)
+		return
+	}
+
+	application := app.NewApp(p, s.factory, r.Body, w, cfg)
+
+	result, err := application.Execute(r.Context())
+	if err != nil {
 		log.Printf("ERROR: mission failed: %v", err)
 
-		var maxBytesError *http.MaxBytesError
+		status, message := errorStatus(err)
+		if jsonResponse {
+			writeJSON(w, status, newErrorResponse(message, err))
+			return
+		}
+		http.Error(w, message, status)
+		return
+	}
+
+	s.metrics.MissionExecuted()
+
+	renderer, err := app.Renderer(cfg)
+	if err != nil {
+		log.Printf("ERROR: choosing renderer: %v", err)
+		http.Error(w, "An internal server error occurred.", http.StatusInternalServerError)
+		return
+	}
 
-		switch {
-		case errors.As(err, &maxBytesError):
-			http.Error(w, "Request body is too large.", http.StatusRequestEntityTooLarge)
+	body := &responseBody{w: w, contentType: renderer.ContentType()}
+	if err := renderer.Render(body, result); err != nil {
+		log.Printf("ERROR: rendering mission result: %v", err)
 
-		case errors.Is(err, app.ErrAppParsing):
-			http.Error(w, fmt.Sprintf("Bad request: %v", err), http.StatusBadRequest)
+		// a result too large to draw fails before anything is written, an error once the reply has started can only be logged
+		if body.started {
+			return
+		}
 
-		default:
-			http.Error(w, "An internal server error occurred.", http.StatusInternalServerError)
+		status, message := errorStatus(err)
+		if jsonResponse {
+			writeJSON(w, status, newErrorResponse(message, err))
+			return
 		}
+		http.Error(w, message, status)
 		return
 	}
+
+	// an empty result still gets its content type
+	body.start()
+}
+
+// responseBody writes a rendered result to the client, only setting its content type with the first bytes written so a renderer failing before that still leaves the reply free for an error
+type responseBody struct {
+	w           http.ResponseWriter
+	contentType string
+	started     bool
+}
+
+func (b *responseBody) Write(p []byte) (int, error) {
+	b.start()
+	return b.w.Write(p)
+}
+
+// start sets the content type of the reply, once
+func (b *responseBody) start() {
+	if !b.started {
+		b.w.Header().Set("Content-Type", b.contentType)
+		b.started = true
+	}
+}
+
+// handleValidate dry-runs a mission: the body is parsed and simulated like a /mcontrol one but the reply lists every problem found, with its severity, instead of the result.
+// A mission with problems is still a 200 reply, its "valid" field telling whether it can be run. Every parse problem is reported unless "allErrors" says otherwise
+func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
+
+	// limit the size of what we accept
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestSize)
+	defer r.Body.Close()
+
+	// pick the parser matching the body format
+	p := s.parser
+	if isJSON(r.Header.Get("Content-Type")) {
+		p = s.jsonParser
+	}
+
+	cfg, err := s.missionConfig(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("Bad request: %v", err)})
+		return
+	}
+
+	if !r.URL.Query().Has("allErrors") {
+		cfg.AllErrors = true
+	}
+
+	report, err := app.NewApp(p, s.dryRun, r.Body, io.Discard, cfg).Validate(r.Context())
+	if err != nil {
+		log.Printf("ERROR: mission validation failed: %v", err)
+
+		status, message := errorStatus(err)
+		writeJSON(w, status, newErrorResponse(message, err))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newValidateResponse(report))
+}
+
+// missionConfig returns a copy of the server config with the settings overridden by the "boundary", "collision", "exec", "format", "cellSize", "frameDelay", "allErrors", "strict" and "trace" query parameters, if given.
+// Without a "format" query parameter the output format is negotiated: JSON when the client wants JSON, text otherwise
+func (s *Server) missionConfig(r *http.Request) (*config.Config, error) {
+	cfg := *s.cfg
+	query := r.URL.Query()
+
+	cfg.OutputFormat = render.FormatText
+	if wantsJSON(r) {
+		cfg.OutputFormat = render.FormatJSON
+	}
+
+	if format := query.Get("format"); format != "" {
+		cfg.OutputFormat = format
+	}
+
+	if boundary := query.Get("boundary"); boundary != "" {
+		cfg.BoundaryPolicy = boundary
+	}
+
+	if collision := query.Get("collision"); collision != "" {
+		cfg.CollisionPolicy = collision
+	}
+
+	if exec := query.Get("exec"); exec != "" {
+		cfg.ExecMode = exec
+	}
+
+	if cellSize := query.Get("cellSize"); cellSize != "" {
+		size, err := strconv.Atoi(cellSize)
+		if err != nil {
+			return nil, fmt.Errorf("%w: cellSize %q", ErrInvalidQuery, cellSize)
+		}
+		cfg.Image.CellSize = size
+	}
+
+	if frameDelay := query.Get("frameDelay"); frameDelay != "" {
+		delay, err := time.ParseDuration(frameDelay)
+		if err != nil {
+			return nil, fmt.Errorf("%w: frameDelay %q", ErrInvalidQuery, frameDelay)
+		}
+		cfg.Image.FrameDelay = delay
+	}
+
+	flags := map[string]*bool{
+		"allErrors": &cfg.AllErrors,
+		"strict":    &cfg.Strict,
+		"trace":     &cfg.Trace,
+	}
+
+	for name, flag := range flags {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s %q", ErrInvalidQuery, name, value)
+		}
+		*flag = enabled
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// errorStatus maps a mission error to the HTTP status code and message returned to the client
+func errorStatus(err error) (int, string) {
+	var maxBytesError *http.MaxBytesError
+
+	switch {
+	case errors.As(err, &maxBytesError):
+		return http.StatusRequestEntityTooLarge, "Request body is too large."
+
+	case errors.Is(err, parser.ErrParseLimit):
+		return http.StatusUnprocessableEntity, fmt.Sprintf("Mission too large: %v", err)
+
+	case errors.Is(err, app.ErrAppParsing):
+		return http.StatusBadRequest, fmt.Sprintf("Bad request: %v", err)
+
+	case errors.Is(err, rover.ErrMissionCancelled):
+		return http.StatusServiceUnavailable, fmt.Sprintf("Mission cancelled: %v", err)
+
+	case errors.Is(err, app.ErrAppExecMission):
+		return http.StatusUnprocessableEntity, fmt.Sprintf("Mission failed: %v", err)
+
+	case errors.Is(err, render.ErrGridTooLarge), errors.Is(err, render.ErrSVGTooLarge), errors.Is(err, render.ErrImageTooLarge), errors.Is(err, render.ErrAnimationTooLong):
+		return http.StatusUnprocessableEntity, fmt.Sprintf("Mission too large to draw: %v", err)
+
+	default:
+		return http.StatusInternalServerError, "An internal server error occurred."
+	}
 }
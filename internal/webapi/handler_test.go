@@ -1,17 +1,26 @@
 package webapi
 
 import (
+	"context"
 	"errors"
+	"image"
+	"image/gif"
+	"image/png"
+	"io"
 	"mars/internal/app"
 	"mars/internal/config"
+	"mars/internal/parser"
 	"mars/internal/rover"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 type MockParser struct {
@@ -31,7 +40,7 @@ type MockMCFactory struct {
 	mock.Mock
 }
 
-func (m *MockMCFactory) Create(plateau *rover.Plateau) (*rover.MissionControl, error) {
+func (m *MockMCFactory) Create(plateau *rover.Plateau, opts ...rover.Option) (*rover.MissionControl, error) {
 	args := m.Called(plateau)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -110,7 +119,7 @@ func TestHandleMission(t *testing.T) {
 			tc.setupMocks(mockParser, mockMCFactory)
 
 			// new server instance
-			server := NewServer(config.Default(), mockParser, mockMCFactory)
+			server := NewServer(config.Default(), mockParser, parser.NewJSON(), mockMCFactory)
 
 			// fake request
 			req := httptest.NewRequest(tc.httpMethod, "/mcontrol", strings.NewReader(tc.requestBody))
@@ -128,6 +137,413 @@ func TestHandleMission(t *testing.T) {
 		})
 	}
 }
+func TestHandleMission_JSON(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		query           string
+		requestBody     string
+		contentType     string
+		accept          string
+		wantStatusCode  int
+		wantContentType string
+		wantBody        string
+	}{
+		"ok - json in, json out": {
+			requestBody:     `{"plateau": {"x": 5, "y": 5}, "rovers": [{"x": 1, "y": 2, "heading": "N", "commands": "LMLMLMLMM"}, {"x": 3, "y": 3, "heading": "E", "commands": "MMRMMRMRRM"}]}`,
+			contentType:     "application/json",
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "application/json",
+			wantBody:        `{"rovers":[{"id":1,"x":1,"y":3,"heading":"N","status":"operational","start":{"x":1,"y":2,"heading":"N"},"ignoredMoves":[]},{"id":2,"x":5,"y":1,"heading":"E","status":"operational","start":{"x":3,"y":3,"heading":"E"},"ignoredMoves":[]}]}` + "\n",
+		},
+		"ok - json in with charset, text out": {
+			requestBody:     `{"plateau": {"x": 5, "y": 5}, "rovers": [{"x": 1, "y": 2, "heading": "N", "commands": "LMLMLMLMM"}]}`,
+			contentType:     "application/json; charset=utf-8",
+			accept:          "text/plain",
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "text/plain",
+			wantBody:        "1 3 N\n",
+		},
+		"ok - text in, json out with ignored moves": {
+			requestBody:     "5 5\n5 5 N\nMR",
+			accept:          "application/json",
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "application/json",
+			wantBody:        `{"rovers":[{"id":1,"x":5,"y":5,"heading":"E","status":"operational","start":{"x":5,"y":5,"heading":"N"},"ignoredMoves":[{"step":1,"x":5,"y":6,"heading":"N","reason":"position must be more than 0 and within boundaries"}]}]}` + "\n",
+		},
+		"ok - collision policy from query": {
+			query:           "?collision=halt",
+			requestBody:     "5 5\n1 3 N\n\n1 1 N\nMMRM",
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "text/plain",
+			wantBody:        "1 3 N\n1 2 N HALTED\n",
+		},
+		"ok - boundary policy from query": {
+			query:           "?boundary=wrap",
+			requestBody:     "5 5\n5 5 E\nM",
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "text/plain",
+			wantBody:        "0 5 E\n",
+		},
+		"ok - json trace from query": {
+			query:           "?trace=true",
+			requestBody:     `{"plateau": {"x": 5, "y": 5}, "rovers": [{"x": 1, "y": 2, "heading": "N", "commands": "R"}]}`,
+			contentType:     "application/json",
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "application/json",
+			wantBody:        `{"rovers":[{"id":1,"x":1,"y":2,"heading":"E","status":"operational","start":{"x":1,"y":2,"heading":"N"},"ignoredMoves":[],"trace":[{"step":1,"command":"R","before":{"x":1,"y":2,"heading":"N"},"after":{"x":1,"y":2,"heading":"E"},"outcome":"turned"}]}]}` + "\n",
+		},
+		"ok - lockstep from query": {
+			query:           "?exec=lockstep",
+			requestBody:     "5 5\n0 0 E\nM\n1 0 E\nM",
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "text/plain",
+			wantBody:        "1 0 E\n2 0 E\n",
+		},
+		"ok - json ticks in lockstep": {
+			query:           "?exec=lockstep",
+			requestBody:     `{"plateau": {"x": 5, "y": 5}, "rovers": [{"x": 1, "y": 2, "heading": "N", "commands": "M"}]}`,
+			contentType:     "application/json",
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "application/json",
+			wantBody:        `{"rovers":[{"id":1,"x":1,"y":3,"heading":"N","status":"operational","start":{"x":1,"y":2,"heading":"N"},"ignoredMoves":[]}],"ticks":[{"tick":0,"rovers":[{"id":1,"x":1,"y":2,"heading":"N","status":"operational"}]},{"tick":1,"rovers":[{"id":1,"x":1,"y":3,"heading":"N","status":"operational"}]}]}` + "\n",
+		},
+		"ok - named rovers": {
+			requestBody:     "5 5\nalpha: 1 2 N\nM",
+			accept:          "application/json",
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "application/json",
+			wantBody:        `{"rovers":[{"id":1,"name":"alpha","x":1,"y":3,"heading":"N","status":"operational","start":{"x":1,"y":2,"heading":"N"},"ignoredMoves":[]}]}` + "\n",
+		},
+		"ok - csv from query": {
+			query:           "?format=csv",
+			requestBody:     "5 5\nalpha: 1 2 N\nM",
+			accept:          "application/json",
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "text/csv",
+			wantBody:        "id,name,start_x,start_y,start_heading,x,y,heading,status,blocked_moves,error\n1,alpha,1,2,N,1,3,N,operational,0,\n",
+		},
+		"ok - table from query": {
+			query:           "?format=table",
+			requestBody:     "5 5\n1 2 N\nM",
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "text/plain",
+			wantBody:        "ID  NAME  START  FINAL  STATUS       BLOCKED\n1   -     1 2 N  1 3 N  operational  0\n",
+		},
+		"ok - grid from query": {
+			query:           "?format=grid",
+			requestBody:     "2 2\nROCK 2 2\n1 0 N\nMM",
+			accept:          "application/json",
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "text/plain",
+			wantBody:        "2 .  ^1 #\n1 .  o  .\n0 .  o  .\n  0  1  2\n1: 1 2 N\n",
+		},
+		"ok - grid steps from query": {
+			query:           "?format=grid-steps",
+			requestBody:     "2 2\n0 0 N\nR",
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "text/plain",
+			wantBody:        "rover 1 deployed at 0 0 N\n2 .  .  .\n1 .  .  .\n0 ^1 .  .\n  0  1  2\n\nrover 1 step 1 R: 0 0 N -> 0 0 E turned\n2 .  .  .\n1 .  .  .\n0 >1 .  .\n  0  1  2\n\n1: 0 0 E\n",
+		},
+		"err - grid steps too large": {
+			query:           "?format=grid-steps",
+			requestBody:     "300 300\n0 0 N\n" + strings.Repeat("M", 300),
+			wantStatusCode:  http.StatusUnprocessableEntity,
+			wantContentType: "text/plain",
+			wantBody:        "Mission too large to draw: mission too large to draw as a grid: 301 drawings of 301 x 301 cells\n",
+		},
+		"err - unknown format in query": {
+			query:           "?format=yaml",
+			requestBody:     "5 5\n1 2 N\nM",
+			wantStatusCode:  http.StatusBadRequest,
+			wantContentType: "text/plain",
+			wantBody:        "Bad request: output format must be one of text, json, csv, table, grid, grid-steps, svg, png, gif: (got \"yaml\")\n",
+		},
+		"err - all parse diagnostics as json": {
+			query:           "?allErrors=true",
+			requestBody:     "5 5\n1 2 Q\nMM\n3 3 N\nMZ",
+			accept:          "application/json",
+			wantStatusCode:  http.StatusBadRequest,
+			wantContentType: "application/json",
+			wantBody:        `{"error":"Bad request: error parsing input: 2:5: invalid direction given, must be N, E, S, W: given Q\n5:2: invalid command character given, must be L, R, M: given 'Z'","diagnostics":[{"line":2,"column":5,"text":"Q","message":"invalid direction given, must be N, E, S, W: given Q","hint":"headings are N, E, S and W"},{"line":5,"column":2,"text":"Z","message":"invalid command character given, must be L, R, M: given 'Z'","hint":"commands are L (turn left), R (turn right) and M (move forward)"}]}` + "\n",
+		},
+		"err - parse diagnostic as text": {
+			requestBody:     "5 5\n1 2 N\nMZ",
+			wantStatusCode:  http.StatusBadRequest,
+			wantContentType: "text/plain",
+			wantBody:        "Bad request: error parsing input: 3:2: invalid command character given, must be L, R, M: given 'Z'\n",
+		},
+		"ok - comments ignored unless strict": {
+			requestBody:     "5 5 # plateau\n1 2 N\nM # forward",
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "text/plain",
+			wantBody:        "1 3 N\n",
+		},
+		"err - strict from query": {
+			query:           "?strict=true",
+			requestBody:     "5 5\n1 2 N\nM # forward",
+			wantStatusCode:  http.StatusBadRequest,
+			wantContentType: "text/plain",
+			wantBody:        "Bad request: error parsing input: 3:2: invalid command character given, must be L, R, M: given ' '\n",
+		},
+		"err - invalid trace in query": {
+			query:           "?trace=maybe",
+			requestBody:     "5 5\n1 2 N\nM",
+			wantStatusCode:  http.StatusBadRequest,
+			wantContentType: "text/plain",
+			wantBody:        "Bad request: invalid query parameter: trace \"maybe\"\n",
+		},
+		"err - unknown policy in query": {
+			query:           "?collision=bounce",
+			requestBody:     "5 5\n1 2 N\nM",
+			accept:          "application/json",
+			wantStatusCode:  http.StatusBadRequest,
+			wantContentType: "application/json",
+			wantBody:        `{"error":"Bad request: collision policy must be one of skip, halt, abort, push, swap: (got \"bounce\")"}` + "\n",
+		},
+		"err - mission aborted by collision policy": {
+			query:           "?collision=abort",
+			requestBody:     "5 5\n1 3 N\n\n1 1 N\nMM",
+			wantStatusCode:  http.StatusUnprocessableEntity,
+			wantContentType: "text/plain",
+			wantBody:        "Mission failed: error executing mission: rover error executing instruction 2: rover 2 blocked at step 2 moving to (1 3 N): path is blocked by another rover\n",
+		},
+		"err - plateau over the size limit": {
+			requestBody:     "20000 5\n1 2 N\nM",
+			wantStatusCode:  http.StatusUnprocessableEntity,
+			wantContentType: "text/plain",
+			wantBody:        "Mission too large: error parsing input: 1:1: mission exceeds a resource limit: plateau is too large: 20000 wide, at most 10000\n",
+		},
+		"err - json plateau over the size limit": {
+			requestBody:     `{"plateau": {"x": 5, "y": 20000}, "rovers": [{"x": 1, "y": 2, "heading": "N", "commands": "M"}]}`,
+			contentType:     "application/json",
+			wantStatusCode:  http.StatusUnprocessableEntity,
+			wantContentType: "application/json",
+			wantBody:        `{"error":"Mission too large: error parsing input: mission exceeds a resource limit: plateau is too large: 20000 high, at most 10000"}` + "\n",
+		},
+		"err - invalid json document": {
+			requestBody:     `{"plateau": {"x": 5, "y": 5}, "rovers": []}`,
+			contentType:     "application/json",
+			wantStatusCode:  http.StatusBadRequest,
+			wantContentType: "application/json",
+			wantBody:        `{"error":"Bad request: error parsing input: mission must declare at least one rover"}` + "\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := NewServer(config.Default(), parser.New(), parser.NewJSON(), rover.NewMissionControlFactory())
+
+			req := httptest.NewRequest(http.MethodPost, "/mcontrol"+tc.query, strings.NewReader(tc.requestBody))
+			if tc.contentType != "" {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			rcap := httptest.NewRecorder()
+
+			server.handleMission(rcap, req)
+
+			assert.Equal(t, tc.wantStatusCode, rcap.Code)
+			assert.Contains(t, rcap.Header().Get("Content-Type"), tc.wantContentType)
+			assert.Equal(t, tc.wantBody, rcap.Body.String())
+		})
+	}
+}
+
+func TestHandleMission_SVG(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(config.Default(), parser.New(), parser.NewJSON(), rover.NewMissionControlFactory())
+
+	req := httptest.NewRequest(http.MethodPost, "/mcontrol?format=svg", strings.NewReader("5 5\n1 2 N\nMM"))
+	rcap := httptest.NewRecorder()
+
+	server.handleMission(rcap, req)
+
+	assert.Equal(t, http.StatusOK, rcap.Code)
+	assert.Equal(t, "image/svg+xml", rcap.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(rcap.Body.String(), `<svg xmlns="http://www.w3.org/2000/svg"`))
+	assert.Contains(t, rcap.Body.String(), `points="92,172 92,132 92,92"`, "the path is drawn from the trace without ?trace=true")
+}
+
+func TestHandleMission_Image(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		query           string
+		requestBody     string
+		wantStatusCode  int
+		wantContentType string
+		wantSize        image.Point // size of the picture, or of the animation
+		wantFrames      int
+		wantDelay       int // delay of the first frame, in hundredths of a second
+		wantBody        string
+	}{
+		"ok - png": {
+			query:           "?format=png",
+			requestBody:     "5 5\n1 2 N\nMM",
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "image/png",
+			wantSize:        image.Pt(6*32+1, 6*32+1),
+		},
+		"ok - gif with cell size and frame delay": {
+			query:           "?format=gif&cellSize=10&frameDelay=120ms",
+			requestBody:     "5 5\n1 2 N\nMM",
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "image/gif",
+			wantSize:        image.Pt(61, 61),
+			wantFrames:      3,
+			wantDelay:       12,
+		},
+		"err - cell size not a number": {
+			query:           "?format=png&cellSize=big",
+			requestBody:     "5 5\n1 2 N\nMM",
+			wantStatusCode:  http.StatusBadRequest,
+			wantContentType: "text/plain",
+			wantBody:        "Bad request: invalid query parameter: cellSize \"big\"\n",
+		},
+		"err - frame delay out of range": {
+			query:           "?format=gif&frameDelay=1ms",
+			requestBody:     "5 5\n1 2 N\nMM",
+			wantStatusCode:  http.StatusBadRequest,
+			wantContentType: "text/plain",
+			wantBody:        "Bad request: image cell size must be between 4 and 128 pixels and frame delay between 10ms and 10s: (got 32 pixels and 1ms)\n",
+		},
+		"err - plateau too large to draw": {
+			query:           "?format=png&cellSize=128",
+			requestBody:     "100 100\n1 2 N\nMM",
+			wantStatusCode:  http.StatusUnprocessableEntity,
+			wantContentType: "text/plain",
+			wantBody:        "Mission too large to draw: plateau too large to draw at this cell size: 12929 x 12929 pixels\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := NewServer(config.Default(), parser.New(), parser.NewJSON(), rover.NewMissionControlFactory())
+
+			req := httptest.NewRequest(http.MethodPost, "/mcontrol"+tc.query, strings.NewReader(tc.requestBody))
+			rcap := httptest.NewRecorder()
+
+			server.handleMission(rcap, req)
+
+			assert.Equal(t, tc.wantStatusCode, rcap.Code)
+			assert.Contains(t, rcap.Header().Get("Content-Type"), tc.wantContentType)
+
+			switch tc.wantContentType {
+			case "image/png":
+				img, err := png.Decode(rcap.Body)
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantSize, img.Bounds().Size())
+			case "image/gif":
+				anim, err := gif.DecodeAll(rcap.Body)
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantSize, image.Pt(anim.Config.Width, anim.Config.Height))
+				assert.Len(t, anim.Image, tc.wantFrames)
+				assert.Equal(t, tc.wantDelay, anim.Delay[0])
+			default:
+				assert.Equal(t, tc.wantBody, rcap.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleValidate(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		query          string
+		requestBody    string
+		contentType    string
+		wantStatusCode int
+		wantBody       string
+	}{
+		"ok - nothing to report": {
+			requestBody:    "5 5\n1 2 N\nLMLMLMLMM",
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"valid":true,"errors":0,"warnings":0,"infos":0,"findings":[]}`,
+		},
+		"ok - warnings and infos keep the mission valid": {
+			requestBody:    "5 5\n5 5 N\nMLR",
+			wantStatusCode: http.StatusOK,
+			wantBody: `{"valid":true,"errors":0,"warnings":1,"infos":1,"findings":[` +
+				`{"severity":"warning","rover":"1","step":1,"message":"move blocked by the edge of the plateau, the rover stays at 5 5 N"},` +
+				`{"severity":"info","rover":"1","step":2,"message":"commands 2-3 \"LR\" leave the rover facing the way it was, they do nothing"}]}`,
+		},
+		"ok - every parse problem by default": {
+			requestBody:    "5 5\n1 2 Q\nMZ",
+			wantStatusCode: http.StatusOK,
+			wantBody: `{"valid":false,"errors":2,"warnings":0,"infos":0,"findings":[` +
+				`{"severity":"error","line":2,"column":5,"message":"invalid direction given, must be N, E, S, W: given Q","hint":"headings are N, E, S and W"},` +
+				`{"severity":"error","line":3,"column":2,"message":"invalid command character given, must be L, R, M: given 'Z'","hint":"commands are L (turn left), R (turn right) and M (move forward)"}]}`,
+		},
+		"ok - first parse problem only from query": {
+			query:          "?allErrors=false",
+			requestBody:    "5 5\n1 2 Q\nMZ",
+			wantStatusCode: http.StatusOK,
+			wantBody: `{"valid":false,"errors":1,"warnings":0,"infos":0,"findings":[` +
+				`{"severity":"error","line":2,"column":5,"message":"invalid direction given, must be N, E, S, W: given Q","hint":"headings are N, E, S and W"}]}`,
+		},
+		"ok - json mission with policies from query": {
+			query:          "?boundary=lost",
+			requestBody:    `{"plateau": {"x": 5, "y": 5}, "rovers": [{"x": 1, "y": 2, "heading": "N", "commands": "M"}, {"x": 0, "y": 0, "heading": "S", "commands": "M"}]}`,
+			contentType:    "application/json",
+			wantStatusCode: http.StatusOK,
+			wantBody: `{"valid":false,"errors":1,"warnings":0,"infos":0,"findings":[` +
+				`{"severity":"error","rover":"2","step":1,"message":"rover drives off the plateau from 0 0 S and is lost"}]}`,
+		},
+		"err - unknown policy in query": {
+			query:          "?collision=bounce",
+			requestBody:    "5 5\n1 2 N\nM",
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error":"Bad request: collision policy must be one of skip, halt, abort, push, swap: (got \"bounce\")"}`,
+		},
+		"err - req body too large": {
+			requestBody:    strings.Repeat("?", maxRequestSize+1),
+			wantStatusCode: http.StatusRequestEntityTooLarge,
+			wantBody:       `{"error":"Request body is too large."}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := NewServer(config.Default(), parser.New(), parser.NewJSON(), rover.NewMissionControlFactory())
+
+			req := httptest.NewRequest(http.MethodPost, "/validate"+tc.query, strings.NewReader(tc.requestBody))
+			if tc.contentType != "" {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+			rcap := httptest.NewRecorder()
+
+			server.Handler().ServeHTTP(rcap, req)
+
+			assert.Equal(t, tc.wantStatusCode, rcap.Code)
+			assert.Contains(t, rcap.Header().Get("Content-Type"), "application/json")
+			assert.JSONEq(t, tc.wantBody, rcap.Body.String())
+		})
+	}
+}
+
+func TestHandleMission_ClientGone(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(config.Default(), parser.New(), parser.NewJSON(), rover.NewMissionControlFactory())
+
+	// the client gave up before the mission ran
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req := httptest.NewRequest(http.MethodPost, "/mcontrol", strings.NewReader("5 5\n1 2 N\n"+strings.Repeat("L", 4096))).WithContext(ctx)
+	rcap := httptest.NewRecorder()
+
+	server.handleMission(rcap, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rcap.Code)
+	assert.Contains(t, rcap.Body.String(), "Mission cancelled")
+}
+
 func TestServer_RoutingOnly(t *testing.T) {
 	t.Parallel()
 	testCases := map[string]struct {
@@ -170,7 +586,7 @@ func TestServer_RoutingOnly(t *testing.T) {
 				mockMCFactory.On("Create", plateau).Return(mc, nil)
 			}
 
-			server := NewServer(config.Default(), mockParser, mockMCFactory)
+			server := NewServer(config.Default(), mockParser, parser.NewJSON(), mockMCFactory)
 
 			router := server.Handler()
 
@@ -188,3 +604,89 @@ func TestServer_RoutingOnly(t *testing.T) {
 		})
 	}
 }
+
+func TestServer_Health(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(config.Default(), parser.New(), parser.NewJSON(), rover.NewMissionControlFactory())
+	router := server.Handler()
+
+	get := func(endpoint string) *httptest.ResponseRecorder {
+		rcap := httptest.NewRecorder()
+		router.ServeHTTP(rcap, httptest.NewRequest(http.MethodGet, endpoint, nil))
+		return rcap
+	}
+
+	assert.Equal(t, http.StatusOK, get("/healthz").Code)
+	assert.Equal(t, http.StatusServiceUnavailable, get("/readyz").Code, "not ready before the server starts")
+
+	server.ready.Store(true)
+	assert.Equal(t, http.StatusOK, get("/readyz").Code)
+	assert.Equal(t, "ready\n", get("/readyz").Body.String())
+}
+
+func TestServer_GracefulShutdown(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(config.Default(), parser.New(), parser.NewJSON(), rover.NewMissionControlFactory())
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	baseURL := "http://" + ln.Addr().String()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve(ctx, ln)
+	}()
+
+	require.Eventually(t, server.ready.Load, time.Second, 5*time.Millisecond)
+
+	// a mission still streaming when shutdown starts is allowed to complete
+	resp, err := http.Post(baseURL+"/mcontrol/stream?delay=50ms", "text/plain", strings.NewReader("5 5\n1 2 N\nMMM"))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	cancel()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), "event: result")
+
+	require.NoError(t, <-served)
+	assert.False(t, server.ready.Load())
+
+	_, err = http.Get(baseURL + "/healthz")
+	assert.Error(t, err, "no longer accepting connections")
+}
+
+func TestServer_Metrics(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(config.Default(), parser.New(), parser.NewJSON(), rover.NewMissionControlFactory())
+	router := server.Handler()
+
+	for _, body := range []string{"5 5\n5 5 N\nMLM", "5 5\n1 2 Q\nM"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/mcontrol", strings.NewReader(body)))
+	}
+
+	rcap := httptest.NewRecorder()
+	router.ServeHTTP(rcap, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rcap.Code)
+	assert.Contains(t, rcap.Header().Get("Content-Type"), "version=0.0.4")
+
+	body := rcap.Body.String()
+	for _, want := range []string{
+		`mars_http_requests_total{code="200"} 1`,
+		`mars_http_requests_total{code="400"} 1`,
+		"mars_http_request_duration_seconds_count 2",
+		"mars_missions_total 1",
+		"mars_rovers_total 1",
+		"mars_commands_total 3",
+		"mars_moves_blocked_boundary_total 1",
+	} {
+		assert.Contains(t, body, want+"\n")
+	}
+}
@@ -0,0 +1,128 @@
+package webapi
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"mars/internal/lint"
+	"mars/internal/parser"
+	"mars/internal/render"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+const contentTypeJSON = "application/json"
+
+// sessionResponse is the JSON representation of a mission session
+type sessionResponse struct {
+	ID      string          `json:"id"`
+	Plateau plateauResponse `json:"plateau"`
+	Rovers  []render.Rover  `json:"rovers"`
+}
+
+type plateauResponse struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type errorResponse struct {
+	Error       string               `json:"error"`
+	Diagnostics []diagnosticResponse `json:"diagnostics,omitempty"`
+}
+
+// validateResponse is the JSON representation of a validation report
+type validateResponse struct {
+	Valid    bool           `json:"valid"`
+	Errors   int            `json:"errors"`
+	Warnings int            `json:"warnings"`
+	Infos    int            `json:"infos"`
+	Findings []lint.Finding `json:"findings"`
+}
+
+type diagnosticResponse struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Text    string `json:"text"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+// newSessionResponse maps a mission session to its JSON representation
+func newSessionResponse(sess *session) sessionResponse {
+	return sessionResponse{
+		ID: sess.id,
+		Plateau: plateauResponse{
+			X: sess.plateau.MaxX(),
+			Y: sess.plateau.MaxY(),
+		},
+		Rovers: render.NewRovers(sess.mc.Rovers()),
+	}
+}
+
+// newValidateResponse maps a validation report to its JSON representation
+func newValidateResponse(report *lint.Report) validateResponse {
+	findings := report.Findings
+	if findings == nil {
+		findings = []lint.Finding{}
+	}
+
+	return validateResponse{
+		Valid:    report.Valid(),
+		Errors:   report.Count(lint.SeverityError),
+		Warnings: report.Count(lint.SeverityWarning),
+		Infos:    report.Count(lint.SeverityInfo),
+		Findings: findings,
+	}
+}
+
+// newErrorResponse maps a mission error to its JSON representation, listing the parse diagnostics it holds if any
+func newErrorResponse(message string, err error) errorResponse {
+	resp := errorResponse{Error: message}
+
+	var diags parser.Diagnostics
+	if errors.As(err, &diags) {
+		for _, d := range diags {
+			resp.Diagnostics = append(resp.Diagnostics, diagnosticResponse{
+				Line:    d.Line,
+				Column:  d.Column,
+				Text:    d.Text,
+				Message: d.Err.Error(),
+				Hint:    d.Hint,
+			})
+		}
+	}
+
+	return resp
+}
+
+// writeJSON encodes v as the response body with the given status code
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("ERROR: encoding JSON response: %v", err)
+	}
+}
+
+// isJSON reports whether the given Content-Type header value is application/json
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == contentTypeJSON
+}
+
+// wantsJSON reports whether the client asked for a JSON response. Without an explicit Accept header the response mirrors the request Content-Type
+func wantsJSON(r *http.Request) bool {
+	accept := r.Header.Get("Accept")
+	if accept == "" || accept == "*/*" {
+		return isJSON(r.Header.Get("Content-Type"))
+	}
+
+	for _, part := range strings.Split(accept, ",") {
+		if isJSON(strings.TrimSpace(part)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,357 @@
+package webapi
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mars/internal/app"
+	"mars/internal/config"
+	"mars/internal/parser"
+	"mars/internal/render"
+	"mars/internal/rover"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const maxSessions = 1024
+
+var (
+	ErrSessionNotFound = errors.New("no mission with that id")
+	ErrSessionLimit    = errors.New("too many missions in progress, delete one first")
+)
+
+// session is a mission kept between requests so rovers can be deployed and driven one request at a time
+type session struct {
+	id       string
+	cfg      *config.Config // config the session was created with, query overrides included
+	plateau  *rover.Plateau
+	mc       *rover.MissionControl
+	lastUsed time.Time // last time a request got the session, guarded by the store
+
+	mu       sync.Mutex
+	commands int // commands given to the rovers of the session so far
+}
+
+// spend reserves the commands given to a rover of the session, failing once they would take the session over the total commands limit of its config.
+// Commands a deploy or drive never ran are given back with refund
+func (s *session) spend(commands string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if limit := s.cfg.Limits.TotalCommands; limit > 0 && s.commands+len(commands) > limit {
+		return fmt.Errorf("%w: at most %d", parser.ErrParseTooManyCommands, limit)
+	}
+	s.commands += len(commands)
+	return nil
+}
+
+// refund gives back the commands reserved by spend when the deploy or drive failed before its rover ran any of them, which MissionControl reports with a zero result
+func (s *session) refund(result rover.RoverResult, commands string, err error) {
+	if err == nil || result.ID != 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.commands -= len(commands)
+}
+
+// sessionStore holds the mission sessions by id. The store only guards the map, every MissionControl serialises its own calls.
+// Sessions no request has used for the timeout are gone: they are deleted once asked for or when a new session is added, a zero timeout keeps them until they are deleted
+type sessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]*session
+	timeout  time.Duration
+	now      func() time.Time
+}
+
+func newSessionStore(timeout time.Duration) *sessionStore {
+	return &sessionStore{
+		sessions: make(map[string]*session),
+		timeout:  timeout,
+		now:      time.Now,
+	}
+}
+
+// add stores a new session for the mission control under a random id
+func (st *sessionStore) add(cfg *config.Config, plateau *rover.Plateau, mc *rover.MissionControl) (*session, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.expire()
+	if len(st.sessions) >= maxSessions {
+		return nil, ErrSessionLimit
+	}
+
+	id, err := newSessionID()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &session{id: id, cfg: cfg, plateau: plateau, mc: mc, lastUsed: st.now()}
+	st.sessions[id] = s
+	return s, nil
+}
+
+// get returns the session with the given id, which counts as using it
+func (st *sessionStore) get(id string) (*session, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	s, ok := st.sessions[id]
+	if ok && st.idle(s) {
+		delete(st.sessions, id)
+		ok = false
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrSessionNotFound, id)
+	}
+
+	s.lastUsed = st.now()
+	return s, nil
+}
+
+// expire deletes every session no request has used for the timeout
+func (st *sessionStore) expire() {
+	for id, s := range st.sessions {
+		if st.idle(s) {
+			delete(st.sessions, id)
+		}
+	}
+}
+
+// idle reports whether no request has used the session for the timeout
+func (st *sessionStore) idle(s *session) bool {
+	return st.timeout > 0 && st.now().Sub(s.lastUsed) > st.timeout
+}
+
+// remove deletes the session with the given id
+func (st *sessionStore) remove(id string) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	s, ok := st.sessions[id]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrSessionNotFound, id)
+	}
+	delete(st.sessions, id)
+
+	if st.idle(s) {
+		return fmt.Errorf("%w: %q", ErrSessionNotFound, id)
+	}
+	return nil
+}
+
+// newSessionID returns a random 128-bit id in hex
+func newSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating mission id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// handleCreateSession creates a mission session from a plateau document. The "boundary", "collision" and "trace" query parameters set the policies of the mission
+func (s *Server) handleCreateSession(w http.ResponseWriter, r *http.Request) {
+	body, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+
+	cfg, err := s.missionConfig(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("Bad request: %v", err)})
+		return
+	}
+
+	plateau, err := s.jsonParser.ParsePlateau(body, cfg)
+	if err != nil {
+		writeSessionError(w, fmt.Errorf("%w: %w", app.ErrAppParsing, err))
+		return
+	}
+
+	mc, err := s.sessionMissionControl(plateau, cfg)
+	if err != nil {
+		writeSessionError(w, err)
+		return
+	}
+
+	sess, err := s.sessions.add(cfg, plateau, mc)
+	if err != nil {
+		writeSessionError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/missions/"+sess.id)
+	writeJSON(w, http.StatusCreated, newSessionResponse(sess))
+}
+
+// handleGetSession returns the plateau of a mission session and the current state of its rovers
+func (s *Server) handleGetSession(w http.ResponseWriter, r *http.Request) {
+	sess, err := s.sessions.get(r.PathValue("id"))
+	if err != nil {
+		writeSessionError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newSessionResponse(sess))
+}
+
+// handleDeleteSession ends a mission session
+func (s *Server) handleDeleteSession(w http.ResponseWriter, r *http.Request) {
+	if err := s.sessions.remove(r.PathValue("id")); err != nil {
+		writeSessionError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleListRovers returns the current state of every rover of a mission session
+func (s *Server) handleListRovers(w http.ResponseWriter, r *http.Request) {
+	sess, err := s.sessions.get(r.PathValue("id"))
+	if err != nil {
+		writeSessionError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, render.NewRovers(sess.mc.Rovers()))
+}
+
+// handleDeployRover deploys a rover described by a rover document on the plateau of a mission session, processing its commands if it has any
+func (s *Server) handleDeployRover(w http.ResponseWriter, r *http.Request) {
+	sess, err := s.sessions.get(r.PathValue("id"))
+	if err != nil {
+		writeSessionError(w, err)
+		return
+	}
+
+	body, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+
+	instruction, err := s.jsonParser.ParseRover(body, sess.plateau, sess.cfg)
+	if err != nil {
+		writeSessionError(w, fmt.Errorf("%w: %w", app.ErrAppParsing, err))
+		return
+	}
+
+	if err := sess.spend(instruction.Commands); err != nil {
+		writeSessionError(w, err)
+		return
+	}
+
+	result, err := sess.mc.Deploy(r.Context(), instruction)
+	sess.refund(result, instruction.Commands, err)
+	if err != nil {
+		writeSessionError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/missions/%s/rovers/%s", sess.id, result.Label()))
+	writeJSON(w, http.StatusCreated, render.NewRover(result))
+}
+
+// handleDriveRover sends a batch of commands to a rover of a mission session, known by its name or deployment number
+func (s *Server) handleDriveRover(w http.ResponseWriter, r *http.Request) {
+	sess, err := s.sessions.get(r.PathValue("id"))
+	if err != nil {
+		writeSessionError(w, err)
+		return
+	}
+
+	body, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+
+	commands, err := s.jsonParser.ParseCommands(body, sess.cfg)
+	if err != nil {
+		writeSessionError(w, fmt.Errorf("%w: %w", app.ErrAppParsing, err))
+		return
+	}
+
+	if err := sess.spend(commands); err != nil {
+		writeSessionError(w, err)
+		return
+	}
+
+	result, err := sess.mc.Drive(r.Context(), r.PathValue("rover"), commands)
+	sess.refund(result, commands, err)
+	if err != nil {
+		writeSessionError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, render.NewRover(result))
+}
+
+// sessionMissionControl creates the MissionControl of a new session, deploying no more rovers than the limit of a mission. Rovers of a session always run one after another, the execution mode is ignored
+func (s *Server) sessionMissionControl(plateau *rover.Plateau, cfg *config.Config) (*rover.MissionControl, error) {
+	opts, err := app.MissionOptions(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", app.ErrAppCreatingMC, err)
+	}
+	opts = append(opts, rover.WithRoverLimit(cfg.Limits.Rovers))
+
+	mc, err := s.factory.Create(plateau, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", app.ErrAppCreatingMC, err)
+	}
+	return mc, nil
+}
+
+// readBody reads the size limited request body, writing the error response and returning false should it fail
+func readBody(w http.ResponseWriter, r *http.Request) (string, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestSize)
+	defer r.Body.Close()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeSessionError(w, fmt.Errorf("%w: %w", app.ErrAppInput, err))
+		return "", false
+	}
+	return string(body), true
+}
+
+// writeSessionError maps a session error to its HTTP status code and writes it as JSON
+func writeSessionError(w http.ResponseWriter, err error) {
+	status, message := sessionErrorStatus(err)
+	if status == http.StatusInternalServerError {
+		log.Printf("ERROR: mission session failed: %v", err)
+	}
+
+	writeJSON(w, status, newErrorResponse(message, err))
+}
+
+// sessionErrorStatus maps a session error to the HTTP status code and message returned to the client, falling back to errorStatus for mission errors
+func sessionErrorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, ErrSessionNotFound), errors.Is(err, rover.ErrRoverNotFound):
+		return http.StatusNotFound, fmt.Sprintf("Not found: %v", err)
+
+	case errors.Is(err, rover.ErrRoverNameTaken), errors.Is(err, rover.ErrRoverNotOperational):
+		return http.StatusConflict, fmt.Sprintf("Conflict: %v", err)
+
+	case errors.Is(err, ErrSessionLimit):
+		return http.StatusServiceUnavailable, fmt.Sprintf("Unavailable: %v", err)
+
+	case errors.Is(err, rover.ErrMissionCancelled):
+		return http.StatusServiceUnavailable, fmt.Sprintf("Mission cancelled: %v", err)
+
+	case errors.Is(err, rover.ErrRoverLimit):
+		return http.StatusUnprocessableEntity, fmt.Sprintf("Mission too large: %v", err)
+
+	case errors.Is(err, rover.ErrRoverCreating), errors.Is(err, rover.ErrRoverInstructions):
+		return http.StatusUnprocessableEntity, fmt.Sprintf("Mission failed: %v", err)
+
+	default:
+		return errorStatus(err)
+	}
+}
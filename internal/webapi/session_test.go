@@ -0,0 +1,408 @@
+package webapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"mars/internal/config"
+	"mars/internal/parser"
+	"mars/internal/rover"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessions(t *testing.T) {
+	t.Parallel()
+
+	router := newTestSessionRouter()
+
+	rcap := doRequest(router, http.MethodPost, "/missions?boundary=lost", `{"plateau": {"x": 5, "y": 5}, "obstacles": [{"kind": "rock", "x": 2, "y": 3}]}`)
+	require.Equal(t, http.StatusCreated, rcap.Code)
+
+	var created sessionResponse
+	require.NoError(t, json.Unmarshal(rcap.Body.Bytes(), &created))
+	assert.Len(t, created.ID, 32)
+	assert.Equal(t, "/missions/"+created.ID, rcap.Header().Get("Location"))
+	assert.Equal(t, plateauResponse{X: 5, Y: 5}, created.Plateau)
+	assert.Empty(t, created.Rovers)
+
+	base := "/missions/" + created.ID
+
+	testSteps := []struct {
+		method         string
+		path           string
+		body           string
+		wantStatusCode int
+		wantBody       string
+	}{
+		{
+			method:         http.MethodPost,
+			path:           base + "/rovers",
+			body:           `{"x": 1, "y": 2, "heading": "N"}`,
+			wantStatusCode: http.StatusCreated,
+			wantBody:       `{"id":1,"x":1,"y":2,"heading":"N","status":"operational","start":{"x":1,"y":2,"heading":"N"},"ignoredMoves":[]}`,
+		},
+		{
+			method:         http.MethodPost,
+			path:           base + "/rovers",
+			body:           `{"name": "alpha", "x": 3, "y": 3, "heading": "W", "commands": "m"}`,
+			wantStatusCode: http.StatusCreated,
+			wantBody:       `{"id":2,"name":"alpha","x":3,"y":3,"heading":"W","status":"operational","start":{"x":3,"y":3,"heading":"W"},"ignoredMoves":[{"step":1,"x":2,"y":3,"heading":"W","reason":"path is blocked by an obstacle"}]}`,
+		},
+		{
+			method:         http.MethodPost,
+			path:           base + "/rovers",
+			body:           `{"name": "alpha", "x": 4, "y": 4, "heading": "N"}`,
+			wantStatusCode: http.StatusConflict,
+			wantBody:       `{"error":"Conflict: a rover with that name or number is already deployed: \"alpha\""}`,
+		},
+		{
+			method:         http.MethodPost,
+			path:           base + "/rovers/1/commands",
+			body:           `{"commands": "MM"}`,
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"id":1,"x":1,"y":4,"heading":"N","status":"operational","start":{"x":1,"y":2,"heading":"N"},"ignoredMoves":[]}`,
+		},
+		{
+			method:         http.MethodPost,
+			path:           base + "/rovers/1/commands",
+			body:           `{"commands": "MM"}`,
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"id":1,"x":1,"y":5,"heading":"N","status":"lost","start":{"x":1,"y":4,"heading":"N"},"ignoredMoves":[]}`,
+		},
+		{
+			method:         http.MethodPost,
+			path:           base + "/rovers/1/commands",
+			body:           `{"commands": "R"}`,
+			wantStatusCode: http.StatusConflict,
+			wantBody:       `{"error":"Conflict: rover can no longer take commands: rover 1 is lost"}`,
+		},
+		{
+			method:         http.MethodGet,
+			path:           base + "/rovers",
+			wantStatusCode: http.StatusOK,
+			wantBody:       `[{"id":1,"x":1,"y":5,"heading":"N","status":"lost","start":{"x":1,"y":2,"heading":"N"},"ignoredMoves":[]},{"id":2,"name":"alpha","x":3,"y":3,"heading":"W","status":"operational","start":{"x":3,"y":3,"heading":"W"},"ignoredMoves":[]}]`,
+		},
+		{
+			method:         http.MethodDelete,
+			path:           base,
+			wantStatusCode: http.StatusNoContent,
+		},
+		{
+			method:         http.MethodGet,
+			path:           base,
+			wantStatusCode: http.StatusNotFound,
+			wantBody:       `{"error":"Not found: no mission with that id: \"` + created.ID + `\""}`,
+		},
+	}
+
+	for _, step := range testSteps {
+		rcap := doRequest(router, step.method, step.path, step.body)
+
+		assert.Equal(t, step.wantStatusCode, rcap.Code, "%s %s", step.method, step.path)
+		if step.wantBody != "" {
+			assert.JSONEq(t, step.wantBody, rcap.Body.String(), "%s %s", step.method, step.path)
+		}
+	}
+}
+
+func TestSessions_Errors(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		method         string
+		path           string
+		body           string
+		wantStatusCode int
+		wantBody       string
+	}{
+		"err - invalid plateau": {
+			method:         http.MethodPost,
+			path:           "/missions",
+			body:           `{"obstacles": []}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error":"Bad request: error parsing input: mission must declare plateau x and y"}`,
+		},
+		"err - unknown policy": {
+			method:         http.MethodPost,
+			path:           "/missions?collision=bounce",
+			body:           `{"plateau": {"x": 5, "y": 5}}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error":"Bad request: collision policy must be one of skip, halt, abort, push, swap: (got \"bounce\")"}`,
+		},
+		"err - unknown mission": {
+			method:         http.MethodPost,
+			path:           "/missions/nope/rovers",
+			body:           `{"x": 1, "y": 2, "heading": "N"}`,
+			wantStatusCode: http.StatusNotFound,
+			wantBody:       `{"error":"Not found: no mission with that id: \"nope\""}`,
+		},
+		"err - unknown rover": {
+			method:         http.MethodPost,
+			path:           "{base}/rovers/beta/commands",
+			body:           `{"commands": "M"}`,
+			wantStatusCode: http.StatusNotFound,
+			wantBody:       `{"error":"Not found: no rover deployed with that name or number: \"beta\""}`,
+		},
+		"err - rover out of bounds": {
+			method:         http.MethodPost,
+			path:           "{base}/rovers",
+			body:           `{"x": 9, "y": 2, "heading": "N"}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error":"Bad request: error parsing input: position must be more than 0 and within boundaries"}`,
+		},
+		"err - rover on an occupied cell": {
+			method:         http.MethodPost,
+			path:           "{base}/rovers",
+			body:           `{"x": 1, "y": 1, "heading": "N"}`,
+			wantStatusCode: http.StatusUnprocessableEntity,
+			wantBody:       `{"error":"Mission failed: rover error executing instruction 2: new rover with id 2 cannot be placed at (1 1 N): path is blocked by another rover"}`,
+		},
+		"err - rover name is a number": {
+			method:         http.MethodPost,
+			path:           "{base}/rovers",
+			body:           `{"name": "3", "x": 2, "y": 2, "heading": "N"}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error":"Bad request: error parsing input: rover name must not be a number, numbers are kept for the rovers without a name: given \"3\""}`,
+		},
+		"err - invalid commands": {
+			method:         http.MethodPost,
+			path:           "{base}/rovers/1/commands",
+			body:           `{"commands": "MX"}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error":"Bad request: error parsing input: invalid command character given, must be L, R, M: given 'X'"}`,
+		},
+		"err - unknown field": {
+			method:         http.MethodPost,
+			path:           "{base}/rovers/1/commands",
+			body:           `{"cmds": "M"}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error":"Bad request: error parsing input: invalid JSON mission document: json: unknown field \"cmds\""}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			router := newTestSessionRouter()
+
+			// every case starts with a mission holding a single rover at 1 1 N
+			rcap := doRequest(router, http.MethodPost, "/missions", `{"plateau": {"x": 5, "y": 5}}`)
+			require.Equal(t, http.StatusCreated, rcap.Code)
+			base := rcap.Header().Get("Location")
+
+			rcap = doRequest(router, http.MethodPost, base+"/rovers", `{"x": 1, "y": 1, "heading": "N"}`)
+			require.Equal(t, http.StatusCreated, rcap.Code)
+
+			rcap = doRequest(router, tc.method, strings.ReplaceAll(tc.path, "{base}", base), tc.body)
+
+			assert.Equal(t, tc.wantStatusCode, rcap.Code)
+			assert.JSONEq(t, tc.wantBody, rcap.Body.String())
+		})
+	}
+}
+
+func TestSessions_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	router := newTestSessionRouter()
+
+	rcap := doRequest(router, http.MethodPost, "/missions?boundary=wrap", `{"plateau": {"x": 5, "y": 5}}`)
+	require.Equal(t, http.StatusCreated, rcap.Code)
+	base := rcap.Header().Get("Location")
+
+	rcap = doRequest(router, http.MethodPost, base+"/rovers", `{"name": "alpha", "x": 0, "y": 0, "heading": "E"}`)
+	require.Equal(t, http.StatusCreated, rcap.Code)
+
+	// every batch takes the rover round the plateau, ending where it started
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rcap := doRequest(router, http.MethodPost, base+"/rovers/alpha/commands", `{"commands": "MMMMMM"}`)
+			assert.Equal(t, http.StatusOK, rcap.Code)
+			doRequest(router, http.MethodGet, base, "")
+		}()
+	}
+	wg.Wait()
+
+	rcap = doRequest(router, http.MethodGet, base+"/rovers", "")
+	assert.JSONEq(t, `[{"id":1,"name":"alpha","x":0,"y":0,"heading":"E","status":"operational","start":{"x":0,"y":0,"heading":"E"},"ignoredMoves":[]}]`, rcap.Body.String())
+}
+
+func TestSessions_RoverLimit(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.Default()
+	cfg.Limits.Rovers = 3
+	router := NewServer(cfg, parser.New(), parser.NewJSON(), rover.NewMissionControlFactory()).Handler()
+
+	rcap := doRequest(router, http.MethodPost, "/missions", `{"plateau": {"x": 5, "y": 5}}`)
+	require.Equal(t, http.StatusCreated, rcap.Code)
+	base := rcap.Header().Get("Location")
+
+	// rovers deployed at the same time on distinct cells cannot get past the limit together
+	var mu sync.Mutex
+	codes := map[int]int{}
+	var wg sync.WaitGroup
+	for i := range 12 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rcap := doRequest(router, http.MethodPost, base+"/rovers", fmt.Sprintf(`{"x": %d, "y": %d, "heading": "N"}`, i%6, i/6))
+			mu.Lock()
+			codes[rcap.Code]++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, map[int]int{http.StatusCreated: 3, http.StatusUnprocessableEntity: 9}, codes)
+
+	rcap = doRequest(router, http.MethodPost, base+"/rovers", `{"x": 5, "y": 5, "heading": "N"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rcap.Code)
+	assert.JSONEq(t, `{"error":"Mission too large: too many rovers deployed: at most 3"}`, rcap.Body.String())
+}
+
+func TestSessions_CommandLimit(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.Default()
+	cfg.Limits.TotalCommands = 5
+	srv := NewServer(cfg, parser.New(), parser.NewJSON(), rover.NewMissionControlFactory())
+	router := srv.Handler()
+
+	rcap := doRequest(router, http.MethodPost, "/missions?collision=push", `{"plateau": {"x": 5, "y": 5}}`)
+	require.Equal(t, http.StatusCreated, rcap.Code)
+	base := rcap.Header().Get("Location")
+
+	// the session keeps the config it was created with
+	sess, err := srv.sessions.get(strings.TrimPrefix(base, "/missions/"))
+	require.NoError(t, err)
+	assert.Equal(t, rover.CollisionPush, sess.cfg.CollisionPolicy)
+
+	rcap = doRequest(router, http.MethodPost, base+"/rovers", `{"x": 1, "y": 1, "heading": "N", "commands": "MM"}`)
+	require.Equal(t, http.StatusCreated, rcap.Code)
+
+	rcap = doRequest(router, http.MethodPost, base+"/rovers/1/commands", `{"commands": "LRL"}`)
+	require.Equal(t, http.StatusOK, rcap.Code)
+
+	// every drive counts towards the total of the session
+	rcap = doRequest(router, http.MethodPost, base+"/rovers/1/commands", `{"commands": "R"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rcap.Code)
+	assert.JSONEq(t, `{"error":"Mission too large: mission exceeds a resource limit: too many commands in total: at most 5"}`, rcap.Body.String())
+
+	rcap = doRequest(router, http.MethodPost, base+"/rovers", `{"x": 3, "y": 3, "heading": "N", "commands": "M"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rcap.Code)
+
+	// a rover without commands can still be deployed
+	rcap = doRequest(router, http.MethodPost, base+"/rovers", `{"x": 3, "y": 3, "heading": "N"}`)
+	assert.Equal(t, http.StatusCreated, rcap.Code)
+}
+
+func TestSessions_CommandLimitRefund(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.Default()
+	cfg.Limits.TotalCommands = 4
+	srv := NewServer(cfg, parser.New(), parser.NewJSON(), rover.NewMissionControlFactory())
+	router := srv.Handler()
+
+	rcap := doRequest(router, http.MethodPost, "/missions", `{"plateau": {"x": 5, "y": 5}}`)
+	require.Equal(t, http.StatusCreated, rcap.Code)
+	base := rcap.Header().Get("Location")
+
+	sess, err := srv.sessions.get(strings.TrimPrefix(base, "/missions/"))
+	require.NoError(t, err)
+
+	rcap = doRequest(router, http.MethodPost, base+"/rovers", `{"x": 1, "y": 1, "heading": "N", "commands": "M"}`)
+	require.Equal(t, http.StatusCreated, rcap.Code)
+
+	// commands given to a rover that is not there, or to a rover that cannot be deployed, are given back
+	rcap = doRequest(router, http.MethodPost, base+"/rovers/ghost/commands", `{"commands": "LRL"}`)
+	require.Equal(t, http.StatusNotFound, rcap.Code)
+
+	rcap = doRequest(router, http.MethodPost, base+"/rovers", `{"x": 1, "y": 2, "heading": "N", "commands": "LRL"}`)
+	require.Equal(t, http.StatusUnprocessableEntity, rcap.Code)
+
+	sess.mu.Lock()
+	assert.Equal(t, 1, sess.commands)
+	sess.mu.Unlock()
+
+	rcap = doRequest(router, http.MethodPost, base+"/rovers/1/commands", `{"commands": "LRL"}`)
+	assert.Equal(t, http.StatusOK, rcap.Code)
+}
+
+func TestSessionStore_Timeout(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	st := newSessionStore(time.Minute)
+	st.now = func() time.Time { return now }
+
+	idle, err := st.add(config.Default(), nil, nil)
+	require.NoError(t, err)
+	used, err := st.add(config.Default(), nil, nil)
+	require.NoError(t, err)
+
+	// a request keeps the session alive
+	now = now.Add(40 * time.Second)
+	_, err = st.get(used.id)
+	require.NoError(t, err)
+
+	now = now.Add(40 * time.Second)
+	_, err = st.get(idle.id)
+	require.ErrorIs(t, err, ErrSessionNotFound)
+	_, err = st.get(used.id)
+	require.NoError(t, err)
+
+	// abandoned sessions make room for new ones
+	for len(st.sessions) < maxSessions {
+		_, err := st.add(config.Default(), nil, nil)
+		require.NoError(t, err)
+	}
+	_, err = st.add(config.Default(), nil, nil)
+	require.ErrorIs(t, err, ErrSessionLimit)
+
+	now = now.Add(2 * time.Minute)
+	_, err = st.add(config.Default(), nil, nil)
+	require.NoError(t, err)
+	assert.Len(t, st.sessions, 1)
+	require.ErrorIs(t, st.remove(used.id), ErrSessionNotFound)
+}
+
+func TestSessionStore_NoTimeout(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	st := newSessionStore(0)
+	st.now = func() time.Time { return now }
+
+	s, err := st.add(config.Default(), nil, nil)
+	require.NoError(t, err)
+
+	now = now.Add(24 * time.Hour)
+	_, err = st.get(s.id)
+	require.NoError(t, err)
+	require.NoError(t, st.remove(s.id))
+}
+
+func newTestSessionRouter() http.Handler {
+	server := NewServer(config.Default(), parser.New(), parser.NewJSON(), rover.NewMissionControlFactory())
+	return server.Handler()
+}
+
+func doRequest(router http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", contentTypeJSON)
+	rcap := httptest.NewRecorder()
+
+	router.ServeHTTP(rcap, req)
+	return rcap
+}
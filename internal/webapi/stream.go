@@ -0,0 +1,219 @@
+package webapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mars/internal/app"
+	"mars/internal/render"
+	"mars/internal/rover"
+	"net/http"
+	"time"
+)
+
+const (
+	contentTypeEventStream = "text/event-stream"
+	maxStreamDelay         = 5 * time.Second
+)
+
+// streamEventResponse is the JSON data of a rover event sent on the stream. Fields that do not apply to the event are left out
+type streamEventResponse struct {
+	ID       int              `json:"id"`
+	Name     string           `json:"name,omitempty"`
+	Step     int              `json:"step,omitempty"`
+	Command  string           `json:"command,omitempty"`
+	From     *render.Position `json:"from,omitempty"`
+	To       *render.Position `json:"to,omitempty"`
+	Position *render.Position `json:"position,omitempty"`
+	Outcome  string           `json:"outcome,omitempty"`
+	Reason   string           `json:"reason,omitempty"`
+	Status   string           `json:"status,omitempty"`
+	Error    string           `json:"error,omitempty"`
+}
+
+// sseSink writes every rover event it receives as a Server-Sent Event, pausing after each rover step so clients can follow the mission as it runs
+type sseSink struct {
+	w       http.ResponseWriter
+	rc      *http.ResponseController
+	done    <-chan struct{}
+	delay   time.Duration
+	started bool // true once the stream headers have been written
+}
+
+// sinkFactory wraps a MissionControlFactory so every MissionControl it creates sends its events to the given sink
+type sinkFactory struct {
+	rover.MissionControlFactory
+	sink rover.EventSink
+}
+
+// Create returns a new MissionControl for the plateau sending its events to the sink
+func (f sinkFactory) Create(plateau *rover.Plateau, opts ...rover.Option) (*rover.MissionControl, error) {
+	return f.MissionControlFactory.Create(plateau, append(opts, rover.WithEventSink(f.sink))...)
+}
+
+// handleMissionStream runs a mission like handleMission but streams every rover event as it happens, followed by a "result" event holding the JSON mission result.
+// Errors found before the first event are returned as a regular error response, later ones as an "error" event. The "delay" query parameter overrides the configured pause after each rover step
+func (s *Server) handleMissionStream(w http.ResponseWriter, r *http.Request) {
+
+	// limit the size of what we accept
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestSize)
+	defer r.Body.Close()
+
+	// pick the parser matching the body format
+	p := s.parser
+	if isJSON(r.Header.Get("Content-Type")) {
+		p = s.jsonParser
+	}
+
+	jsonResponse := wantsJSON(r)
+
+	cfg, err := s.missionConfig(r)
+	if err == nil {
+		err = streamDelay(r, &cfg.StreamDelay)
+	}
+	if err != nil {
+		message := fmt.Sprintf("Bad request: %v", err)
+		if jsonResponse {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: message})
+			return
+		}
+		http.Error(w, message, http.StatusBadRequest)
+		return
+	}
+
+	// the write timeout would cut long streams short
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Printf("ERROR: clearing stream write deadline: %v", err)
+	}
+
+	sink := &sseSink{
+		w:     w,
+		rc:    rc,
+		done:  r.Context().Done(),
+		delay: cfg.StreamDelay,
+	}
+
+	application := app.NewApp(p, sinkFactory{MissionControlFactory: s.factory, sink: sink}, r.Body, io.Discard, cfg)
+
+	result, err := application.Execute(r.Context())
+	if err != nil {
+		log.Printf("ERROR: streamed mission failed: %v", err)
+
+		status, message := errorStatus(err)
+		if sink.started {
+			sink.send("error", newErrorResponse(message, err))
+			return
+		}
+
+		if jsonResponse {
+			writeJSON(w, status, newErrorResponse(message, err))
+			return
+		}
+		http.Error(w, message, status)
+		return
+	}
+
+	s.metrics.MissionExecuted()
+	sink.send("result", render.NewMission(result))
+}
+
+// streamDelay overrides the delay with the "delay" query parameter, if given, as a Go duration such as 250ms
+func streamDelay(r *http.Request, delay *time.Duration) error {
+	value := r.URL.Query().Get("delay")
+	if value == "" {
+		return nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 || d > maxStreamDelay {
+		return fmt.Errorf("%w: delay %q, must be a duration between 0s and %s", ErrInvalidQuery, value, maxStreamDelay)
+	}
+
+	*delay = d
+	return nil
+}
+
+func (s *sseSink) HandleEvent(e rover.Event) {
+	switch ev := e.(type) {
+	case rover.RoverPlaced:
+		s.send("placed", streamEventResponse{ID: ev.RoverID, Name: ev.RoverName, Position: positionRef(ev.Position)})
+
+	case rover.RoverMoved:
+		s.send("moved", streamEventResponse{ID: ev.RoverID, Name: ev.RoverName, Step: ev.Step, From: positionRef(ev.From), To: positionRef(ev.To), Outcome: ev.Outcome.String()})
+		s.pause()
+
+	case rover.RoverTurned:
+		s.send("turned", streamEventResponse{ID: ev.RoverID, Name: ev.RoverName, Step: ev.Step, Command: ev.Command.String(), From: positionRef(ev.From), To: positionRef(ev.To)})
+		s.pause()
+
+	case rover.MoveRejected:
+		s.send("blocked", streamEventResponse{ID: ev.RoverID, Name: ev.RoverName, Step: ev.Step, From: positionRef(ev.From), To: positionRef(ev.Target), Outcome: ev.Outcome.String(), Reason: ev.Reason.Error()})
+		s.pause()
+
+	case rover.RoverFinished:
+		resp := streamEventResponse{ID: ev.RoverID, Name: ev.RoverName, Position: positionRef(ev.Position), Status: ev.Status.String()}
+		if ev.Err != nil {
+			resp.Error = ev.Err.Error()
+		}
+		s.send("finished", resp)
+	}
+}
+
+// send writes a single event with v encoded as JSON data and flushes it to the client, writing the stream headers first if needed. Nothing is sent once the client has gone away
+func (s *sseSink) send(event string, v any) {
+	if s.closed() {
+		return
+	}
+
+	if !s.started {
+		s.w.Header().Set("Content-Type", contentTypeEventStream)
+		s.w.Header().Set("Cache-Control", "no-cache")
+		s.w.WriteHeader(http.StatusOK)
+		s.started = true
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("ERROR: encoding stream event: %v", err)
+		return
+	}
+
+	fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event, data)
+
+	if err := s.rc.Flush(); err != nil {
+		log.Printf("ERROR: flushing stream event: %v", err)
+	}
+}
+
+// pause waits for the configured delay, returning early if the client goes away
+func (s *sseSink) pause() {
+	if s.delay <= 0 || s.closed() {
+		return
+	}
+
+	timer := time.NewTimer(s.delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-s.done:
+	}
+}
+
+// closed reports whether the client has gone away
+func (s *sseSink) closed() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func positionRef(p rover.Position) *render.Position {
+	resp := render.NewPosition(p)
+	return &resp
+}
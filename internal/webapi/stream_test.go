@@ -0,0 +1,110 @@
+package webapi
+
+import (
+	"mars/internal/config"
+	"mars/internal/parser"
+	"mars/internal/rover"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleMissionStream(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		query           string
+		requestBody     string
+		contentType     string
+		wantStatusCode  int
+		wantContentType string
+		wantBody        string
+	}{
+		"ok - every event then the result": {
+			requestBody:     "5 5\n5 4 N\nRMLM",
+			wantStatusCode:  http.StatusOK,
+			wantContentType: contentTypeEventStream,
+			wantBody: "event: placed\ndata: {\"id\":1,\"position\":{\"x\":5,\"y\":4,\"heading\":\"N\"}}\n\n" +
+				"event: turned\ndata: {\"id\":1,\"step\":1,\"command\":\"R\",\"from\":{\"x\":5,\"y\":4,\"heading\":\"N\"},\"to\":{\"x\":5,\"y\":4,\"heading\":\"E\"}}\n\n" +
+				"event: blocked\ndata: {\"id\":1,\"step\":2,\"from\":{\"x\":5,\"y\":4,\"heading\":\"E\"},\"to\":{\"x\":6,\"y\":4,\"heading\":\"E\"},\"outcome\":\"blocked-by-boundary\",\"reason\":\"position must be more than 0 and within boundaries\"}\n\n" +
+				"event: turned\ndata: {\"id\":1,\"step\":3,\"command\":\"L\",\"from\":{\"x\":5,\"y\":4,\"heading\":\"E\"},\"to\":{\"x\":5,\"y\":4,\"heading\":\"N\"}}\n\n" +
+				"event: moved\ndata: {\"id\":1,\"step\":4,\"from\":{\"x\":5,\"y\":4,\"heading\":\"N\"},\"to\":{\"x\":5,\"y\":5,\"heading\":\"N\"},\"outcome\":\"moved\"}\n\n" +
+				"event: finished\ndata: {\"id\":1,\"position\":{\"x\":5,\"y\":5,\"heading\":\"N\"},\"status\":\"operational\"}\n\n" +
+				"event: result\ndata: {\"rovers\":[{\"id\":1,\"x\":5,\"y\":5,\"heading\":\"N\",\"status\":\"operational\",\"start\":{\"x\":5,\"y\":4,\"heading\":\"N\"},\"ignoredMoves\":[{\"step\":2,\"x\":6,\"y\":4,\"heading\":\"E\",\"reason\":\"position must be more than 0 and within boundaries\"}]}]}\n\n",
+		},
+		"ok - json body with named rover and delay": {
+			query:           "?delay=1ms",
+			requestBody:     `{"plateau": {"x": 5, "y": 5}, "rovers": [{"name": "alpha", "x": 1, "y": 2, "heading": "N", "commands": ""}]}`,
+			contentType:     "application/json",
+			wantStatusCode:  http.StatusOK,
+			wantContentType: contentTypeEventStream,
+			wantBody: "event: placed\ndata: {\"id\":1,\"name\":\"alpha\",\"position\":{\"x\":1,\"y\":2,\"heading\":\"N\"}}\n\n" +
+				"event: finished\ndata: {\"id\":1,\"name\":\"alpha\",\"position\":{\"x\":1,\"y\":2,\"heading\":\"N\"},\"status\":\"operational\"}\n\n" +
+				"event: result\ndata: {\"rovers\":[{\"id\":1,\"name\":\"alpha\",\"x\":1,\"y\":2,\"heading\":\"N\",\"status\":\"operational\",\"start\":{\"x\":1,\"y\":2,\"heading\":\"N\"},\"ignoredMoves\":[]}]}\n\n",
+		},
+		"err - mission failure after streaming started": {
+			query:           "?collision=abort",
+			requestBody:     "5 5\n1 2 N\n\n1 1 N\nM",
+			wantStatusCode:  http.StatusOK,
+			wantContentType: contentTypeEventStream,
+			wantBody: "event: placed\ndata: {\"id\":1,\"position\":{\"x\":1,\"y\":2,\"heading\":\"N\"}}\n\n" +
+				"event: finished\ndata: {\"id\":1,\"position\":{\"x\":1,\"y\":2,\"heading\":\"N\"},\"status\":\"operational\"}\n\n" +
+				"event: placed\ndata: {\"id\":2,\"position\":{\"x\":1,\"y\":1,\"heading\":\"N\"}}\n\n" +
+				"event: finished\ndata: {\"id\":2,\"position\":{\"x\":1,\"y\":1,\"heading\":\"N\"},\"status\":\"aborted\",\"error\":\"rover 2 blocked at step 1 moving to (1 2 N): path is blocked by another rover\"}\n\n" +
+				"event: error\ndata: {\"error\":\"Mission failed: error executing mission: rover error executing instruction 2: rover 2 blocked at step 1 moving to (1 2 N): path is blocked by another rover\"}\n\n",
+		},
+		"err - parse error before streaming": {
+			requestBody:     "5 5\n1 2 N\nMZ",
+			wantStatusCode:  http.StatusBadRequest,
+			wantContentType: "text/plain",
+			wantBody:        "Bad request: error parsing input: 3:2: invalid command character given, must be L, R, M: given 'Z'\n",
+		},
+		"err - invalid delay": {
+			query:           "?delay=1h",
+			requestBody:     "5 5\n1 2 N\nM",
+			wantStatusCode:  http.StatusBadRequest,
+			wantContentType: "text/plain",
+			wantBody:        "Bad request: invalid query parameter: delay \"1h\", must be a duration between 0s and 5s\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := NewServer(config.Default(), parser.New(), parser.NewJSON(), rover.NewMissionControlFactory())
+
+			req := httptest.NewRequest(http.MethodPost, "/mcontrol/stream"+tc.query, strings.NewReader(tc.requestBody))
+			if tc.contentType != "" {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+			rcap := httptest.NewRecorder()
+
+			server.Handler().ServeHTTP(rcap, req)
+
+			assert.Equal(t, tc.wantStatusCode, rcap.Code)
+			assert.Contains(t, rcap.Header().Get("Content-Type"), tc.wantContentType)
+			assert.Equal(t, tc.wantBody, rcap.Body.String())
+		})
+	}
+}
+
+func TestHandleMissionStream_Delay(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.Default()
+	cfg.StreamDelay = 20 * time.Millisecond
+	server := NewServer(cfg, parser.New(), parser.NewJSON(), rover.NewMissionControlFactory())
+
+	req := httptest.NewRequest(http.MethodPost, "/mcontrol/stream", strings.NewReader("5 5\n1 2 N\nLRM"))
+	rcap := httptest.NewRecorder()
+
+	start := time.Now()
+	server.Handler().ServeHTTP(rcap, req)
+
+	// one pause per command
+	assert.Equal(t, http.StatusOK, rcap.Code)
+	assert.GreaterOrEqual(t, time.Since(start), 3*cfg.StreamDelay)
+}